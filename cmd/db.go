@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/dbench/internal/build"
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+)
+
+type dbRestoreOptions struct {
+	*globalOptions
+
+	force bool
+}
+
+func newDBCommand(globalOpts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "db [COMMAND]",
+		GroupID:               "commands",
+		Short:                 "Back up and restore the local database",
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+	}
+
+	cmd.AddCommand(
+		newDBBackupCommand(globalOpts),
+		newDBRestoreCommand(globalOpts),
+	)
+
+	return cmd
+}
+
+// localDatabasePath returns the path of the local SQLite database. Backups of a shared PostgreSQL store are taken by
+// its operators, e.g. with pg_dump.
+func localDatabasePath(opts *globalOptions) (string, error) {
+	if opts.storeDSN != "" {
+		return "", fmt.Errorf("backup and restore are only supported for the local database, use pg_dump and pg_restore for a shared PostgreSQL store")
+	}
+	if opts.dataDir == "" {
+		return "", fmt.Errorf("path to data directory is empty")
+	}
+
+	return filepath.Join(opts.dataDir, build.AppName) + ".db", nil
+}
+
+// openLocalDatabase opens the SQLite database at the given path. The caller is responsible for closing it.
+func openLocalDatabase(ctx context.Context, path string) (*database.DB, error) {
+	db, err := database.New().Connect(ctx, "file:"+path)
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+
+	return db, nil
+}
+
+func newDBBackupCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:                   "backup FILE",
+		Short:                 "Write a consistent copy of the database to a file",
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbPath, err := localDatabasePath(opts)
+			if err != nil {
+				return err
+			}
+
+			target := args[0]
+			if _, err := os.Stat(target); err == nil {
+				return fmt.Errorf("%s already exists", target)
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Backup")
+
+			p.PrintInfo(" Backing up database ... ", printer.WithIndent())
+
+			db, err := openLocalDatabase(cmd.Context(), dbPath)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return err
+			}
+			defer db.Close()
+
+			if err := db.Backup(cmd.Context(), target); err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("back up database: %w", err)
+			}
+
+			p.PrintlnSuccess("")
+			p.Spacer(2)
+			p.PrintText(" Complete! Saved backup to ")
+			p.PrintlnHighlight(target)
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+}
+
+func newDBRestoreCommand(globalOpts *globalOptions) *cobra.Command {
+	opts := &dbRestoreOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "restore [OPTIONS] FILE",
+		Short:                 "Replace the database with a backup",
+		Long:                  dbRestoreLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dbPath, err := localDatabasePath(opts.globalOptions)
+			if err != nil {
+				return err
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Restore")
+
+			// The backup is validated and migrated on a copy, so that neither the backup nor the database are touched if
+			// anything goes wrong.
+			p.PrintInfo(" Validating backup ... ", printer.WithIndent())
+
+			restorePath := dbPath + ".restore"
+			backupInfo, err := prepareRestore(cmd.Context(), args[0], restorePath)
+			if err != nil {
+				_ = removeDatabaseFiles(restorePath)
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("validate backup: %w", err)
+			}
+
+			p.PrintlnSuccess(fmt.Sprintf("%d benchmark(s)", backupInfo.Benchmarks))
+
+			// Guard against replacing newer data and keep a copy of the current database
+			if _, err := os.Stat(dbPath); err == nil {
+				p.PrintInfo(" Backing up current database ... ", printer.WithIndent())
+
+				currentBackupPath, err := backupCurrentDatabase(cmd.Context(), dbPath, backupInfo, opts.force)
+				if err != nil {
+					_ = removeDatabaseFiles(restorePath)
+					p.PrintlnError(err.Error())
+					return err
+				}
+
+				p.PrintlnSuccess(currentBackupPath)
+			}
+
+			p.PrintInfo(" Restoring database ... ", printer.WithIndent())
+
+			// Stale WAL files of the current database must not be applied to the restored one
+			if err := removeDatabaseFiles(dbPath); err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("remove current database: %w", err)
+			}
+
+			if err := os.Rename(restorePath, dbPath); err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("move restored database into place: %w", err)
+			}
+
+			p.PrintlnSuccess("")
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.force, "force", "f", false, "Restore even if the current database holds newer benchmarks than the backup")
+
+	return cmd
+}
+
+// prepareRestore copies the backup to the given path, validates it and migrates it to the current schema.
+func prepareRestore(ctx context.Context, backupPath, restorePath string) (*database.Info, error) {
+	if err := removeDatabaseFiles(restorePath); err != nil {
+		return nil, err
+	}
+	if err := copyFile(backupPath, restorePath); err != nil {
+		return nil, fmt.Errorf("copy backup: %w", err)
+	}
+
+	db, err := openLocalDatabase(ctx, restorePath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	// Refuse backups of a newer version of dbench, their schema is unknown to us
+	if _, err := db.ShouldMigrate(ctx, build.Version); err != nil {
+		return nil, err
+	}
+
+	backupVersion, err := db.AppVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if isNewerVersion(backupVersion, build.Version) {
+		return nil, fmt.Errorf("backup was taken by dbench %s, which is newer than this version (%s)", backupVersion, build.Version)
+	}
+
+	if _, err := db.MigrateUp(ctx, build.Version); err != nil {
+		return nil, fmt.Errorf("migrate backup: %w", err)
+	}
+
+	return db.Info(ctx)
+}
+
+// backupCurrentDatabase backs up the current database before it's replaced by the restored one. Unless forced, it
+// refuses to continue if the current database holds benchmarks that are newer than the ones of the backup.
+func backupCurrentDatabase(ctx context.Context, dbPath string, backupInfo *database.Info, force bool) (string, error) {
+	db, err := openLocalDatabase(ctx, dbPath)
+	if err != nil {
+		return "", err
+	}
+	defer db.Close()
+
+	if !force {
+		if _, err := db.ShouldMigrate(ctx, build.Version); err != nil {
+			return "", err
+		}
+
+		current, err := db.Info(ctx)
+		if err != nil {
+			return "", fmt.Errorf("inspect current database: %w", err)
+		}
+
+		if current.LatestRecordedAt != nil &&
+			(backupInfo.LatestRecordedAt == nil || current.LatestRecordedAt.After(*backupInfo.LatestRecordedAt)) {
+			return "", fmt.Errorf("the current database holds benchmarks recorded after the latest one of the backup (%s); use --force to restore anyway", current.LatestRecordedAt.Local().Format(time.DateTime))
+		}
+	}
+
+	backupPath := fmt.Sprintf("%s.%s.bak", dbPath, time.Now().UTC().Format("20060102T150405Z"))
+	if err := db.Backup(ctx, backupPath); err != nil {
+		return "", fmt.Errorf("back up current database: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// isNewerVersion reports whether version a is newer than version b. Development builds and invalid versions are never
+// considered newer.
+func isNewerVersion(a, b string) bool {
+	if a == "" || a == "dev" || b == "dev" {
+		return false
+	}
+
+	versionA, err := semver.NewVersion(a)
+	if err != nil {
+		return false
+	}
+
+	versionB, err := semver.NewVersion(b)
+	if err != nil {
+		return false
+	}
+
+	return versionA.GreaterThan(versionB)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// removeDatabaseFiles removes a SQLite database file including its WAL and shared memory files.
+func removeDatabaseFiles(path string) error {
+	for _, file := range []string{path, path + "-wal", path + "-shm"} {
+		if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var dbRestoreLongDesc = `Replace the local database with a backup that was taken with 'dbench db backup'.
+
+The backup is validated before anything is replaced. Backups taken by a newer
+version of dbench are refused, older ones are migrated to the current schema.
+If the current database holds benchmarks that were recorded after the latest
+one of the backup, the restore is refused unless '--force' is given. Either way,
+the current database is backed up next to it before it's replaced.`
@@ -17,6 +17,7 @@ type doctorOptions struct {
 	*globalOptions
 
 	showSystemConfig bool
+	pgDataDir        string
 }
 
 func newDoctorCommand(globalsOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
@@ -30,7 +31,7 @@ func newDoctorCommand(globalsOpts *globalOptions, connectToDB dbConnector) *cobr
 		Short:   "Check if dbench is ready to run",
 		Long: `Check if dbench is ready to run.
 
-Enabling the --system flag, dbench will show you the exact system config that would be collected during a benchmark if you execute 'dbench run' with the '--collect-sysinfo' flag.`,
+Enabling the --sysinfo flag, dbench will show you the exact system config that would be collected during a benchmark if you execute 'dbench run' with the '--collect-sysinfo' flag. Pass the same '--pg-data-dir' as to 'dbench run' to also see the filesystem, mount options and disk type of the PostgreSQL data directory.`,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
@@ -53,6 +54,14 @@ Enabling the --system flag, dbench will show you the exact system config that wo
 
 			// Check dbench database
 			p.PrintlnSubTitle("Database")
+
+			store := "SQLite (local)"
+			if opts.storeDSN != "" {
+				store = "PostgreSQL (shared)"
+			}
+			p.PrintInfo(" Store ... ", printer.WithIndent())
+			p.PrintlnSuccess(store)
+
 			p.PrintInfo(" Connecting ... ", printer.WithIndent())
 			p.PrintlnSuccess(connDuration.String())
 
@@ -94,7 +103,7 @@ Enabling the --system flag, dbench will show you the exact system config that wo
 			}
 
 			p.PrintlnSubTitle("Host system")
-			systemConfig, errs := system.GetConfig()
+			systemConfig, errs := system.GetConfig(opts.pgDataDir)
 
 			// Pretty print errors
 			if len(errs) > 0 {
@@ -147,6 +156,39 @@ Enabling the --system flag, dbench will show you the exact system config that wo
 			p.PrintInfo("  Disk Total:", printer.WithIndent())
 			p.PrintlnInfo(text.HumanizeBytes(systemConfig.DiskSpaceTotal))
 
+			p.PrintInfo("  Disk Type:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.DiskType))
+
+			p.PrintInfo("  Kernel Version:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.KernelVersion))
+
+			p.PrintInfo("  CPU Governor:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.CPUGovernor))
+
+			p.PrintInfo("  NUMA Nodes:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.NumaNodes))
+
+			p.PrintInfo("  Transparent Huge Pages:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.TransparentHugePages))
+
+			p.PrintInfo("  Data Dir Filesystem:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.DataDirFilesystem))
+
+			p.PrintInfo("  Data Dir Mount Options:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.DataDirMountOptions))
+
+			p.PrintInfo("  Cgroup Version:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.CgroupVersion))
+
+			p.PrintInfo("  Cgroup CPU Quota:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.CgroupCPUQuota))
+
+			p.PrintInfo("  Cgroup Memory Limit:", printer.WithIndent())
+			p.PrintlnInfo(text.HumanizeBytes(systemConfig.CgroupMemoryLimit))
+
+			p.PrintInfo("  Cgroup IO Limits:", printer.WithIndent())
+			p.PrintlnInfo(text.ValueOrNA(systemConfig.CgroupIoLimits))
+
 			p.Spacer(1)
 
 			return nil
@@ -154,6 +196,7 @@ Enabling the --system flag, dbench will show you the exact system config that wo
 	}
 
 	cmd.Flags().BoolVar(&opts.showSystemConfig, "sysinfo", false, "Show detailed system information")
+	cmd.Flags().StringVar(&opts.pgDataDir, "pg-data-dir", "", "Data directory of the local PostgreSQL server; used to inspect its filesystem and disk")
 
 	cmd.Flags().SortFlags = false
 
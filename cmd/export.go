@@ -3,28 +3,36 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 
-	"github.com/nikoksr/dbench/ent"
-	"github.com/nikoksr/dbench/ent/benchmark"
 	"github.com/nikoksr/dbench/internal/archive"
 	"github.com/nikoksr/dbench/internal/build"
 	"github.com/nikoksr/dbench/internal/database"
 	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/models"
 	"github.com/nikoksr/dbench/internal/portability/converter"
 	"github.com/nikoksr/dbench/internal/portability/exporter"
+	"github.com/nikoksr/dbench/internal/portability/manifest"
 	"github.com/nikoksr/dbench/internal/ui/printer"
 )
 
 const (
-	csvFileFormat  = "csv"
-	jsonFileFormat = "json"
+	csvFileFormat     = "csv"
+	jsonFileFormat    = "json"
+	ndjsonFileFormat  = "ndjson"
+	parquetFileFormat = "parquet"
+	sqliteFileFormat  = "sqlite"
 )
 
+// exportPageSize is the number of benchmarks that are fetched and written at once while exporting a batch.
+const exportPageSize = 500
+
 type exportOptions struct {
 	*globalOptions
 
@@ -33,9 +41,14 @@ type exportOptions struct {
 	batchSize int
 	archive   bool
 	keep      bool
+	tags      []string
+	where     string
+	since     string
+	until     string
 
 	// Internal
 	targetDir string
+	filters   []database.QueryOption
 }
 
 func generateExportDirPath() string {
@@ -58,16 +71,22 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 	}
 
 	cmd := &cobra.Command{
-		Use:                   "export [OPTIONS]",
+		Use:                   "export [OPTIONS] [ID|BENCHMARK-GROUP...]",
 		Aliases:               []string{"e"},
 		GroupID:               "commands",
-		Short:                 "Export all data to a format of your choice",
+		Short:                 "Export benchmarks to a format of your choice",
+		Long:                  exportLongDesc,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
-		Args:                  cobra.NoArgs,
+		Args:                  cobra.ArbitraryArgs,
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			recordedBetween, err := recordedBetweenOption(opts.since, opts.until)
+			if err != nil {
+				return err
+			}
+
 			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
 			if err != nil {
 				return fmt.Errorf("connect to database: %w", err)
@@ -78,10 +97,40 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			p.PrintlnTitle("Export")
 			p.PrintlnSubTitle("Preparation")
 
-			// Check how many benchmarks are stored
+			opts.filters, err = filterOptions(opts.tags, opts.where)
+			if err != nil {
+				return err
+			}
+			if recordedBetween != nil {
+				opts.filters = append(opts.filters, recordedBetween)
+			}
+
+			if opts.format != sqliteFileFormat {
+				if _, _, err := newExportStream(opts.format, io.Discard); err != nil {
+					return err
+				}
+			}
+
+			// Narrow the export down to the given benchmarks and benchmark-groups
+			if len(args) > 0 {
+				p.PrintInfo(" Resolving benchmarks and benchmark-groups ... ", printer.WithIndent())
+
+				ids, groups := splitBenchmarkArgs(args)
+				groupIDs, err := db.ResolveGroupIDs(cmd.Context(), groups)
+				if err != nil {
+					p.PrintlnError(err.Error())
+					return fmt.Errorf("resolve benchmark-groups: %w", err)
+				}
+
+				opts.filters = append(opts.filters, database.WithSelection(ids, groupIDs))
+
+				p.PrintlnSuccess("")
+			}
+
+			// Check how many benchmarks match; the batches are split by this count
 			p.PrintInfo(" Checking database for benchmarks ... ", printer.WithIndent())
 
-			count, err := db.Count(cmd.Context())
+			count, err := db.Count(cmd.Context(), opts.filters...)
 			if err != nil {
 				p.PrintlnError(err.Error())
 				return fmt.Errorf("count benchmarks: %w", err)
@@ -93,7 +142,7 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 				return nil
 			}
 
-			p.PrintlnSuccess("")
+			p.PrintlnSuccess(fmt.Sprintf("%d found", count))
 
 			// Generate export directory
 			p.PrintInfo(" Creating export directory ... ", printer.WithIndent())
@@ -106,11 +155,9 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			p.PrintlnSuccess("")
 			p.Spacer(2)
 
-			// Sanitize batch size
 			p.PrintlnSubTitle("Exporting benchmarks")
 
-			// Sanitize batch size
-			opts.batchSize = sanitizeBatchSize(opts.batchSize)
+			opts.batchSize = exportBatchSize(opts.format, opts.batchSize, count)
 
 			// We export in batches of 5_000 by default
 			runs := int(count / uint64(opts.batchSize))
@@ -119,6 +166,9 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 				runs++
 			}
 
+			// The manifest lets imports verify the export and tells them which record layout it holds
+			exportManifest := manifest.New(build.Version, opts.format)
+
 			for i := 0; i < runs; i++ {
 				offset := i * opts.batchSize
 				limit := opts.batchSize
@@ -126,11 +176,23 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 					limit = remainder
 				}
 
-				if err := exportBenchmarksBatch(cmd.Context(), opts, p, db, offset, limit); err != nil {
+				file, err := exportBenchmarksBatch(cmd.Context(), opts, p, db, offset, limit)
+				if err != nil {
 					return err
 				}
+
+				exportManifest.Files = append(exportManifest.Files, file)
 			}
 
+			p.PrintInfo(" Writing manifest ... ", printer.WithIndent())
+
+			if err := exportManifest.Write(opts.targetDir); err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("write manifest: %w", err)
+			}
+
+			p.PrintlnSuccess("")
+
 			// Only print success message if we:
 			// - don't archive
 			// - archive and keep the export directory
@@ -187,13 +249,16 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 	// Flags
 	cmd.Flags().BoolVarP(&opts.archive, "archive", "a", false, "Archive the export directory")
 	cmd.Flags().BoolVarP(&opts.keep, "keep", "k", false, "Keep the export directory after archiving")
-	cmd.Flags().StringVarP(&opts.format, "format", "f", jsonFileFormat, "Format to export benchmarks to (json, csv)")
+	cmd.Flags().StringVarP(&opts.format, "format", "f", jsonFileFormat, "Format to export benchmarks to (json, ndjson, csv, parquet, sqlite)")
 	cmd.Flags().StringVarP(&opts.targetDir, "output-dir", "o", opts.targetDir, "Directory to export data to")
-	cmd.Flags().IntVarP(&opts.batchSize, "batch-size", "b", defaultBatchSize, "Number of benchmarks to export per file")
+	cmd.Flags().IntVarP(&opts.batchSize, "batch-size", "b", defaultBatchSize, "Number of benchmarks to export per file; doesn't apply to sqlite")
+	addFilterFlags(cmd, &opts.tags, &opts.where)
+	cmd.Flags().StringVar(&opts.since, "since", "", "Only export benchmarks recorded at or after the given date or time, e.g. 2024-01-31 or 2024-01-31T15:04:05")
+	cmd.Flags().StringVar(&opts.until, "until", "", "Only export benchmarks recorded at or before the given date or time; a date includes the whole day")
 
 	_ = cmd.MarkFlagDirname("output-dir")
 	_ = cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		return []string{jsonFileFormat, csvFileFormat}, cobra.ShellCompDirectiveNoFileComp
+		return []string{jsonFileFormat, ndjsonFileFormat, csvFileFormat, parquetFileFormat, sqliteFileFormat}, cobra.ShellCompDirectiveNoFileComp
 	})
 
 	cmd.Flags().SortFlags = false
@@ -201,78 +266,217 @@ func newExportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 	return cmd
 }
 
-func exportBenchmarksBatch(ctx context.Context, opts *exportOptions, p *printer.Printer, db database.Store, offset, limit int) error {
-	// Query benchmarks
-	benchmarkRange := fmt.Sprintf("(%d-%d)", offset+1, offset+limit)
-	p.PrintInfo(" Fetching ... ", printer.WithIndent())
-
-	// The order function we're using here is the reverse of our default order as seen in [internal/database/database.go].
-	// When we're, for example, running `dbench ls`, we want to see the latest benchmarks first. When we're exporting,
-	// we want to export the oldest benchmarks first, so that when they're imported again, they're in the correct order.
-	benchmarks, err := db.Fetch(ctx,
-		database.WithOrderBy(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
-			return query.Order(
-				ent.Asc(benchmark.FieldGroupID),
-				ent.Asc(benchmark.FieldID),
-			)
-		}),
-		database.WithLimit(limit),
-		database.WithOffset(offset),
-	)
-	if err != nil {
-		p.PrintlnError(err.Error())
-		return fmt.Errorf("fetch benchmarks: %w", err)
+// recordedBetweenOption converts the values of the --since and --until flags into a query option. It returns nil if
+// neither is given.
+func recordedBetweenOption(sinceValue, untilValue string) (database.QueryOption, error) {
+	if sinceValue == "" && untilValue == "" {
+		return nil, nil
 	}
 
-	if len(benchmarks) == 0 {
-		p.PrintlnWarning("no benchmarks found")
-		return fmt.Errorf("no benchmarks found")
+	var since, until time.Time
+	var err error
+	if sinceValue != "" {
+		since, _, err = parseTimeFlag("since", sinceValue)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	p.PrintlnSuccess(benchmarkRange)
+	if untilValue != "" {
+		var dateOnly bool
+		until, dateOnly, err = parseTimeFlag("until", untilValue)
+		if err != nil {
+			return nil, err
+		}
+
+		// A date includes all benchmarks of that day
+		if dateOnly {
+			until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+	}
 
-	// Export benchmarks
-	p.PrintInfo(" Writing ... ", printer.WithIndent())
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return nil, fmt.Errorf("--until must not be before --since")
+	}
 
-	// Open file
-	var (
-		format  string
-		expFunc exporter.Exporter
-		expData any
-	)
+	return database.WithRecordedBetween(since, until), nil
+}
 
-	switch opts.format {
+// newExportStream returns the stream exporter for the given format, along with the function that converts a page of
+// benchmarks into the records of the format.
+func newExportStream(format string, w io.Writer) (exporter.StreamExporter, func([]*models.Benchmark) any, error) {
+	switch format {
 	case csvFileFormat:
-		format = csvFileFormat
-		expFunc = exporter.ToCSV
-		expData = converter.BenchmarksToCSV(benchmarks)
+		return exporter.NewCSVStream(w), func(b []*models.Benchmark) any { return converter.BenchmarksToCSV(b) }, nil
 	case jsonFileFormat:
-		format = jsonFileFormat
-		expFunc = exporter.ToJSON
-		expData = benchmarks // Doesn't need conversion
+		return exporter.NewJSONStream(w), func(b []*models.Benchmark) any { return b }, nil // Doesn't need conversion
+	case ndjsonFileFormat:
+		return exporter.NewNDJSONStream(w), func(b []*models.Benchmark) any { return b }, nil
+	case parquetFileFormat:
+		return exporter.NewParquetStream(w), func(b []*models.Benchmark) any { return converter.BenchmarksToParquet(b) }, nil
 	default:
-		p.PrintlnError("unknown export format")
-		return fmt.Errorf("unknown export format: %s", opts.format)
+		return nil, nil, fmt.Errorf("unknown export format: %s", format)
 	}
+}
+
+// exportBatchSize returns the number of benchmarks to write per file. A SQLite export is a single database, no matter how
+// many benchmarks it holds. Other formats are split into batches of the given size, within sane limits.
+func exportBatchSize(format string, batchSize int, count uint64) int {
+	if format == sqliteFileFormat {
+		return int(count)
+	}
+
+	return sanitizeBatchSize(batchSize)
+}
+
+func exportBenchmarksBatch(ctx context.Context, opts *exportOptions, p *printer.Printer, db database.Store, offset, limit int) (manifest.File, error) {
+	if opts.format == sqliteFileFormat {
+		return exportBenchmarksToSQLite(ctx, opts, p, db, offset, limit)
+	}
+
+	benchmarkRange := fmt.Sprintf("(%d-%d)", offset+1, offset+limit)
+	p.PrintInfo(" Exporting ... ", printer.WithIndent())
 
 	// Create the file
-	fileName := generateExportFilePath(format)
+	fileName := generateExportFilePath(opts.format)
 	path := filepath.Join(opts.targetDir, fileName)
 
 	file, err := os.Create(path)
 	if err != nil {
 		p.PrintlnError(err.Error())
-		return fmt.Errorf("create file: %w", err)
+		return manifest.File{}, fmt.Errorf("create file: %w", err)
 	}
 	defer file.Close()
 
-	// Export data
-	if err := expFunc(file, expData); err != nil {
+	w := manifest.NewHashingWriter(file)
+	stream, convert, err := newExportStream(opts.format, w)
+	if err != nil {
+		p.PrintlnError(err.Error())
+		return manifest.File{}, err
+	}
+
+	// JSON records hold the benchmarks with all of their edges, CSV and Parquet records only aggregates of some
+	filters := opts.filters
+	if opts.format == jsonFileFormat || opts.format == ndjsonFileFormat {
+		filters = append(slices.Clone(filters), database.WithEdgesForCopy())
+	}
+
+	records, err := fetchPages(ctx, db, filters, offset, limit, func(benchmarks []*models.Benchmark) error {
+		if err := stream.Write(convert(benchmarks)); err != nil {
+			return fmt.Errorf("export benchmarks: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
 		p.PrintlnError(err.Error())
-		return fmt.Errorf("export benchmarks: %w", err)
+		return manifest.File{}, err
+	}
+
+	if records == 0 {
+		p.PrintlnWarning("no benchmarks found")
+		return manifest.File{}, fmt.Errorf("no benchmarks found")
+	}
+
+	if err := stream.Close(); err != nil {
+		p.PrintlnError(err.Error())
+		return manifest.File{}, fmt.Errorf("export benchmarks: %w", err)
 	}
 
 	p.PrintlnSuccess(benchmarkRange)
 
-	return nil
+	return manifest.File{Name: fileName, Records: records, SHA256: w.Sum()}, nil
 }
+
+// exportBenchmarksToSQLite writes a batch into a fresh SQLite database with the schema of the current version of
+// dbench. The database holds the benchmarks with all of their edges, exactly as they're stored.
+func exportBenchmarksToSQLite(ctx context.Context, opts *exportOptions, p *printer.Printer, db database.Store, offset, limit int) (manifest.File, error) {
+	benchmarkRange := fmt.Sprintf("(%d-%d)", offset+1, offset+limit)
+	p.PrintInfo(" Exporting ... ", printer.WithIndent())
+
+	fileName := generateExportFilePath(sqliteFileFormat)
+	path := filepath.Join(opts.targetDir, fileName)
+
+	records, err := writeSQLiteExport(ctx, db, path, opts.filters, offset, limit)
+	if err != nil {
+		_ = removeDatabaseFiles(path)
+		p.PrintlnError(err.Error())
+		return manifest.File{}, err
+	}
+
+	if records == 0 {
+		_ = removeDatabaseFiles(path)
+		p.PrintlnWarning("no benchmarks found")
+		return manifest.File{}, fmt.Errorf("no benchmarks found")
+	}
+
+	checksum, err := manifest.Checksum(path)
+	if err != nil {
+		p.PrintlnError(err.Error())
+		return manifest.File{}, fmt.Errorf("checksum export: %w", err)
+	}
+
+	p.PrintlnSuccess(benchmarkRange)
+
+	return manifest.File{Name: fileName, Records: records, SHA256: checksum}, nil
+}
+
+// writeSQLiteExport creates a SQLite database at the given path and copies a batch of benchmarks into it. The database
+// is closed before returning, which checkpoints its write-ahead log, so that the file is complete on its own.
+func writeSQLiteExport(ctx context.Context, db database.Store, path string, filters []database.QueryOption, offset, limit int) (int, error) {
+	if _, err := os.Stat(path); err == nil {
+		return 0, fmt.Errorf("%s already exists", path)
+	}
+
+	target, err := openLocalDatabase(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := target.MigrateUp(ctx, build.Version); err != nil {
+		_ = target.Close()
+		return 0, fmt.Errorf("create schema: %w", err)
+	}
+
+	// The benchmarks are saved as they are, including their IDs, groups and all of their edges
+	filters = append(slices.Clone(filters), database.WithEdgesForCopy())
+	records, err := fetchPages(ctx, db, filters, offset, limit, func(benchmarks []*models.Benchmark) error {
+		if _, err := target.SaveMany(ctx, benchmarks); err != nil {
+			return fmt.Errorf("save benchmarks: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = target.Close()
+		return records, err
+	}
+
+	if err := target.Close(); err != nil {
+		return records, fmt.Errorf("close database: %w", err)
+	}
+
+	return records, nil
+}
+
+var exportLongDesc = `Export benchmarks to JSON, NDJSON, CSV, Parquet or SQLite files. The benchmarks
+are written in batches, each batch to its own file, along with a manifest that
+lets imports verify the export.
+
+SQLite exports are a single, standalone database with the same schema as the one
+of dbench, holding the benchmarks with all of their metrics and raw outputs. They
+are the most complete exchange format, can be queried with any SQLite tool and
+can be imported again.
+
+Parquet files hold the same columns as CSV files, but typed, so that they can be
+loaded into analytics tools like DuckDB or pandas directly. They're meant for
+analysis only and can't be imported again.
+
+By default, all benchmarks are exported. To hand over exactly the benchmarks of
+an experiment, narrow the export down by:
+
+  - the IDs of benchmarks or the names or IDs of benchmark-groups as arguments
+  - the time the benchmarks were recorded at with '--since' and '--until'
+  - their tags with '--tag' and arbitrary conditions with '--where'
+
+Given several of them, only the benchmarks matching all of them are exported.`
@@ -2,21 +2,31 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"go.jetpack.io/typeid"
 
 	"github.com/nikoksr/dbench/cmd/cobrax"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
 	"github.com/nikoksr/dbench/internal/archive"
+	pgbench "github.com/nikoksr/dbench/internal/benchmark"
 	"github.com/nikoksr/dbench/internal/build"
 	"github.com/nikoksr/dbench/internal/database"
 	"github.com/nikoksr/dbench/internal/fs"
 	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/portability/converter"
 	"github.com/nikoksr/dbench/internal/portability/importer"
+	"github.com/nikoksr/dbench/internal/portability/manifest"
 	"github.com/nikoksr/dbench/internal/ui/printer"
 )
 
@@ -29,6 +39,15 @@ type importOptions struct {
 
 	importPaths []string
 	lax         bool
+	onConflict  string
+
+	// Options for imports of pgbench output files
+	fromPgbench bool
+	name        string
+	description string
+	comment     string
+	tags        []string
+	recordedAt  string
 }
 
 func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
@@ -48,6 +67,19 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 		Args:                  cobra.MinimumNArgs(1),
 		PreRunE:               cobrax.HooksE(pgbenchInstalledHook()),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			strategy, err := database.ParseConflictStrategy(opts.onConflict)
+			if err != nil {
+				return err
+			}
+
+			if !opts.fromPgbench {
+				for _, name := range []string{"name", "description", "comment", "tag", "recorded-at"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s requires --from-pgbench", name)
+					}
+				}
+			}
+
 			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
 			if err != nil {
 				return fmt.Errorf("connect to database: %w", err)
@@ -56,6 +88,11 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			// Print header
 			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
 			p.PrintlnTitle("Import")
+
+			if opts.fromPgbench {
+				return importPgbenchOutputs(cmd.Context(), opts, p, db, args, strategy)
+			}
+
 			p.PrintlnSubTitle("Preparation")
 
 			// Set import paths
@@ -82,8 +119,11 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			// Collect all files to import
 			p.PrintInfo(" Collecting files to import ...", printer.WithIndent())
 
+			// Exports carry a manifest, which is verified before anything is imported. Exports of older versions of
+			// dbench don't have one.
 			files := make([]string, 0)
-			for _, path := range opts.importPaths {
+			manifests := make(map[string]*manifest.Manifest)
+			for idx, path := range opts.importPaths {
 				// Check if path is a directory
 				if !isPathADirectory(path) {
 
@@ -94,14 +134,29 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 						continue
 					}
 
-					// It's an archive, extract it
-					if err := extractArchive(cmd.Context(), path, extractDir); err != nil {
+					// It's an archive, extract it. Each archive gets its own directory, so that their manifests don't
+					// overwrite each other.
+					archiveDir := filepath.Join(extractDir, strconv.Itoa(idx))
+					if err := extractArchive(cmd.Context(), path, archiveDir); err != nil {
 						p.PrintlnError(err.Error())
 						return fmt.Errorf("extract archive: %w", err)
 					}
 
 					// We fall through here to add all files in the extracted directory to the import list
-					path = extractDir
+					path = archiveDir
+				}
+
+				exportManifest, err := manifest.Read(path)
+				if err != nil {
+					p.PrintlnError(err.Error())
+					return fmt.Errorf("read manifest of %s: %w", path, err)
+				}
+				if exportManifest != nil {
+					if err := exportManifest.Verify(path); err != nil {
+						p.PrintlnError("")
+						return fmt.Errorf("verify export %s: %w", path, err)
+					}
+					manifests[filepath.Clean(path)] = exportManifest
 				}
 
 				// Get all files in directory
@@ -113,6 +168,9 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 
 				// Add all files to import list
 				for _, file := range filesInDir {
+					if file.Name() == manifest.FileName {
+						continue
+					}
 					files = append(files, filepath.Join(path, file.Name()))
 				}
 			}
@@ -124,7 +182,7 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			p.PrintlnSubTitle("Importing")
 
 			start := time.Now()
-			benchmarksImported := 0
+			summary := &database.ImportSummary{}
 
 			// Only initialize map if we're not in lax mode
 			var importedFiles map[string]struct{}
@@ -135,9 +193,10 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 			for _, path := range files {
 				p.PrintInfo(fmt.Sprintf(" %s ... ", filepath.Base(path)), printer.WithIndent())
 
-				// Check if file is a JSON file
-				if !strings.EqualFold(filepath.Ext(path), ".json") {
-					p.PrintlnWarning("skipping; not a JSON file")
+				// Check if file is a JSON, NDJSON, CSV or SQLite file
+				ext := strings.ToLower(filepath.Ext(path))
+				if ext != "."+jsonFileFormat && ext != "."+ndjsonFileFormat && ext != "."+csvFileFormat && !isSQLiteFile(path) {
+					p.PrintlnWarning("skipping; not a JSON, NDJSON, CSV or SQLite file")
 					continue
 				}
 
@@ -147,15 +206,32 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 					continue
 				}
 
+				// Files of exports with a manifest must be listed in it
+				source := exportFile{path: path, schemaVersion: manifest.LegacySchemaVersion, records: -1}
+				if exportManifest, found := manifests[filepath.Dir(path)]; found {
+					entry, listed := exportManifest.Lookup(filepath.Base(path))
+					if !listed {
+						p.PrintlnWarning("skipping; not listed in manifest")
+						continue
+					}
+
+					source.schemaVersion = exportManifest.SchemaVersion
+					source.records = entry.Records
+				}
+
 				// Import benchmarks from file
-				benchmarkCount, err := importBenchmarksFromFile(cmd.Context(), db, path)
+				importFile := importBenchmarksFromFile
+				if isSQLiteFile(path) {
+					importFile = importBenchmarksFromSQLite
+				}
+
+				fileSummary, err := importFile(cmd.Context(), db, source, strategy)
 				if err != nil {
 					p.PrintlnError(err.Error())
 					return err
 				}
 
-				// Increment benchmark counter
-				benchmarksImported += benchmarkCount
+				summary.Add(fileSummary)
 
 				// Add file to imported files. The lax flag check here isn't really necessary but helps with memory
 				// usage in case we import a lot of files.
@@ -163,7 +239,7 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 					importedFiles[path] = struct{}{}
 				}
 
-				p.PrintlnSuccess(fmt.Sprintf("imported %d benchmarks", benchmarkCount))
+				p.PrintlnSuccess(formatImportSummary(fileSummary))
 			}
 
 			// Print benchmark complete message
@@ -172,7 +248,8 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 
 			p.Spacer(2)
 			p.PrintText(" Complete! Imported ")
-			p.PrintlnHighlight(fmt.Sprintf("%d benchmarks in %s", benchmarksImported, timeToImport.Round(time.Millisecond)))
+			p.PrintlnHighlight(fmt.Sprintf("%d benchmarks in %s", summary.Imported+summary.Replaced+summary.Duplicated, timeToImport.Round(time.Millisecond)))
+			p.PrintlnText(fmt.Sprintf(" %s.", formatImportSummary(summary)))
 			p.Spacer(2)
 
 			return nil
@@ -180,38 +257,377 @@ func newImportCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 	}
 
 	cmd.Flags().BoolVar(&opts.lax, "lax", false, "Less strict imports; allows to import duplicate data sources")
+	cmd.Flags().StringVar(&opts.onConflict, "on-conflict", string(database.ConflictSkip), "How to handle benchmarks that already exist (skip, replace, new-id)")
+	cmd.Flags().BoolVar(&opts.fromPgbench, "from-pgbench", false, "Import files of raw pgbench output into a new benchmark-group")
+	cmd.Flags().StringVarP(&opts.name, "name", "n", "", "Unique name of the benchmark-group; only with --from-pgbench")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Description of the benchmark-group; only with --from-pgbench")
+	cmd.Flags().StringVarP(&opts.comment, "comment", "c", "", "Comment to add to the benchmarks; only with --from-pgbench")
+	cmd.Flags().StringSliceVarP(&opts.tags, "tag", "t", nil, "Key-value tags to label the benchmarks with, e.g. env=staging,pg=16.1; only with --from-pgbench")
+	cmd.Flags().StringVar(&opts.recordedAt, "recorded-at", "", "Time the benchmarks were recorded at, e.g. 2024-01-31 or 2024-01-31T15:04:05 (defaults to the modification time of each file); only with --from-pgbench")
+
+	_ = cmd.RegisterFlagCompletionFunc("on-conflict", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		strategies := database.ConflictStrategies()
+		names := make([]string, 0, len(strategies))
+		for _, strategy := range strategies {
+			names = append(names, string(strategy))
+		}
+
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	cmd.Flags().SortFlags = false
 
 	return cmd
 }
 
-func importBenchmarksFromFile(ctx context.Context, db database.Store, path string) (int, error) {
+// exportFile is a file to import along with what the manifest of its export says about it.
+type exportFile struct {
+	path          string
+	schemaVersion int
+	records       int // Number of records according to the manifest, -1 if unknown
+}
+
+// importChunkSize is the number of benchmarks that are imported at once. Files are read record by record and imported
+// in chunks of this size, so that large exports don't have to be held in memory.
+const importChunkSize = 500
+
+func importBenchmarksFromFile(ctx context.Context, db database.Store, source exportFile, strategy database.ConflictStrategy) (*database.ImportSummary, error) {
 	// Open file
-	file, err := os.Open(path)
+	file, err := os.Open(source.path)
 	if err != nil {
-		return 0, fmt.Errorf("open file: %w", err)
+		return nil, fmt.Errorf("open file: %w", err)
 	}
 	defer file.Close()
 
-	data, err := importer.FromJSON[[]*models.Benchmark](file)
+	summary := &database.ImportSummary{}
+	records := 0
+	chunk := make([]*models.Benchmark, 0, importChunkSize)
+
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+
+		chunkSummary, err := db.Import(ctx, chunk, strategy)
+		if err != nil {
+			return fmt.Errorf("save benchmarks: %w", err)
+		}
+
+		summary.Add(chunkSummary)
+		chunk = chunk[:0]
+
+		return nil
+	}
+
+	add := func(bmark *models.Benchmark) error {
+		records++
+		chunk = append(chunk, bmark)
+		if len(chunk) < importChunkSize {
+			return nil
+		}
+
+		return flush()
+	}
+
+	if strings.EqualFold(filepath.Ext(source.path), "."+csvFileFormat) {
+		err = streamBenchmarksFromCSV(file, add)
+	} else {
+		err = streamBenchmarksFromJSON(file, source.schemaVersion, add)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("read data file: %w", err)
+		return nil, fmt.Errorf("read data file: %w", err)
 	}
 
-	// Get number of records and check if we have any
-	records := len(data)
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	// Check if we had any records and as many as the manifest lists
 	if records == 0 {
-		return 0, fmt.Errorf("no benchmarks found in file")
+		return nil, fmt.Errorf("no benchmarks found in file")
+	}
+	if source.records >= 0 && records != source.records {
+		return nil, fmt.Errorf("file holds %d benchmarks, but the manifest lists %d", records, source.records)
+	}
+
+	return summary, nil
+}
+
+// isSQLiteFile reports whether the given file is a SQLite export or a dbench database, judging by its extension.
+func isSQLiteFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == "."+sqliteFileFormat || ext == ".db"
+}
+
+// importBenchmarksFromSQLite imports the benchmarks of a SQLite export or of another dbench database. They're read in
+// chunks, just like the records of the other formats, and keep all of their edges.
+func importBenchmarksFromSQLite(ctx context.Context, db database.Store, source exportFile, strategy database.ConflictStrategy) (*database.ImportSummary, error) {
+	sourceDB, cleanup, err := openSQLiteSource(ctx, source.path)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	count, err := sourceDB.Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count benchmarks: %w", err)
+	}
+
+	// Check if we have any benchmarks and as many as the manifest lists
+	if count == 0 {
+		return nil, fmt.Errorf("no benchmarks found in file")
+	}
+	if source.records >= 0 && int(count) != source.records {
+		return nil, fmt.Errorf("file holds %d benchmarks, but the manifest lists %d", count, source.records)
 	}
 
-	// Save benchmarks
-	_, err = db.SaveMany(ctx, data)
+	return copyBenchmarks(ctx, db, sourceDB, int(count), strategy)
+}
+
+// copyBenchmarks imports the given number of benchmarks of the source database into the database in chunks.
+func copyBenchmarks(ctx context.Context, db, source database.Store, count int, strategy database.ConflictStrategy) (*database.ImportSummary, error) {
+	summary := &database.ImportSummary{}
+	filters := []database.QueryOption{database.WithEdgesForCopy()}
+	_, err := fetchPages(ctx, source, filters, 0, count, func(benchmarks []*models.Benchmark) error {
+		chunkSummary, err := db.Import(ctx, benchmarks, strategy)
+		if err != nil {
+			return fmt.Errorf("save benchmarks: %w", err)
+		}
+
+		summary.Add(chunkSummary)
+
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("save benchmarks: %w", err)
+		return nil, err
 	}
 
-	return records, nil
+	return summary, nil
+}
+
+// openSQLiteSource opens a SQLite database to read benchmarks from. The database is opened on a copy, which is
+// migrated if the database was created by an older version of dbench, so that the file itself is never changed.
+// Databases of newer versions are refused. The returned function closes the database and removes the copy.
+func openSQLiteSource(ctx context.Context, path string) (*database.DB, func(), error) {
+	dir, err := os.MkdirTemp("", build.AppName+"-source-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create temporary directory: %w", err)
+	}
+
+	copyPath := filepath.Join(dir, filepath.Base(path))
+	if err := copySQLiteFile(path, copyPath); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, fmt.Errorf("copy %s: %w", path, err)
+	}
+
+	sourceDB, err := openLocalDatabase(ctx, copyPath)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, nil, err
+	}
+
+	cleanup := func() {
+		_ = sourceDB.Close()
+		_ = os.RemoveAll(dir)
+	}
+
+	if err := migrateSQLiteSource(ctx, sourceDB); err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("prepare %s: %w", path, err)
+	}
+
+	return sourceDB, cleanup, nil
+}
+
+// copySQLiteFile copies a SQLite database file along with its write-ahead log, which holds the latest changes of a
+// database that is still in use.
+func copySQLiteFile(src, dst string) error {
+	if err := copyFile(src, dst); err != nil {
+		return err
+	}
+
+	err := copyFile(src+"-wal", dst+"-wal")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+// migrateSQLiteSource migrates a database to read benchmarks from to the current schema. Like restores, it refuses
+// databases of newer versions of dbench, whose schema is unknown to us.
+func migrateSQLiteSource(ctx context.Context, sourceDB *database.DB) error {
+	shouldMigrate, err := sourceDB.ShouldMigrate(ctx, build.Version)
+	if err != nil {
+		return err
+	}
+
+	sourceVersion, err := sourceDB.AppVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if isNewerVersion(sourceVersion, build.Version) {
+		return fmt.Errorf("database was written by dbench %s, which is newer than this version (%s)", sourceVersion, build.Version)
+	}
+
+	if !shouldMigrate {
+		return nil
+	}
+
+	if _, err := sourceDB.MigrateUp(ctx, build.Version); err != nil {
+		return fmt.Errorf("migrate database: %w", err)
+	}
+
+	return nil
+}
+
+// streamBenchmarksFromJSON reads benchmarks from a JSON or NDJSON export one by one. Records of older schema versions
+// are transformed to the current one first.
+func streamBenchmarksFromJSON(r io.Reader, schemaVersion int, fn func(*models.Benchmark) error) error {
+	return importer.StreamJSON(r, func(record json.RawMessage) error {
+		record, err := manifest.UpgradeRecord(record, schemaVersion)
+		if err != nil {
+			return err
+		}
+
+		bmark := new(models.Benchmark)
+		if err := json.Unmarshal(record, bmark); err != nil {
+			return err
+		}
+
+		return fn(bmark)
+	})
+}
+
+// streamBenchmarksFromCSV reads benchmarks from a CSV export one by one. CSV exports only hold aggregates of some
+// metrics, see converter.BenchmarkFromCSV.
+func streamBenchmarksFromCSV(r io.Reader, fn func(*models.Benchmark) error) error {
+	line := 1 // The header
+	return importer.StreamCSV(r, func(record *models.BenchmarkCSV) error {
+		line++
+		bmark, err := converter.BenchmarkFromCSV(record)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		return fn(bmark)
+	})
+}
+
+func formatImportSummary(summary *database.ImportSummary) string {
+	return fmt.Sprintf("%d imported, %d skipped, %d conflicted, %d replaced, %d duplicated",
+		summary.Imported, summary.Skipped, summary.Conflicted, summary.Replaced, summary.Duplicated)
+}
+
+// importPgbenchOutputs imports files of raw pgbench output into a new benchmark-group.
+func importPgbenchOutputs(ctx context.Context, opts *importOptions, p *printer.Printer, db database.Store, paths []string, strategy database.ConflictStrategy) error {
+	tags, err := models.ParseTags(opts.tags)
+	if err != nil {
+		return err
+	}
+
+	var recordedAt time.Time
+	if opts.recordedAt != "" {
+		recordedAt, _, err = parseTimeFlag("recorded-at", opts.recordedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	groupID, err := typeid.WithPrefix("bmkgrp")
+	if err != nil {
+		return fmt.Errorf("create benchmark group id: %w", err)
+	}
+
+	// The group is created along with its first benchmark. It spans the time its benchmarks were recorded at.
+	group := &models.BenchmarkGroup{
+		ID:     pulid.ID(groupID.String()),
+		Status: benchmarkgroup.StatusCompleted,
+	}
+	if opts.name != "" {
+		group.Name = &opts.name
+	}
+	if opts.description != "" {
+		group.Description = &opts.description
+	}
+
+	p.PrintlnSubTitle("Parsing")
+
+	benchmarks := make([]*models.Benchmark, 0, len(paths))
+	for _, path := range paths {
+		p.PrintInfo(fmt.Sprintf(" %s ... ", filepath.Base(path)), printer.WithIndent())
+
+		bmark, err := readPgbenchOutputFile(path, recordedAt)
+		if err != nil {
+			p.PrintlnError(err.Error())
+			return fmt.Errorf("import %s: %w", path, err)
+		}
+
+		bmark.GroupID = group.ID
+		bmark.Edges.Group = group
+		bmark.Edges.Tags = tags
+		if opts.comment != "" {
+			bmark.Comment = &opts.comment
+		}
+
+		if group.StartedAt.IsZero() || bmark.RecordedAt.Before(group.StartedAt) {
+			group.StartedAt = bmark.RecordedAt
+		}
+		if group.FinishedAt == nil || bmark.RecordedAt.After(*group.FinishedAt) {
+			group.FinishedAt = &bmark.RecordedAt
+		}
+
+		benchmarks = append(benchmarks, bmark)
+
+		p.PrintlnSuccess(fmt.Sprintf("%d clients, %.2f tps", bmark.Clients, bmark.Edges.Result.TransactionsPerSecond))
+	}
+
+	// Import benchmarks
+	p.Spacer(2)
+	p.PrintlnSubTitle("Importing")
+	p.PrintInfo(" Saving benchmarks ... ", printer.WithIndent())
+
+	summary, err := db.Import(ctx, benchmarks, strategy)
+	if err != nil {
+		p.PrintlnError(err.Error())
+		return fmt.Errorf("save benchmarks: %w", err)
+	}
+
+	p.PrintlnSuccess(formatImportSummary(summary))
+
+	p.Spacer(2)
+	p.PrintText(" Complete! Imported into benchmark-group ")
+	p.PrintlnHighlight(group.ID.String())
+	p.Spacer(2)
+
+	return nil
+}
+
+// readPgbenchOutputFile reads a benchmark from a file of raw pgbench output. Unless a time is given, the benchmark is
+// taken as recorded at the modification time of the file.
+func readPgbenchOutputFile(path string, recordedAt time.Time) (*models.Benchmark, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat file: %w", err)
+	}
+
+	output, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read file: %w", err)
+	}
+
+	bmark, err := pgbench.FromOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	bmark.RecordedAt = recordedAt
+	if bmark.RecordedAt.IsZero() {
+		bmark.RecordedAt = info.ModTime()
+	}
+	bmark.RecordedAt = bmark.RecordedAt.UTC()
+
+	return bmark, nil
 }
 
 func extractArchive(ctx context.Context, sourcePath, targetPath string) error {
@@ -222,13 +638,42 @@ func extractArchive(ctx context.Context, sourcePath, targetPath string) error {
 	}
 	defer file.Close()
 
+	if err := os.MkdirAll(targetPath, 0o755); err != nil {
+		return fmt.Errorf("prepare directory: %w", err)
+	}
+
 	// Extract archive
 	return archive.Extract(ctx, file, targetPath)
 }
 
 var importLongDesc = `
 Import a dbench data directory or file into the database. This command will import
-all JSON files in the given directory or file.
-
-Note: We only support JSON as it's far more consistent and robust to import from.
-We only support the CSV exports for the sake of compatibility with other tools.`
+all JSON, NDJSON, CSV and SQLite files in the given directory or file. Files are read
+record by record and imported in chunks, so that large exports need little memory.
+
+SQLite files are SQLite exports or other dbench databases, e.g. backups. They hold
+the benchmarks with all of their metrics. Files of older versions of dbench are
+migrated on a copy, the files themselves are never changed.
+
+Exports carry a manifest with the version of dbench that created them and the
+checksum and number of records of each file. Before anything is imported, the
+manifest is verified, so that missing or corrupted files are detected. Records
+of older exports are transformed to the current layout.
+
+Benchmarks keep their original IDs. A benchmark exists already if a stored one
+has its ID or its content, i.e. was recorded at the same time, with the same
+pgbench command, for the same runtime, on the same machine and with the same
+pgbench output. Such benchmarks are handled according to '--on-conflict':
+
+  skip     keep the existing benchmark (default); if only the ID matches, the
+           imported benchmark is counted as conflicted
+  replace  replace the existing benchmark with the imported one
+  new-id   import the benchmark anyway, under a new ID if its ID is taken
+
+With '--from-pgbench', the given files are taken as raw pgbench output instead,
+e.g. of runs from before dbench was adopted. Each file becomes a benchmark of a
+new benchmark-group. The output holds no system metrics, so these are absent.
+
+Note: JSON is far more consistent and robust to import from. CSV exports only hold
+aggregates of the disk, network and process metrics and the shares of the wait
+events, which are lost when importing them.`
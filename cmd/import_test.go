@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+)
+
+// pgbenchOutput is the output of a pgbench run with the given number of clients and tps.
+const pgbenchOutput = `pgbench (16.1)
+transaction type: <builtin: TPC-B (sort of)>
+scaling factor: 1
+query mode: simple
+number of clients: %d
+number of threads: 1
+maximum number of tries: 1
+duration: 10 s
+number of transactions actually processed: 12345
+number of failed transactions: 0 (0.000%%)
+latency average = 1.234 ms
+initial connection time = 2.345 ms
+tps = %.6f (without initial connection time)
+`
+
+func TestImportPgbenchOutputsWithSameRecordingTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	db, err := database.New().Connect(ctx, "file:"+filepath.Join(dir, "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.MigrateUp(ctx, "dev")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var paths []string
+	for _, clients := range []int{1, 8} {
+		path := filepath.Join(dir, fmt.Sprintf("pgbench-%d.txt", clients))
+		if !assert.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf(pgbenchOutput, clients, 1234.5*float64(clients))), 0o600)) {
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	opts := &importOptions{
+		globalOptions: &globalOptions{},
+		recordedAt:    "2024-01-31T12:00:00",
+	}
+	p := printer.NewPrinter(io.Discard, 50)
+
+	// Each file is a benchmark of its own, even though they were recorded at the same time
+	assert.NoError(t, importPgbenchOutputs(ctx, opts, p, db, paths, database.ConflictSkip))
+
+	count, err := db.CountAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(paths)), count)
+
+	// Importing the same files again recognizes them
+	assert.NoError(t, importPgbenchOutputs(ctx, opts, p, db, paths, database.ConflictSkip))
+
+	count, err = db.CountAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(len(paths)), count)
+}
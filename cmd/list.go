@@ -29,6 +29,8 @@ type listOptions struct {
 	*globalOptions
 
 	sort                      []string
+	tags                      []string
+	where                     string
 	page, perPage, totalPages int
 }
 
@@ -55,8 +57,13 @@ func newListCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 
 			p := printer.NewPrinter(cmd.OutOrStdout())
 
+			filters, err := filterOptions(opts.tags, opts.where)
+			if err != nil {
+				return err
+			}
+
 			// Get the total benchmark count and calculate the total number of pages for pagination
-			benchmarksCount, err := db.Count(cmd.Context())
+			benchmarksCount, err := db.Count(cmd.Context(), filters...)
 			if err != nil {
 				return fmt.Errorf("count benchmarks: %w", err)
 			}
@@ -85,11 +92,11 @@ func newListCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 				}
 			}
 
-			benchmarks, err := db.Fetch(cmd.Context(),
+			benchmarks, err := db.Fetch(cmd.Context(), append(filters,
 				database.WithOrderBy(orderByFunc),
 				database.WithLimit(opts.perPage),
 				database.WithOffset((opts.page-1)*opts.perPage),
-			)
+			)...)
 			if err != nil {
 				return fmt.Errorf("fetch benchmarks: %w", err)
 			}
@@ -114,6 +121,7 @@ func newListCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 	cmd.Flags().IntVar(&opts.page, "page", defaultPage, "Page number")
 	cmd.Flags().IntVar(&opts.perPage, "per-page", defaultPerPage, "Number of benchmarks per page")
 	cmd.Flags().StringSliceVar(&opts.sort, "sort", nil, "Sort benchmarks columns (+/- for ascending/descending)")
+	addFilterFlags(cmd, &opts.tags, &opts.where)
 
 	cmd.Flags().SortFlags = false
 
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+)
+
+func newMergeCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "merge FILE",
+		GroupID:               "commands",
+		Short:                 "Merge the benchmarks of another dbench database",
+		Long:                  mergeLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourcePath := args[0]
+			if _, err := os.Stat(sourcePath); err != nil {
+				return fmt.Errorf("open database %s: %w", sourcePath, err)
+			}
+
+			// Merging the local database into itself would only skip all of its benchmarks
+			if dbPath, err := localDatabasePath(globalOpts); err == nil && isSameFile(sourcePath, dbPath) {
+				return fmt.Errorf("%s is the database to merge into", sourcePath)
+			}
+
+			db, err := connectToDB(cmd.Context(), globalOpts.dataDir, globalOpts.noMigration, fs.OSFileSystem{})
+			if err != nil {
+				return fmt.Errorf("connect to database: %w", err)
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Merge")
+			p.PrintlnSubTitle("Preparation")
+
+			p.PrintInfo(fmt.Sprintf(" Opening %s ... ", filepath.Base(sourcePath)), printer.WithIndent())
+
+			sourceDB, cleanup, err := openSQLiteSource(cmd.Context(), sourcePath)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return err
+			}
+			defer cleanup()
+
+			count, err := sourceDB.Count(cmd.Context())
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("count benchmarks: %w", err)
+			}
+
+			if count == 0 {
+				p.PrintlnWarning("no benchmarks found")
+				p.Spacer(2)
+				return nil
+			}
+
+			p.PrintlnSuccess(fmt.Sprintf("%d found", count))
+
+			// Merge benchmarks
+			p.Spacer(2)
+			p.PrintlnSubTitle("Merging")
+			p.PrintInfo(" Copying benchmarks ... ", printer.WithIndent())
+
+			start := time.Now()
+
+			// Benchmarks keep their IDs; ones that exist already are skipped
+			summary, err := copyBenchmarks(cmd.Context(), db, sourceDB, int(count), database.ConflictSkip)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("merge benchmarks: %w", err)
+			}
+
+			p.PrintlnSuccess("")
+
+			p.Spacer(2)
+			p.PrintText(" Complete! Merged ")
+			p.PrintlnHighlight(fmt.Sprintf("%d benchmarks in %s", summary.Imported, time.Since(start).Round(time.Millisecond)))
+			p.PrintlnText(fmt.Sprintf(" %d merged, %d skipped, %d conflicted.", summary.Imported, summary.Skipped, summary.Conflicted))
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// isSameFile reports whether both paths point to the same file. Paths that can't be inspected are never the same.
+func isSameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(infoA, infoB)
+}
+
+var mergeLongDesc = `Merge the benchmarks of another dbench database into this one, e.g. to
+consolidate the databases of several machines or CI runners.
+
+The other database is never changed. If it was written by an older version of
+dbench, a copy of it is migrated to the current schema first. Databases of newer
+versions are refused.
+
+All benchmarks are copied with all of their metrics and keep their IDs and
+benchmark-groups. Benchmarks that exist already, either by their ID or by their
+content, are skipped. A benchmark whose ID is taken by a benchmark with other
+content is counted as conflicted and isn't merged either.`
@@ -0,0 +1,230 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/dbench/internal/build"
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+)
+
+type migrateDownOptions struct {
+	*globalOptions
+
+	steps int
+}
+
+func newMigrateCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "migrate [COMMAND]",
+		GroupID:               "commands",
+		Short:                 "Show and apply the migrations of the database",
+		Long:                  migrateLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+	}
+
+	cmd.AddCommand(
+		newMigrateStatusCommand(globalOpts, connectToDB),
+		newMigrateUpCommand(globalOpts, connectToDB),
+		newMigrateDownCommand(globalOpts, connectToDB),
+	)
+
+	return cmd
+}
+
+// connectWithoutMigration connects to the database without migrating it automatically, so that the migrate commands
+// stay in control of the migrations.
+func connectWithoutMigration(ctx context.Context, opts *globalOptions, connectToDB dbConnector) (database.Store, error) {
+	db, err := connectToDB(ctx, opts.dataDir, true, fs.OSFileSystem{})
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+
+	return db, nil
+}
+
+func newMigrateStatusCommand(opts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	return &cobra.Command{
+		Use:                   "status",
+		Short:                 "Show which migrations are applied",
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := connectWithoutMigration(cmd.Context(), opts, connectToDB)
+			if err != nil {
+				return err
+			}
+
+			statuses, err := db.MigrationStatus(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("get migration status: %w", err)
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Migrations")
+
+			pending := 0
+			for _, status := range statuses {
+				p.PrintInfo(fmt.Sprintf(" %04d %s ... ", status.Version, status.Name), printer.WithIndent())
+
+				if !status.Applied {
+					pending++
+					p.PrintlnWarning("pending")
+					continue
+				}
+
+				p.PrintlnSuccess("applied " + status.AppliedAt.Local().Format(time.DateTime))
+			}
+
+			p.Spacer(2)
+			p.PrintlnText(fmt.Sprintf(" %d of %d migration(s) pending.", pending, len(statuses)))
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+}
+
+func newMigrateUpCommand(opts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	return &cobra.Command{
+		Use:                   "up",
+		Short:                 "Apply all pending migrations",
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := connectWithoutMigration(cmd.Context(), opts, connectToDB)
+			if err != nil {
+				return err
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Migrate up")
+
+			if err := backupBeforeMigration(cmd.Context(), p, db); err != nil {
+				return err
+			}
+
+			p.PrintInfo(" Applying migrations ... ", printer.WithIndent())
+
+			count, err := db.MigrateUp(cmd.Context(), build.Version)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("apply migrations: %w", err)
+			}
+
+			p.PrintlnSuccess(fmt.Sprintf("%d applied", count))
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+}
+
+func newMigrateDownCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	opts := &migrateDownOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "down [OPTIONS]",
+		Short:                 "Revert the most recently applied migrations",
+		Long:                  migrateDownLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.NoArgs,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.steps <= 0 {
+				return fmt.Errorf("number of steps must be positive")
+			}
+
+			db, err := connectWithoutMigration(cmd.Context(), opts.globalOptions, connectToDB)
+			if err != nil {
+				return err
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Migrate down")
+
+			if err := backupBeforeMigration(cmd.Context(), p, db); err != nil {
+				return err
+			}
+
+			p.PrintInfo(" Reverting migrations ... ", printer.WithIndent())
+
+			count, err := db.MigrateDown(cmd.Context(), opts.steps)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("revert migrations: %w", err)
+			}
+
+			p.PrintlnSuccess(fmt.Sprintf("%d reverted", count))
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&opts.steps, "steps", "n", 1, "Number of migrations to revert")
+
+	return cmd
+}
+
+func backupBeforeMigration(ctx context.Context, p *printer.Printer, db database.Store) error {
+	p.PrintInfo(" Backing up database ... ", printer.WithIndent())
+
+	backupPath, err := db.BackupBeforeMigration(ctx)
+	if err != nil {
+		p.PrintlnError(err.Error())
+		return fmt.Errorf("back up database: %w", err)
+	}
+
+	if backupPath == "" {
+		p.PrintlnSuccess("skipped")
+	} else {
+		p.PrintlnSuccess(backupPath)
+	}
+
+	return nil
+}
+
+var migrateLongDesc = `Show and apply the versioned migrations of the database.
+
+Pending migrations are applied automatically whenever a new version of dbench
+opens the database. SQLite databases are backed up next to the database file
+before any migration is applied or reverted; the three most recent of these
+backups are kept. A database that was migrated by a newer version of dbench is
+refused.
+
+Migrations can be reverted with 'dbench migrate down' before switching back to
+an older version of dbench. Alternatively, restore the backup that was taken
+before the migration with 'dbench db restore'.`
+
+var migrateDownLongDesc = `Revert the most recently applied migrations.
+
+Reverting a migration undoes its changes to the schema. Data that only fits the
+newer schema is lost, e.g. tags, the metrics that older versions of dbench don't
+collect and, when reverting the baseline, all benchmarks. Restore the backup
+that was taken before the migration to get the data back.
+
+Since pending migrations are applied automatically, the next command of the
+newer version will apply the reverted migrations again.`
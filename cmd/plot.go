@@ -6,11 +6,13 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/exp/slices"
 
 	"github.com/nikoksr/dbench/cmd/cobrax"
 	"github.com/nikoksr/dbench/internal/build"
 	"github.com/nikoksr/dbench/internal/database"
 	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/models"
 	"github.com/nikoksr/dbench/internal/plot"
 	"github.com/nikoksr/dbench/internal/portability/converter"
 	"github.com/nikoksr/dbench/internal/portability/exporter"
@@ -22,6 +24,8 @@ type plotOptions struct {
 
 	outputDir      string
 	cleanOutputDir bool
+	tags           []string
+	where          string
 }
 
 func newPlotCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
@@ -30,28 +34,61 @@ func newPlotCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 	}
 
 	cmd := &cobra.Command{
-		Use:                   "plot [OPTIONS] BENCHMARK-GROUP-ID [BENCHMARK-GROUP-ID...]",
+		Use:                   "plot [OPTIONS] [BENCHMARK-GROUP...]",
 		Aliases:               []string{"p"},
 		GroupID:               "commands",
 		Short:                 "Plot benchmark results by benchmark-groups",
+		Long:                  plotLongDesc,
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
-		Args:                  cobra.MinimumNArgs(1),
-		PreRunE:               cobrax.HooksE(gnuplotInstalledHook()),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && len(opts.tags) == 0 && opts.where == "" {
+				return fmt.Errorf("requires at least one benchmark-group name or ID, --tag or --where filter")
+			}
+			return nil
+		},
+		PreRunE: cobrax.HooksE(gnuplotInstalledHook()),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
 			if err != nil {
 				return fmt.Errorf("connect to database: %w", err)
 			}
 
+			filters, err := filterOptions(opts.tags, opts.where)
+			if err != nil {
+				return err
+			}
+
 			// Print header
 			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
 			p.PrintlnTitle("Plotting")
 			p.PrintlnSubTitle("Preparation")
 
-			// Get benchmark-group IDs
-			benchmarkGroupIDs := args
+			// Get benchmark-group IDs. Without any, we plot all groups that match the filters.
+			benchmarkGroupIDs, err := db.ResolveGroupIDs(cmd.Context(), args)
+			if err != nil {
+				return fmt.Errorf("resolve benchmark-groups: %w", err)
+			}
+
+			if len(benchmarkGroupIDs) == 0 {
+				p.PrintInfo(" Finding benchmark-groups ... ", printer.WithIndent())
+
+				benchmarkGroupIDs, err = db.FetchGroupIDs(cmd.Context(), filters...)
+				if err != nil {
+					p.PrintlnError(err.Error())
+					return fmt.Errorf("fetch benchmark-group IDs: %w", err)
+				}
+
+				benchmarkGroupIDs = slices.Compact(benchmarkGroupIDs) // Sorted by group ID
+				if len(benchmarkGroupIDs) == 0 {
+					p.PrintlnWarning("no benchmarks found")
+					p.Spacer(2)
+					return nil
+				}
+
+				p.PrintlnSuccess(fmt.Sprintf("%d found", len(benchmarkGroupIDs)))
+			}
 
 			// Cleanup output directory
 			if opts.cleanOutputDir {
@@ -76,7 +113,7 @@ func newPlotCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 			p.PrintlnSubTitle("Plotting")
 			for _, id := range benchmarkGroupIDs {
 				p.PrintInfo(" Plotting "+id+" ... ", printer.WithIndent())
-				if err := plotBenchmarks(cmd.Context(), db, id, opts.outputDir); err != nil {
+				if err := plotBenchmarks(cmd.Context(), db, id, opts.outputDir, filters...); err != nil {
 					p.PrintlnError(err.Error())
 					return fmt.Errorf("plot benchmark-group %q: %w", id, err)
 				}
@@ -94,14 +131,32 @@ func newPlotCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.C
 
 	cmd.Flags().StringVarP(&opts.outputDir, "output", "o", "dbench/plots", "Output directory for plots")
 	cmd.Flags().BoolVarP(&opts.cleanOutputDir, "clean", "c", false, "Cleanup output directory before plotting")
+	addFilterFlags(cmd, &opts.tags, &opts.where)
 
 	cmd.Flags().SortFlags = false
 
 	return cmd
 }
 
-func plotBenchmarks(ctx context.Context, db database.Store, id, outputDir string) error {
-	benchmarks, err := db.FetchByGroupIDs(ctx, []string{id})
+// writeTempCSV exports the given data to a temporary CSV file. The caller is responsible for removing the file.
+func writeTempCSV(data any) (string, error) {
+	file, err := os.CreateTemp("", build.AppName+"-*.csv")
+	if err != nil {
+		return "", fmt.Errorf("create temp file: %w", err)
+	}
+	defer file.Close()
+
+	if err := exporter.ToCSV(file, data); err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("export to CSV: %w", err)
+	}
+
+	return file.Name(), nil
+}
+
+func plotBenchmarks(ctx context.Context, db database.Store, id, outputDir string, options ...database.QueryOption) error {
+	// The samples are plotted over time
+	benchmarks, err := db.FetchByGroupIDs(ctx, []string{id}, append(options, database.WithSampleSeries())...)
 	if err != nil {
 		return fmt.Errorf("fetch benchmarks by benchmark-group ID: %w", err)
 	}
@@ -110,31 +165,52 @@ func plotBenchmarks(ctx context.Context, db database.Store, id, outputDir string
 		return fmt.Errorf("no benchmarks found for benchmark-group %q", id)
 	}
 
-	// Create temp file for CSV data
-	file, err := os.CreateTemp("", build.AppName+"-*.csv")
+	// Convert benchmarks to CSV export format and save them to a temp file
+	fileName, err := writeTempCSV(converter.BenchmarksToCSV(benchmarks))
 	if err != nil {
-		return fmt.Errorf("create temp file: %w", err)
+		return fmt.Errorf("export benchmarks: %w", err)
 	}
+	defer func() { _ = os.Remove(fileName) }()
 
-	// Save temp file name for plotting and cleanup
-	fileName := file.Name()
-	defer func() {
-		_ = file.Close()
-		_ = os.Remove(fileName)
-	}()
+	// Generate plots using gnuplot
+	if err := plot.Plot(ctx, fileName, outputDir); err != nil {
+		return fmt.Errorf("plot benchmarks: %w", err)
+	}
 
-	// Convert benchmarks to CSV export format
-	exportable := converter.BenchmarksToCSV(benchmarks)
+	// Plot the system metric samples of each benchmark over time. Benchmarks that were recorded before we persisted
+	// the samples don't have any.
+	for _, benchmark := range benchmarks {
+		if len(benchmark.Edges.SystemMetricSamples) == 0 {
+			continue
+		}
 
-	// Export benchmarks to CSV
-	if err := exporter.ToCSV(file, exportable); err != nil {
-		return fmt.Errorf("export benchmarks to CSV: %w", err)
+		if err := plotTimeSeries(ctx, benchmark, outputDir); err != nil {
+			return fmt.Errorf("plot time series of benchmark %q: %w", benchmark.ID, err)
+		}
 	}
 
-	// Generate plots using gnuplot
-	if err := plot.Plot(ctx, fileName, outputDir); err != nil {
-		return fmt.Errorf("plot benchmarks: %w", err)
+	return nil
+}
+
+func plotTimeSeries(ctx context.Context, benchmark *models.Benchmark, outputDir string) error {
+	fileName, err := writeTempCSV(converter.SystemMetricSamplesToCSV([]*models.Benchmark{benchmark}))
+	if err != nil {
+		return fmt.Errorf("export system metric samples: %w", err)
+	}
+	defer func() { _ = os.Remove(fileName) }()
+
+	title := fmt.Sprintf("%d Clients (%s)", benchmark.Clients, benchmark.ID)
+	if err := plot.PlotTimeSeries(ctx, fileName, outputDir, benchmark.ID.String(), title); err != nil {
+		return fmt.Errorf("plot system metric samples: %w", err)
 	}
 
 	return nil
 }
+
+var plotLongDesc = `Plot the results of the given benchmark-groups using gnuplot. Benchmark-groups
+are given by their name or ID.
+
+Instead of listing the benchmark-groups explicitly, you can select them by their
+tags with the '--tag' flag or by an expression with the '--where' flag. Given
+both, only the benchmarks of the listed groups that match the filters are
+plotted.`
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlotRequiresGroupsOrFilters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		args      []string
+		flags     map[string]string
+		expectErr bool
+	}{
+		{name: "nothing", expectErr: true},
+		{name: "group", args: []string{"nightly"}},
+		{name: "tag", flags: map[string]string{"tag": "env=staging"}},
+		{name: "where", flags: map[string]string{"where": "clients>=32"}},
+	}
+
+	for _, tc := range tests {
+		tc := tc // capture range variable
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cmd := newPlotCommand(&globalOptions{}, nil)
+			for name, value := range tc.flags {
+				if !assert.NoError(t, cmd.Flags().Set(name, value)) {
+					return
+				}
+			}
+
+			err := cmd.Args(cmd, tc.args)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,17 +1,21 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
-	"go.jetpack.io/typeid"
 
+	"github.com/nikoksr/dbench/internal/database"
 	"github.com/nikoksr/dbench/internal/fs"
 	"github.com/nikoksr/dbench/internal/ui/printer"
 )
 
 type removeOptions struct {
 	*globalOptions
+
+	tags  []string
+	where string
 }
 
 func newRemoveCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
@@ -20,54 +24,79 @@ func newRemoveCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 	}
 
 	cmd := &cobra.Command{
-		Use:                   "remove ID [ID...]",
+		Use:                   "remove [OPTIONS] [ID|BENCHMARK-GROUP...]",
 		Aliases:               []string{"r", "rm"},
 		GroupID:               "commands",
 		Short:                 "Remove benchmarks from the database",
 		SilenceUsage:          true,
 		SilenceErrors:         true,
 		DisableFlagsInUseLine: true,
-		Args:                  cobra.MinimumNArgs(1),
-		ValidArgsFunction:     cobra.NoFileCompletions,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && len(opts.tags) == 0 && opts.where == "" {
+				return fmt.Errorf("requires at least one ID, --tag or --where filter")
+			}
+			return nil
+		},
+		ValidArgsFunction: cobra.NoFileCompletions,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
 			if err != nil {
 				return fmt.Errorf("connect to database: %w", err)
 			}
 
+			filters, err := filterOptions(opts.tags, opts.where)
+			if err != nil {
+				return err
+			}
+
 			// Print header
 			p := printer.NewPrinter(cmd.OutOrStdout(), 60)
 			p.PrintlnTitle("Remove")
 
-			// Convert and validate ids
+			// Sort the arguments into benchmarks and benchmark-groups
 			p.PrintlnSubTitle("Validation")
 
-			var ids, groupIDs []string
-			for _, arg := range args {
-				// Try to convert id to typeid
-				p.PrintInfo(fmt.Sprintf(" Validating ID %s ... ", arg), printer.WithIndent())
+			ctx := cmd.Context()
+
+			ids, groups := splitBenchmarkArgs(args)
+
+			var groupIDs []string
+			if len(groups) > 0 {
+				p.PrintInfo(fmt.Sprintf(" Resolving %d benchmark-group(s) ... ", len(groups)), printer.WithIndent())
 
-				id, err := typeid.FromString(arg)
+				groupIDs, err = db.ResolveGroupIDs(ctx, groups)
 				if err != nil {
 					p.PrintlnError(err.Error())
-					return fmt.Errorf("convert id to typeid: %w", err)
+					return fmt.Errorf("resolve benchmark-groups: %w", err)
 				}
 
 				p.PrintlnSuccess("")
+			}
+
+			// Narrow the selection down to the benchmarks that match the filters
+			if len(filters) > 0 {
+				p.PrintInfo(" Selecting benchmarks by tags ... ", printer.WithIndent())
+
+				ids, err = selectBenchmarkIDs(ctx, db, ids, groupIDs, filters)
+				if err != nil {
+					p.PrintlnError(err.Error())
+					return fmt.Errorf("select benchmarks by tags: %w", err)
+				}
 
-				if id.Prefix() == "bmkgrp" {
-					groupIDs = append(groupIDs, id.String())
-				} else {
-					ids = append(ids, id.String())
+				groupIDs = nil
+				if len(ids) == 0 {
+					p.PrintlnWarning("no benchmarks found")
+					p.Spacer(2)
+					return nil
 				}
-			}
 
-			// Remove benchmark groups
-			ctx := cmd.Context()
+				p.PrintlnSuccess(fmt.Sprintf("%d found", len(ids)))
+			}
 
 			p.Spacer(2)
 			p.PrintlnSubTitle("Removing")
 
+			// Remove benchmark groups
 			if len(groupIDs) > 0 {
 				p.PrintInfo(fmt.Sprintf(" Removing %d benchmark-group(s)", len(groupIDs)), printer.WithIndent())
 
@@ -98,5 +127,17 @@ func newRemoveCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra
 		},
 	}
 
+	addFilterFlags(cmd, &opts.tags, &opts.where)
+
 	return cmd
 }
+
+// selectBenchmarkIDs returns the IDs of the benchmarks that match the given filters. If any IDs or group IDs are given,
+// only the benchmarks among them are selected.
+func selectBenchmarkIDs(ctx context.Context, db database.Store, ids, groupIDs []string, filters []database.QueryOption) ([]string, error) {
+	if len(ids) > 0 || len(groupIDs) > 0 {
+		filters = append(filters, database.WithSelection(ids, groupIDs))
+	}
+
+	return db.FetchIDs(ctx, filters...)
+}
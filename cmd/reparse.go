@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/benchmarkoutput"
+	pgbench "github.com/nikoksr/dbench/internal/benchmark"
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+	"github.com/nikoksr/dbench/internal/ui/text"
+)
+
+// reparseBatchSize is the number of outputs that are loaded at once. Outputs are rather large, so we keep it small.
+const reparseBatchSize = 100
+
+type reparseOptions struct {
+	*globalOptions
+
+	all   bool
+	tags  []string
+	where string
+}
+
+func newReparseCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	opts := &reparseOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "reparse [OPTIONS] [BENCHMARK-GROUP...]",
+		GroupID:               "commands",
+		Short:                 "Re-parse the stored pgbench output of benchmarks",
+		Long:                  reparseLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
+			if err != nil {
+				return fmt.Errorf("connect to database: %w", err)
+			}
+
+			filters, err := filterOptions(opts.tags, opts.where)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			groupIDs, err := db.ResolveGroupIDs(ctx, args)
+			if err != nil {
+				return fmt.Errorf("resolve benchmark-groups: %w", err)
+			}
+
+			// Only benchmarks with a stored output can be re-parsed. Unless asked otherwise, we skip the ones that were
+			// already parsed by the current parser.
+			outputFilter := benchmark.HasOutput()
+			if !opts.all {
+				outputFilter = benchmark.HasOutputWith(benchmarkoutput.ParserVersionLT(pgbench.ParserVersion))
+			}
+			filters = append(filters, database.WithFilter(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+				return query.Where(outputFilter)
+			}))
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Reparse")
+			p.PrintlnSubTitle("Preparation")
+
+			p.PrintInfo(" Finding benchmarks ... ", printer.WithIndent())
+
+			ids, err := selectBenchmarkIDs(ctx, db, nil, groupIDs, filters)
+			if err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("select benchmarks: %w", err)
+			}
+
+			if len(ids) == 0 {
+				p.PrintlnWarning("no benchmarks to re-parse found")
+				p.Spacer(2)
+				return nil
+			}
+
+			p.PrintlnSuccess(fmt.Sprintf("%d found", len(ids)))
+			p.Spacer(2)
+
+			p.PrintlnSubTitle("Reparsing")
+
+			var (
+				updated int
+				errs    []error
+			)
+
+			for start := 0; start < len(ids); start += reparseBatchSize {
+				batch := ids[start:min(start+reparseBatchSize, len(ids))]
+
+				p.PrintInfo(fmt.Sprintf(" Reparsing (%d-%d) ... ", start+1, start+len(batch)), printer.WithIndent())
+
+				outputs, err := db.FetchOutputs(ctx, batch)
+				if err != nil {
+					p.PrintlnError(err.Error())
+					return fmt.Errorf("fetch benchmark outputs: %w", err)
+				}
+
+				// A benchmark whose output can't be parsed doesn't stop the others from being updated
+				for _, output := range outputs {
+					parsed, err := pgbench.Reparse(output)
+					if err == nil {
+						err = db.UpdateParsed(ctx, output.BenchmarkID.String(), parsed, pgbench.ParserVersion)
+					}
+					if err != nil {
+						errs = append(errs, fmt.Errorf("benchmark %s: %w", output.BenchmarkID, err))
+						continue
+					}
+
+					updated++
+				}
+
+				p.PrintlnSuccess("")
+			}
+
+			p.Spacer(2)
+			p.PrintlnText(fmt.Sprintf(" Complete! Updated %d of %d benchmark(s).", updated, len(ids)))
+
+			if len(errs) > 0 {
+				p.Spacer(1)
+				p.PrintlnText(text.WarningsList(errs))
+				p.Spacer(1)
+				return fmt.Errorf("failed to re-parse %d benchmark(s)", len(errs))
+			}
+
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&opts.all, "all", "a", false, "Also re-parse benchmarks that were already parsed by the current parser")
+	addFilterFlags(cmd, &opts.tags, &opts.where)
+
+	cmd.Flags().SortFlags = false
+
+	return cmd
+}
+
+var reparseLongDesc = `Re-parse the raw pgbench output that is stored with each benchmark and update
+its results. This is useful after an update of dbench improved its parser, e.g.
+to fix a parsing error or to support a new field of the pgbench output.
+
+By default, all benchmarks of the given benchmark-groups are re-parsed that were
+parsed by an older version of the parser. Without any benchmark-groups, all
+benchmarks are considered. Benchmarks that were recorded before the output was
+stored can't be re-parsed.`
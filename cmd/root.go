@@ -41,21 +41,31 @@ func printMigrationWarning(w io.Writer) {
 // database connection. We return the database.Store interface instead of the database.DB struct to avoid accidentally
 // calling the Close() method on the database connection in a subcommand. The Close() method is only called once after
 // all subcommands have finished by the root's PersistentPostRunE hook.
+//
+// By default, the store is a SQLite database in the data directory. If a store DSN is given, the store is the shared
+// PostgreSQL database it points to instead.
 type dbConnector func(ctx context.Context, dataDir string, noMigration bool, fs fs.FileSystem) (database.Store, error)
 
-func newDBConnector(db *database.DB) dbConnector {
+func newDBConnector(db *database.DB, opts *globalOptions) dbConnector {
 	connector := func(ctx context.Context, dataDir string, noMigration bool, fs fs.FileSystem) (database.Store, error) {
-		if dataDir == "" {
-			return nil, fmt.Errorf("path to data directory is empty")
-		}
+		// Set database DSN
+		dbenchDSN := opts.storeDSN
+		if dbenchDSN != "" {
+			if err := database.ValidatePostgresDSN(dbenchDSN); err != nil {
+				return nil, fmt.Errorf("invalid store DSN: %w", err)
+			}
+		} else {
+			if dataDir == "" {
+				return nil, fmt.Errorf("path to data directory is empty")
+			}
 
-		// Make sure the data directory exists and create it if not
-		if err := fs.MkdirAll(dataDir, 0o755); err != nil {
-			return nil, fmt.Errorf("create data directory: %w", err)
-		}
+			// Make sure the data directory exists and create it if not
+			if err := fs.MkdirAll(dataDir, 0o755); err != nil {
+				return nil, fmt.Errorf("create data directory: %w", err)
+			}
 
-		// Set database DSN
-		dbenchDSN := getDefaultDSN(dataDir)
+			dbenchDSN = getDefaultDSN(dataDir)
+		}
 
 		// Open database connection
 		if _, err := db.Connect(ctx, dbenchDSN); err != nil {
@@ -74,8 +84,13 @@ func newDBConnector(db *database.DB) dbConnector {
 
 		if shouldMigrate {
 			printMigrationWarning(os.Stdout)
-			if err := db.AutoMigrate(ctx, build.Version); err != nil {
-				return nil, err
+
+			backupPath, err := db.AutoMigrate(ctx, build.Version)
+			if backupPath != "" {
+				printer.NewPrinter(os.Stdout).PrintlnHint(fmt.Sprintf("Backed up the database to %s before migrating it.", backupPath), printer.WithParagraphMode())
+			}
+			if err != nil {
+				return nil, fmt.Errorf("migrate database: %w", err)
 			}
 		}
 
@@ -87,8 +102,9 @@ func newDBConnector(db *database.DB) dbConnector {
 
 var (
 	// envPrefix is the prefix for environment variables. E.g. DBENCH_DATA_DIR
-	envPrefix  = strings.ToUpper(build.AppName)
-	envDataDir = envPrefix + "_DATA_DIR"
+	envPrefix   = strings.ToUpper(build.AppName)
+	envDataDir  = envPrefix + "_DATA_DIR"
+	envStoreDSN = envPrefix + "_STORE_DSN"
 )
 
 // determineDefaultDataPath function to return data path based on operating system.
@@ -119,6 +135,7 @@ func determineDefaultDataPath(appName string, env env.Environment, fs fs.FileSys
 
 type globalOptions struct {
 	dataDir     string
+	storeDSN    string
 	noMigration bool
 }
 
@@ -127,7 +144,7 @@ func newRootCommand() *cobra.Command {
 
 	// Create the application wide database connector function
 	db := database.New()
-	dbConnector := newDBConnector(db)
+	dbConnector := newDBConnector(db, opts)
 
 	cmd := &cobra.Command{
 		Use:                   build.AppName + " [COMMAND]",
@@ -159,6 +176,7 @@ func newRootCommand() *cobra.Command {
 
 	// Flags
 	cmd.PersistentFlags().StringVar(&opts.dataDir, "data-dir", dataDir, "Path to the data directory")
+	cmd.PersistentFlags().StringVar(&opts.storeDSN, "store-dsn", env.RealEnvironment{}.Getenv(envStoreDSN), "DSN of a shared PostgreSQL database to store results in instead of the local database (env "+envStoreDSN+")")
 	cmd.PersistentFlags().BoolVar(&opts.noMigration, "no-migration", false, "Disable automatic database migration")
 
 	_ = cmd.PersistentFlags().MarkHidden("no-migration")
@@ -171,11 +189,16 @@ func newRootCommand() *cobra.Command {
 		newListCommand(opts, dbConnector),
 		newExportCommand(opts, dbConnector),
 		newImportCommand(opts, dbConnector),
+		newMergeCommand(opts, dbConnector),
 		newRemoveCommand(opts, dbConnector),
+		newTagCommand(opts, dbConnector),
+		newReparseCommand(opts, dbConnector),
 		// Plotting
 		newPlotCommand(opts, dbConnector),
 		// Misc
 		newDoctorCommand(opts, dbConnector),
+		newMigrateCommand(opts, dbConnector),
+		newDBCommand(opts),
 	)
 
 	return cmd
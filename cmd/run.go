@@ -1,17 +1,23 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.jetpack.io/typeid"
 
 	"github.com/nikoksr/dbench/cmd/cobrax"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
 	"github.com/nikoksr/dbench/ent/schema/duration"
 	"github.com/nikoksr/dbench/ent/schema/pulid"
 	"github.com/nikoksr/dbench/internal/benchmark"
 	"github.com/nikoksr/dbench/internal/build"
+	"github.com/nikoksr/dbench/internal/collector"
+	"github.com/nikoksr/dbench/internal/database"
 	"github.com/nikoksr/dbench/internal/events"
 	"github.com/nikoksr/dbench/internal/fs"
 	"github.com/nikoksr/dbench/internal/models"
@@ -25,6 +31,9 @@ type runOptions struct {
 
 	benchConfig         models.BenchmarkConfig
 	clients             []int
+	tags                []string
+	name                string
+	description         string
 	collectSystemConfig bool
 }
 
@@ -45,6 +54,16 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 		ValidArgsFunction:     cobra.NoFileCompletions,
 		PreRunE:               cobrax.HooksE(pgbenchInstalledHook()),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := collector.Validate(opts.benchConfig.Collectors...); err != nil {
+				return err
+			}
+
+			tags, err := models.ParseTags(opts.tags)
+			if err != nil {
+				return err
+			}
+			opts.benchConfig.Tags = tags
+
 			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
 			if err != nil {
 				return fmt.Errorf("connect to database: %w", err)
@@ -87,7 +106,7 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 				p.PrintInfo(" Collecting system info ... ", printer.WithIndent())
 
 				var errs []error
-				systemConfig, errs = system.GetConfig()
+				systemConfig, errs = system.GetConfig(opts.benchConfig.PGDataDir)
 
 				// Errors are not fatal, but we still want to inform the user
 				if len(errs) > 0 {
@@ -99,49 +118,49 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 				}
 			}
 
-			// Subscribe to events of the benchmark run
-			events.Subscribe(func(event events.Event) {
-				switch event.Type {
-				case benchmark.RunCommandRunning:
-					p.PrintInfo(fmt.Sprintf(" Executing %s ... ", event.Message))
-				}
-			})
-
-			// Calculate estimated runtime
-			estimatedRuntime := time.Duration(len(opts.clients)) * time.Second * 5 // 5 seconds per set. Clean this up, works for now.
+			// The snapshot has to describe the config the benchmarks run with, e.g. including implied collectors
+			opts.benchConfig.Sanitize()
 
-			p.Spacer(2)
-			p.PrintlnSubTitle("Running benchmarks")
-			p.PrintlnHint(fmt.Sprintf("  Starting! Estimated total runtime %s", estimatedRuntime.String()))
-			p.Spacer(1)
+			// Create the benchmark group up front, so that it's marked as failed or canceled if the run doesn't complete
+			group := &models.BenchmarkGroup{
+				ID:        pulid.ID(benchmarkGroupID.String()),
+				Config:    opts.benchConfig.Snapshot(opts.clients, opts.collectSystemConfig),
+				StartedAt: time.Now().UTC(),
+			}
+			if opts.name != "" {
+				group.Name = &opts.name
+			}
+			if opts.description != "" {
+				group.Description = &opts.description
+			}
 
-			for _, numClients := range opts.clients {
-				// Create benchmark configuration
-				opts.benchConfig.NumClients = numClients
+			group, err = db.CreateGroup(ctx, group)
+			if err != nil {
+				return fmt.Errorf("create benchmark group: %w", err)
+			}
 
-				// Run benchmark
-				benchStart := time.Now()
-				bench, err := benchmark.Run(ctx, &opts.benchConfig)
-				benchRuntime := time.Since(benchStart)
+			runErr := runBenchmarks(ctx, opts, p, db, group.ID, systemConfig)
 
-				if err != nil {
-					p.PrintlnError(err.Error())
-					return fmt.Errorf("run benchmark: %w", err)
+			status := benchmarkgroup.StatusCompleted
+			if runErr != nil {
+				status = benchmarkgroup.StatusFailed
+				if ctx.Err() != nil {
+					status = benchmarkgroup.StatusCanceled
 				}
+			}
 
-				p.PrintlnSuccess("")
-
-				// Set some meta benchmark values
-				bench.Edges.System = systemConfig
-				bench.Edges.Result.TotalRuntime = duration.Duration(benchRuntime)
-				bench.GroupID = pulid.ID(benchmarkGroupID.String())
-				bench.RecordedAt = time.Now().UTC()
+			// The context may already be canceled, so don't use it to record the outcome
+			if err := db.FinishGroup(context.WithoutCancel(ctx), group.ID.String(), status); err != nil {
+				return errors.Join(runErr, fmt.Errorf("finish benchmark group: %w", err))
+			}
+			if runErr != nil {
+				return runErr
+			}
 
-				// Save benchmark to database
-				_, err = db.Save(ctx, bench)
-				if err != nil {
-					return fmt.Errorf("save benchmark: %w", err)
-				}
+			// Refer to the group by its name if it has one
+			groupRef := group.ID.String()
+			if group.Name != nil {
+				groupRef = *group.Name
 			}
 
 			// Print benchmark complete message
@@ -149,7 +168,7 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 			p.PrintlnText(" Complete! Run the following command to plot the results:")
 			p.Spacer(1)
 			p.PrintInfo(fmt.Sprintf("   $ %s plot ", build.AppName))
-			p.PrintlnHighlight(benchmarkGroupID.String())
+			p.PrintlnHighlight(groupRef)
 			p.Spacer(2)
 
 			return nil
@@ -164,10 +183,17 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 
 	// Benchmark flags
 	cmd.Flags().StringVar(&opts.benchConfig.Mode, "mode", models.ModeSimple, "Benchmarking mode (simple, thorough)")
+	cmd.Flags().StringVarP(&opts.name, "name", "n", "", "Unique name of the benchmark-group, can be used instead of its ID in other commands")
+	cmd.Flags().StringVar(&opts.description, "description", "", "Description of the benchmark-group")
 	cmd.Flags().StringVarP(&opts.benchConfig.Comment, "comment", "c", "", "Comment to add some optional information to the benchmark")
+	cmd.Flags().StringSliceVarP(&opts.tags, "tag", "t", nil, "Key-value tags to label the benchmarks with, e.g. env=staging,pg=16.1")
 	cmd.Flags().IntVar(&opts.benchConfig.NumThreads, "threads", 1, "Number of threads to use")
 	cmd.Flags().IntSliceVar(&opts.clients, "clients", []int{1, 2, 4, 8, 16, 32, 64, 128, 256}, "List of number of clients to benchmark with")
 	cmd.Flags().BoolVar(&opts.collectSystemConfig, "collect-sysinfo", false, "Opt-in to collect detailed system specifications (CPU, RAM, etc.) for benchmark analysis. See help for more")
+	cmd.Flags().StringSliceVar(&opts.benchConfig.Collectors, "collect", []string{collector.NameHost, collector.NameProcess}, "Metric collectors to enable ("+strings.Join(collector.Names, ", ")+")")
+	cmd.Flags().StringVar(&opts.benchConfig.PGDataDir, "pg-data-dir", "", "Data directory of the local PostgreSQL server; used to find its process for process metrics and to inspect its filesystem and disk")
+	cmd.Flags().StringVar(&opts.benchConfig.PGProcessName, "pg-process-name", "postgres", "Process name of the local PostgreSQL server; used if --pg-data-dir is not set")
+	cmd.Flags().DurationVar(&opts.benchConfig.WaitEventInterval, "wait-event-interval", 0, "Interval for sampling wait events of the pgbench backends; implies --collect postgres (0 to use the sampling rate of the mode)")
 
 	cmd.Flags().SortFlags = false
 
@@ -176,7 +202,7 @@ func newRunCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Co
 
 var runLongDesc = `Run a series of benchmarks against a PostgreSQL database. This
 tool provides various options to customize the benchmarking process, including
-client count, threading, and optional comments.
+client count, threading, and optional comments and tags.
 
 The '--collect-sysinfo' flag allows users to opt-in to collect detailed system
 specifications such as CPU model, RAM size, etc., which are crucial for a
@@ -184,6 +210,23 @@ comprehensive analysis of the benchmark results. This data collection is entirel
 optional and is designed to enhance the context and accuracy of performance metrics.
 No data is transmitted externally, ensuring your privacy and data security.
 
+While pgbench is running, dbench samples metrics through a set of collectors,
+which are enabled with the '--collect' flag:
+
+  host      CPU, memory, load average, swap, disk and network IO of the system
+  process   CPU, memory, IO and context switches of the PostgreSQL server and
+            the pgbench process separately. The server is found by the PID file
+            in '--pg-data-dir' or by its process name. If several servers run
+            on this machine, '--pg-data-dir' is required to pick one. If the
+            server runs on a different machine, only pgbench is monitored.
+  postgres  Wait events of the pgbench backends from pg_stat_activity. dbench
+            opens an additional connection to the target database and records
+            how often the backends were found in each wait event. The resulting
+            wait event mix shows whether lock, IO or LWLock contention dominates
+            as concurrency rises. Setting '--wait-event-interval' enables it.
+
+The host and process collectors are enabled by default.
+
 By understanding the system's specifications, users can gain better insights into
 how different hardware configurations impact database performance. This feature is
 particularly useful for those looking to optimize database settings or evaluate
@@ -192,3 +235,53 @@ performance across various hardware setups.
 Use this command to initiate the benchmarking process with options tailored to your
 requirements. For detailed information on each flag and its usage, refer to the
 options listed below.`
+
+// runBenchmarks runs a benchmark for each of the configured numbers of clients and saves them to the given group.
+func runBenchmarks(ctx context.Context, opts *runOptions, p *printer.Printer, db database.Store, groupID pulid.ID, systemConfig *models.SystemConfig) error {
+	// Subscribe to events of the benchmark run
+	events.Subscribe(func(event events.Event) {
+		switch event.Type {
+		case benchmark.RunCommandRunning:
+			p.PrintInfo(fmt.Sprintf(" Executing %s ... ", event.Message))
+		}
+	})
+
+	// Calculate estimated runtime
+	estimatedRuntime := time.Duration(len(opts.clients)) * time.Second * 5 // 5 seconds per set. Clean this up, works for now.
+
+	p.Spacer(2)
+	p.PrintlnSubTitle("Running benchmarks")
+	p.PrintlnHint(fmt.Sprintf("  Starting! Estimated total runtime %s", estimatedRuntime.String()))
+	p.Spacer(1)
+
+	for _, numClients := range opts.clients {
+		// Create benchmark configuration
+		opts.benchConfig.NumClients = numClients
+
+		// Run benchmark
+		benchStart := time.Now()
+		bench, err := benchmark.Run(ctx, &opts.benchConfig)
+		benchRuntime := time.Since(benchStart)
+
+		if err != nil {
+			p.PrintlnError(err.Error())
+			return fmt.Errorf("run benchmark: %w", err)
+		}
+
+		p.PrintlnSuccess("")
+
+		// Set some meta benchmark values
+		bench.Edges.System = systemConfig
+		bench.Edges.Result.TotalRuntime = duration.Duration(benchRuntime)
+		bench.GroupID = groupID
+		bench.RecordedAt = time.Now().UTC()
+
+		// Save benchmark to database
+		_, err = db.Save(ctx, bench)
+		if err != nil {
+			return fmt.Errorf("save benchmark: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nikoksr/dbench/internal/fs"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/ui/printer"
+)
+
+type tagOptions struct {
+	*globalOptions
+
+	removeKeys []string
+}
+
+func newTagCommand(globalOpts *globalOptions, connectToDB dbConnector) *cobra.Command {
+	opts := &tagOptions{
+		globalOptions: globalOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:                   "tag [OPTIONS] BENCHMARK-GROUP [BENCHMARK-GROUP...] [KEY=VALUE...]",
+		GroupID:               "commands",
+		Short:                 "Set or remove tags of benchmark-groups",
+		Long:                  tagLongDesc,
+		SilenceUsage:          true,
+		SilenceErrors:         true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.MinimumNArgs(1),
+		ValidArgsFunction:     cobra.NoFileCompletions,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Split the arguments into groups and tags
+			var groups, rawTags []string
+			for _, arg := range args {
+				if strings.Contains(arg, "=") {
+					rawTags = append(rawTags, arg)
+					continue
+				}
+
+				groups = append(groups, arg)
+			}
+
+			tags, err := models.ParseTags(rawTags)
+			if err != nil {
+				return err
+			}
+
+			if len(groups) == 0 {
+				return fmt.Errorf("no benchmark-group provided")
+			}
+			if len(tags) == 0 && len(opts.removeKeys) == 0 {
+				return fmt.Errorf("no tags to set or remove provided")
+			}
+
+			db, err := connectToDB(cmd.Context(), opts.dataDir, opts.noMigration, fs.OSFileSystem{})
+			if err != nil {
+				return fmt.Errorf("connect to database: %w", err)
+			}
+
+			groupIDs, err := db.ResolveGroupIDs(cmd.Context(), groups)
+			if err != nil {
+				return fmt.Errorf("resolve benchmark-groups: %w", err)
+			}
+
+			// Print header
+			p := printer.NewPrinter(cmd.OutOrStdout(), 50)
+			p.PrintlnTitle("Tag")
+
+			p.PrintInfo(fmt.Sprintf(" Tagging %d benchmark-group(s) ... ", len(groupIDs)), printer.WithIndent())
+
+			if err := db.TagByGroupIDs(cmd.Context(), groupIDs, tags, opts.removeKeys); err != nil {
+				p.PrintlnError(err.Error())
+				return fmt.Errorf("tag benchmark-groups: %w", err)
+			}
+
+			p.PrintlnSuccess("")
+			p.Spacer(2)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&opts.removeKeys, "remove", "r", nil, "Keys of the tags to remove")
+
+	cmd.Flags().SortFlags = false
+
+	return cmd
+}
+
+var tagLongDesc = `Set or remove the key-value tags of all benchmarks in the given
+benchmark-groups.
+
+Benchmark-groups are given by their name or ID, tags as KEY=VALUE arguments
+after them. A benchmark holds at most one value per key, so setting a tag
+replaces the current value of its key. Tags can be used to filter the
+benchmarks of the list, plot, export and remove commands with the '--tag' flag.
+
+Examples:
+
+  # Label a benchmark-group
+  $ dbench tag bmkgrp_01h... env=staging pg=16.1
+
+  # Label a named benchmark-group
+  $ dbench tag nightly-2024-01-31 env=staging
+
+  # Change the value of a tag and remove another one
+  $ dbench tag bmkgrp_01h... env=production --remove pg`
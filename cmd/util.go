@@ -1,14 +1,24 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"strings"
+	"time"
 
+	"github.com/spf13/cobra"
+	"go.jetpack.io/typeid"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/internal/database"
+	"github.com/nikoksr/dbench/internal/models"
 	"github.com/nikoksr/dbench/internal/ui"
 	"github.com/nikoksr/dbench/internal/ui/printer"
+	"github.com/nikoksr/dbench/internal/where"
 )
 
 var (
@@ -118,3 +128,99 @@ func sanitizeBatchSize(batchSize int) int {
 
 	return batchSize
 }
+
+// addFilterFlags adds the --tag and --where flags, which are shared by all commands that select benchmarks.
+func addFilterFlags(cmd *cobra.Command, tagFilters *[]string, whereExpr *string) {
+	cmd.Flags().StringSliceVarP(tagFilters, "tag", "t", nil, "Only select benchmarks with the given tags (key=value, or key to match any value)")
+	cmd.Flags().StringVarP(whereExpr, "where", "w", "", "Only select benchmarks matching the expression (e.g. \"clients>=32 and tps>1000\")")
+}
+
+// filterOptions converts the values of the --tag and --where flags into query options. It returns no options if no
+// filters are given.
+func filterOptions(tagFilters []string, whereExpr string) ([]database.QueryOption, error) {
+	var opts []database.QueryOption
+
+	if len(tagFilters) > 0 {
+		tags, err := models.ParseTagFilters(tagFilters)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, database.WithTags(tags...))
+	}
+
+	if strings.TrimSpace(whereExpr) != "" {
+		node, err := where.Parse(whereExpr)
+		if err != nil {
+			return nil, fmt.Errorf("parse --where: %w", err)
+		}
+
+		opts = append(opts, database.WithWhere(node))
+	}
+
+	return opts, nil
+}
+
+// parseTimeFlag parses the value of a flag that takes a date or a date and time. Times without a zone are taken as
+// local time. It reports whether the value was a date only.
+func parseTimeFlag(flag, value string) (time.Time, bool, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", time.DateTime, time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, layout == time.DateOnly, nil
+		}
+	}
+
+	return time.Time{}, false, fmt.Errorf("invalid --%s time %q, expected a date like 2024-01-31 or a time like 2024-01-31T15:04:05", flag, value)
+}
+
+// splitBenchmarkArgs sorts the given arguments into benchmark IDs and benchmark-groups. Arguments that aren't benchmark
+// IDs are taken as benchmark-group names or IDs.
+func splitBenchmarkArgs(args []string) (ids, groups []string) {
+	for _, arg := range args {
+		if id, err := typeid.FromString(arg); err == nil && id.Prefix() == "bmk" {
+			ids = append(ids, id.String())
+		} else {
+			groups = append(groups, arg)
+		}
+	}
+
+	return ids, groups
+}
+
+// fetchPages fetches a range of the selected benchmarks in pages and passes each page to fn, so that only a single page
+// of benchmarks is held in memory at a time. It returns the number of fetched benchmarks.
+func fetchPages(ctx context.Context, db database.Store, filters []database.QueryOption, offset, limit int, fn func([]*models.Benchmark) error) (int, error) {
+	records := 0
+	for records < limit {
+		pageSize := min(exportPageSize, limit-records)
+
+		// The order function we're using here is the reverse of our default order as seen in [internal/database/database.go].
+		// When we're, for example, running `dbench ls`, we want to see the latest benchmarks first. When we're copying
+		// benchmarks, e.g. to export them, we want the oldest benchmarks first, so that they keep their order.
+		benchmarks, err := db.Fetch(ctx, append(filters,
+			database.WithOrderBy(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+				return query.Order(
+					ent.Asc(benchmark.FieldGroupID),
+					ent.Asc(benchmark.FieldID),
+				)
+			}),
+			database.WithLimit(pageSize),
+			database.WithOffset(offset+records),
+		)...)
+		if err != nil {
+			return records, fmt.Errorf("fetch benchmarks: %w", err)
+		}
+
+		if len(benchmarks) == 0 {
+			break
+		}
+
+		if err := fn(benchmarks); err != nil {
+			return records, err
+		}
+
+		records += len(benchmarks)
+	}
+
+	return records, nil
+}
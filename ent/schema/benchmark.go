@@ -34,28 +34,23 @@ func (BenchmarkMixin) Fields() []ent.Field {
 		field.String("comment").
 			Optional().
 			Nillable(),
-		// Remaining fields are optional and cannot be changed once set.
-		field.String("version").
-			Optional().
-			Immutable(),
 		field.String("command").
 			Optional().
 			Immutable(),
+		// Remaining fields are parsed from the pgbench output. They are optional and only change when the output is
+		// re-parsed.
+		field.String("version").
+			Optional(),
 		field.String("transaction_type").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Float("scaling_factor").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.String("query_mode").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Int("clients").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Int("threads").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Time("recorded_at").
 			Default(datetime.NowUTC).
 			Immutable(),
@@ -77,18 +72,46 @@ func (Benchmark) Mixin() []ent.Mixin {
 // Edges function defines the relations/edges of the Benchmark schema.
 func (Benchmark) Edges() []ent.Edge {
 	return []ent.Edge{
+		edge.From("group", BenchmarkGroup.Type).
+			Ref("benchmarks").
+			Field("group_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The group this benchmark was run in."),
 		edge.To("result", BenchmarkResult.Type).
 			Unique().
 			Annotations(entsql.OnDelete(entsql.Cascade)).
 			Comment("The result produced by the benchmark run."),
+		edge.To("output", BenchmarkOutput.Type).
+			Unique().
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The raw output of the pgbench invocation."),
 		edge.To("system_metric", SystemMetric.Type).
 			Unique().
 			Annotations(entsql.OnDelete(entsql.Cascade)).
 			Comment("The metrics that we collected from the system during the benchmark run."),
+		edge.To("system_metric_samples", SystemMetricSample.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The raw samples that the system metrics were aggregated from."),
 		edge.To("system", SystemConfig.Type).
 			Unique().
 			Annotations(entsql.OnDelete(entsql.Cascade)).
 			Comment("The system config that was used for the benchmark run."),
+		edge.To("wait_events", WaitEvent.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The wait events of the pgbench backends that we sampled during the benchmark run."),
+		edge.To("process_metrics", ProcessMetric.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The metrics of the PostgreSQL server and pgbench processes that we collected during the benchmark run."),
+		edge.To("disk_metrics", DiskMetric.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The IO metrics per block device that we collected during the benchmark run."),
+		edge.To("network_metrics", NetworkMetric.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The IO metrics per network interface that we collected during the benchmark run."),
+		edge.To("tags", Tag.Type).
+			Comment("The key-value tags the benchmark is labeled with."),
 	}
 }
 
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+	"go.jetpack.io/typeid"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/ent/schema/snapshot"
+)
+
+// BenchmarkGroup struct extends ent.Schema, defines the BenchmarkGroup table in the database.
+type BenchmarkGroup struct {
+	ent.Schema
+}
+
+// BenchmarkGroupMixin is a struct with embedded mixin.Schema.
+type BenchmarkGroupMixin struct {
+	mixin.Schema
+}
+
+// validateGroupName makes sure that a group name can't be mistaken for an ID, since commands accept both.
+func validateGroupName(name string) error {
+	if _, err := typeid.FromString(name); err == nil {
+		return fmt.Errorf("group name %q must not be a type ID", name)
+	}
+
+	return nil
+}
+
+// Fields method defines the fields within the BenchmarkGroup database table.
+func (BenchmarkGroupMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").
+			Optional().
+			Nillable().
+			Unique().
+			NotEmpty().
+			Validate(validateGroupName).
+			Comment("A unique, human-readable name that can be used instead of the ID."),
+		field.String("description").
+			Optional().
+			Nillable(),
+		field.JSON("config", &snapshot.RunConfig{}).
+			Optional().
+			Immutable().
+			Comment("A snapshot of the configuration the group was run with. Groups that were imported or recorded before groups were stored don't have one."),
+		field.Enum("status").
+			Values("running", "completed", "failed", "canceled").
+			Default("running").
+			Comment("The completion status of the run. Groups that were recorded before groups were stored are considered completed."),
+		field.Time("started_at").
+			Default(datetime.NowUTC).
+			Immutable(),
+		field.Time("finished_at").
+			Optional().
+			Nillable(),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the BenchmarkGroup schema.
+func (BenchmarkGroup) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "bmkgrp"),
+		// The BenchmarkGroup itself
+		BenchmarkGroupMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the BenchmarkGroup schema.
+func (BenchmarkGroup) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.To("benchmarks", Benchmark.Type).
+			Annotations(entsql.OnDelete(entsql.Cascade)).
+			Comment("The benchmarks that were run as part of this group, one per client count."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the BenchmarkGroup schema.
+func (BenchmarkGroup) Indexes() []ent.Index {
+	return []ent.Index{
+		// StartedAt index for faster sorting of default queries.
+		index.Fields("started_at"),
+	}
+}
+
+// Annotations function adds annotations to the BenchmarkGroup schema.
+func (BenchmarkGroup) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Benchmark groups represent a single dbench run, i.e. a series of pgbench runs with different client counts. It is a one-to-many relation to the benchmark table."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
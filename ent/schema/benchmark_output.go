@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// BenchmarkOutput struct extends ent.Schema, defines the BenchmarkOutput table in the database.
+type BenchmarkOutput struct {
+	ent.Schema
+}
+
+// BenchmarkOutputMixin is a struct with embedded mixin.Schema.
+type BenchmarkOutputMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the BenchmarkOutput database table.
+func (BenchmarkOutputMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this output belongs to.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable().
+			Unique(),
+		field.Enum("compression").
+			Values("zstd").
+			Default("zstd").
+			Immutable().
+			Comment("The algorithm that stdout and stderr are compressed with."),
+		field.Bytes("stdout").
+			Immutable().
+			Comment("The compressed stdout of the pgbench invocation."),
+		field.Bytes("stderr").
+			Immutable().
+			Comment("The compressed stderr of the pgbench invocation, including its progress reports."),
+		field.Int("parser_version").
+			NonNegative().
+			Default(0).
+			Comment("The version of the parser that produced the current results of the benchmark."),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the BenchmarkOutput schema.
+func (BenchmarkOutput) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "bmkout"),
+		// The BenchmarkOutput itself
+		BenchmarkOutputMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the BenchmarkOutput schema.
+func (BenchmarkOutput) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("output").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark whose pgbench invocation produced this output."),
+	}
+}
+
+// Annotations function adds annotations to the BenchmarkOutput schema.
+func (BenchmarkOutput) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Benchmark outputs hold the raw, compressed output of the pgbench invocation of a benchmark, so that it can be audited and re-parsed later on. It is a one-to-one relation with the benchmark."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
@@ -27,15 +27,14 @@ type BenchmarkResultMixin struct {
 
 // newDurationField function for creating the duration field with different
 // schema type depending on the SQL dialect (SQLite, Postgres, MySQL).
-func newDurationField(name string) ent.Field {
+func newDurationField(name string) *field.OtherBuilder {
 	return field.Other(name, duration.Duration(0)).
 		SchemaType(map[string]string{
 			dialect.SQLite:   "BIGINT",
 			dialect.Postgres: "BIGINT",
 			dialect.MySQL:    "BIGINT",
 		}).
-		Optional().
-		Immutable()
+		Optional()
 }
 
 // Fields method that defines the fields within the BenchmarkResult database table.
@@ -46,19 +45,17 @@ func (BenchmarkResultMixin) Fields() []ent.Field {
 			GoType(pulid.ID("")).
 			Immutable().
 			Unique(),
-		// Other fields are optional and cannot be changed once set.
+		// Other fields are optional. The parsed ones only change when the output of the benchmark is re-parsed.
 		field.Int("transactions").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Int("failed_transactions").
-			Optional().
-			Immutable(),
+			Optional(),
 		field.Float("transactions_per_second").
-			Optional().
-			Immutable(),
+			Optional(),
 		newDurationField("average_latency"),
 		newDurationField("connection_time"),
-		newDurationField("total_runtime"),
+		newDurationField("total_runtime").
+			Immutable(),
 	}
 }
 
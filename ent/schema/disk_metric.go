@@ -0,0 +1,108 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// DiskMetric struct extends ent.Schema, defines the DiskMetric table in the database.
+type DiskMetric struct {
+	ent.Schema
+}
+
+// DiskMetricMixin is a struct with embedded mixin.Schema.
+type DiskMetricMixin struct {
+	mixin.Schema
+}
+
+// newRateField function creates a float field for per-second rates. Unlike newMetricField, rates can easily exceed the
+// precision of numeric(7, 2), e.g. a disk that reads 2 GiB/s, so we use double precision.
+func newRateField(name string) ent.Field {
+	return field.Float(name).
+		SchemaType(map[string]string{
+			dialect.SQLite:   "real",
+			dialect.Postgres: "double precision",
+			dialect.MySQL:    "double",
+		}).
+		Min(0).
+		Immutable()
+}
+
+// Fields method defines the fields within the DiskMetric database table.
+func (DiskMetricMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this disk metric belongs to. A benchmark has one row per block device.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable(),
+		field.String("device").
+			NotEmpty().
+			Immutable().
+			Comment("The name of the block device, e.g. sda or nvme0n1."),
+		// Throughput in bytes per second
+		newRateField("read_bytes_per_second_average"),
+		newRateField("read_bytes_per_second_max"),
+		newRateField("write_bytes_per_second_average"),
+		newRateField("write_bytes_per_second_max"),
+		// Operations per second
+		newRateField("read_iops_average"),
+		newRateField("read_iops_max"),
+		newRateField("write_iops_average"),
+		newRateField("write_iops_max"),
+		// Utilization in percent of the time the device was busy
+		newMetricField("utilization_average"),
+		newMetricField("utilization_max"),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the DiskMetric schema.
+func (DiskMetric) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "dmet"),
+		// The DiskMetric itself
+		DiskMetricMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the DiskMetric schema.
+func (DiskMetric) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("disk_metrics").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark this disk metric belongs to."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the DiskMetric schema.
+func (DiskMetric) Indexes() []ent.Index {
+	return []ent.Index{
+		// A device is recorded at most once per benchmark.
+		index.Fields("benchmark_id", "device").
+			Unique(),
+	}
+}
+
+// Annotations function adds annotations to the DiskMetric schema.
+func (DiskMetric) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Disk metrics are the IO metrics per block device that we polled while the benchmark was running. Devices without any IO during the benchmark are not recorded."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
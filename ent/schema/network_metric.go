@@ -0,0 +1,91 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// NetworkMetric struct extends ent.Schema, defines the NetworkMetric table in the database.
+type NetworkMetric struct {
+	ent.Schema
+}
+
+// NetworkMetricMixin is a struct with embedded mixin.Schema.
+type NetworkMetricMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the NetworkMetric database table.
+func (NetworkMetricMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this network metric belongs to. A benchmark has one row per network interface.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable(),
+		field.String("interface_name").
+			NotEmpty().
+			Immutable().
+			Comment("The name of the network interface, e.g. eth0 or lo."),
+		// Throughput in bytes per second
+		newRateField("bytes_received_per_second_average"),
+		newRateField("bytes_received_per_second_max"),
+		newRateField("bytes_sent_per_second_average"),
+		newRateField("bytes_sent_per_second_max"),
+		// Packets per second
+		newRateField("packets_received_per_second_average"),
+		newRateField("packets_received_per_second_max"),
+		newRateField("packets_sent_per_second_average"),
+		newRateField("packets_sent_per_second_max"),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the NetworkMetric schema.
+func (NetworkMetric) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "nmet"),
+		// The NetworkMetric itself
+		NetworkMetricMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the NetworkMetric schema.
+func (NetworkMetric) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("network_metrics").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark this network metric belongs to."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the NetworkMetric schema.
+func (NetworkMetric) Indexes() []ent.Index {
+	return []ent.Index{
+		// An interface is recorded at most once per benchmark.
+		index.Fields("benchmark_id", "interface_name").
+			Unique(),
+	}
+}
+
+// Annotations function adds annotations to the NetworkMetric schema.
+func (NetworkMetric) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Network metrics are the IO metrics per network interface that we polled while the benchmark was running. Interfaces without any traffic during the benchmark are not recorded."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// ProcessMetric struct extends ent.Schema, defines the ProcessMetric table in the database.
+type ProcessMetric struct {
+	ent.Schema
+}
+
+// ProcessMetricMixin is a struct with embedded mixin.Schema.
+type ProcessMetricMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the ProcessMetric database table.
+func (ProcessMetricMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this process metric belongs to.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable(),
+		field.Enum("role").
+			Values("server", "client").
+			Immutable().
+			Comment("The role of the monitored process tree. 'server' is the PostgreSQL server, 'client' is pgbench."),
+		field.Int("process_count").
+			NonNegative().
+			Immutable().
+			Comment("The maximum number of processes that we observed in the process tree."),
+		// CPU load in percent of a single core. A process tree that fully uses two cores has a load of 200.
+		newMetricField("cpu_average_load"),
+		newMetricField("cpu_max_load"),
+		// Memory
+		field.Uint64("memory_rss_average").
+			Immutable().
+			Comment("The average resident set size in bytes of all processes in the tree. Shared memory is counted once per process."),
+		field.Uint64("memory_rss_max").
+			Immutable().
+			Comment("The maximum resident set size in bytes of all processes in the tree. Shared memory is counted once per process."),
+		// IO counters usually require elevated permissions for processes of other users, hence they're optional.
+		field.Uint64("io_read_bytes").
+			Optional().
+			Nillable().
+			Immutable().
+			Comment("The number of bytes the process tree read from storage during the benchmark run."),
+		field.Uint64("io_write_bytes").
+			Optional().
+			Nillable().
+			Immutable().
+			Comment("The number of bytes the process tree wrote to storage during the benchmark run."),
+		// Context switches
+		field.Uint64("voluntary_context_switches").
+			Immutable(),
+		field.Uint64("involuntary_context_switches").
+			Immutable(),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the ProcessMetric schema.
+func (ProcessMetric) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "pmet"),
+		// The ProcessMetric itself
+		ProcessMetricMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the ProcessMetric schema.
+func (ProcessMetric) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("process_metrics").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark this process metric belongs to."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the ProcessMetric schema.
+func (ProcessMetric) Indexes() []ent.Index {
+	return []ent.Index{
+		// There's at most one process metric per role and benchmark.
+		index.Fields("benchmark_id", "role").
+			Unique(),
+	}
+}
+
+// Annotations function adds annotations to the ProcessMetric schema.
+func (ProcessMetric) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Process metrics are the resource metrics of the PostgreSQL server and the pgbench process trees that we polled while the benchmark was running. Unlike the system metrics, they don't include unrelated processes, which allows to tell whether the server or the load generator is saturated."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
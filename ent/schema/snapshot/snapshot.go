@@ -0,0 +1,16 @@
+// Package snapshot holds the types that are stored as JSON snapshots in the database.
+package snapshot
+
+// RunConfig is a snapshot of the configuration a benchmark-group was run with. It never contains credentials.
+type RunConfig struct {
+	Host                string   `json:"host"`
+	Port                string   `json:"port"`
+	DBName              string   `json:"db_name"`
+	Username            string   `json:"username"`
+	Mode                string   `json:"mode"`
+	Clients             []int    `json:"clients"`
+	Threads             int      `json:"threads"`
+	Collectors          []string `json:"collectors,omitempty"`
+	WaitEventInterval   string   `json:"wait_event_interval,omitempty"`
+	CollectSystemConfig bool     `json:"collect_system_config"`
+}
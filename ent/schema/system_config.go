@@ -87,6 +87,61 @@ func (SystemConfigMixin) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Positive(),
+		// The following fields describe the settings that explain most performance differences between hosts.
+		field.String("kernel_version").
+			Optional().
+			Nillable().
+			NotEmpty(),
+		field.String("cpu_governor").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The CPU frequency scaling governor of the first CPU, e.g. performance or powersave."),
+		field.Uint32("numa_nodes").
+			Optional().
+			Nillable().
+			Positive(),
+		field.String("transparent_huge_pages").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The transparent huge pages setting, i.e. always, madvise or never."),
+		field.String("data_dir_filesystem").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The filesystem of the mount that holds the PostgreSQL data directory, e.g. ext4 or xfs."),
+		field.String("data_dir_mount_options").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The mount options of the mount that holds the PostgreSQL data directory."),
+		field.String("disk_type").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The type of the disk that holds the PostgreSQL data directory, i.e. hdd, ssd or nvme. If the data directory is unknown, this is a comma separated list of the types of all disks."),
+		// The following fields describe the limits of the cgroup dbench ran in, e.g. inside a container. They're only
+		// set if the cgroup has any limits.
+		field.Uint8("cgroup_version").
+			Optional().
+			Nillable().
+			Positive(),
+		field.Float("cgroup_cpu_quota").
+			Optional().
+			Nillable().
+			Positive().
+			Comment("The number of CPUs the cgroup may use, e.g. 1.5."),
+		field.Uint64("cgroup_memory_limit").
+			Optional().
+			Nillable().
+			Positive().
+			Comment("The memory limit of the cgroup in bytes."),
+		field.String("cgroup_io_limits").
+			Optional().
+			Nillable().
+			NotEmpty().
+			Comment("The IO throttles of the cgroup per device in the format of io.max, separated by commas, e.g. 8:0 rbps=1048576 wiops=100."),
 	}
 }
 
@@ -24,8 +24,8 @@ type SystemMetricMixin struct {
 }
 
 // newMetricField function creates a float field parameterized by the given name.
-// This float field includes restrictions on the input value (positive only,
-// minimum value of 0), so it seems it's used to represent some statistic measures.
+// This float field includes restrictions on the input value (minimum value of 0),
+// so it seems it's used to represent some statistic measures.
 func newMetricField(name string) ent.Field {
 	return field.Float(name).
 		// Define custom schema types for each dialect. Metrics would otherwise with a huge precision which is not needed.
@@ -35,11 +35,24 @@ func newMetricField(name string) ent.Field {
 			dialect.Postgres: "numeric(7, 2)",
 			dialect.MySQL:    "numeric(7, 2)",
 		}).
-		Positive().
 		Min(0).
 		Immutable()
 }
 
+// newOptionalMetricField function creates an optional variant of newMetricField. It's used for metrics that were
+// added later on, so that existing rows remain valid.
+func newOptionalMetricField(name string) ent.Field {
+	return field.Float(name).
+		SchemaType(map[string]string{
+			dialect.SQLite:   "real",
+			dialect.Postgres: "numeric(7, 2)",
+			dialect.MySQL:    "numeric(7, 2)",
+		}).
+		Min(0).
+		Optional().
+		Immutable()
+}
+
 // Fields method defines the fields within the SystemMetric database table.
 func (SystemMetricMixin) Fields() []ent.Field {
 	return []ent.Field{
@@ -67,6 +80,33 @@ func (SystemMetricMixin) Fields() []ent.Field {
 		newMetricField("memory_90th_load"),
 		newMetricField("memory_95th_load"),
 		newMetricField("memory_99th_load"),
+		// Load average (1 minute)
+		newOptionalMetricField("load_average_min"),
+		newOptionalMetricField("load_average_max"),
+		newOptionalMetricField("load_average_average"),
+		newOptionalMetricField("load_average_50th"),
+		newOptionalMetricField("load_average_75th"),
+		newOptionalMetricField("load_average_90th"),
+		newOptionalMetricField("load_average_95th"),
+		newOptionalMetricField("load_average_99th"),
+		// IO wait
+		newOptionalMetricField("iowait_min_load"),
+		newOptionalMetricField("iowait_max_load"),
+		newOptionalMetricField("iowait_average_load"),
+		newOptionalMetricField("iowait_50th_load"),
+		newOptionalMetricField("iowait_75th_load"),
+		newOptionalMetricField("iowait_90th_load"),
+		newOptionalMetricField("iowait_95th_load"),
+		newOptionalMetricField("iowait_99th_load"),
+		// Swap
+		newOptionalMetricField("swap_min_load"),
+		newOptionalMetricField("swap_max_load"),
+		newOptionalMetricField("swap_average_load"),
+		newOptionalMetricField("swap_50th_load"),
+		newOptionalMetricField("swap_75th_load"),
+		newOptionalMetricField("swap_90th_load"),
+		newOptionalMetricField("swap_95th_load"),
+		newOptionalMetricField("swap_99th_load"),
 	}
 }
 
@@ -99,7 +139,7 @@ func (SystemMetric) Edges() []ent.Edge {
 func (SystemMetric) Annotations() []schema.Annotation {
 	return []schema.Annotation{
 		entsql.WithComments(true),
-		schema.Comment("System metrics are the system metrics of the host system we polled while the benchmark was running. It is a one-to-one relation to the benchmark table. We probe for CPU and memory usage while the benchmark is running, calculate the median and percentiles and store them here."),
+		schema.Comment("System metrics are the system metrics of the host system we polled while the benchmark was running. It is a one-to-one relation to the benchmark table. We probe for CPU, memory, load average, IO wait and swap usage while the benchmark is running, calculate the median and percentiles and store them here."),
 		entsql.Annotation{Table: "system_metrics"},
 		edge.Annotation{StructTag: `json:"-"`},
 	}
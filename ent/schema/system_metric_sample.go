@@ -0,0 +1,111 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// SystemMetricSample struct extends ent.Schema, defines the SystemMetricSample table in the database.
+type SystemMetricSample struct {
+	ent.Schema
+}
+
+// SystemMetricSampleMixin is a struct with embedded mixin.Schema.
+type SystemMetricSampleMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the SystemMetricSample database table.
+func (SystemMetricSampleMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this sample belongs to. A benchmark has one row per sample.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable(),
+		field.Time("sampled_at").
+			Immutable().
+			Comment("The time at which the sample was taken."),
+		newDurationField("elapsed").
+			Immutable().
+			Comment("The time that passed between the start of pgbench and the sample."),
+		// System load in percent
+		newMetricField("cpu_load"),
+		newMetricField("memory_load"),
+		newMetricField("iowait_load"),
+		newMetricField("swap_load"),
+		newMetricField("load_average"),
+		// IO rates, summed up over all block devices and network interfaces
+		newRateField("disk_read_bytes_per_second"),
+		newRateField("disk_write_bytes_per_second"),
+		newRateField("network_received_bytes_per_second"),
+		newRateField("network_sent_bytes_per_second"),
+		// Progress of pgbench around the time of the sample. It's missing if pgbench didn't report any progress yet.
+		field.Float("transactions_per_second").
+			Optional().
+			Nillable().
+			Immutable().
+			Comment("The transactions per second that pgbench reported for the progress interval closest to the sample."),
+		field.Other("average_latency", duration.Duration(0)).
+			SchemaType(map[string]string{
+				dialect.SQLite:   "BIGINT",
+				dialect.Postgres: "BIGINT",
+				dialect.MySQL:    "BIGINT",
+			}).
+			Optional().
+			Nillable().
+			Immutable().
+			Comment("The average latency that pgbench reported for the progress interval closest to the sample."),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the SystemMetricSample schema.
+func (SystemMetricSample) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "smsmp"),
+		// The SystemMetricSample itself
+		SystemMetricSampleMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the SystemMetricSample schema.
+func (SystemMetricSample) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("system_metric_samples").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark this sample belongs to."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the SystemMetricSample schema.
+func (SystemMetricSample) Indexes() []ent.Index {
+	return []ent.Index{
+		// Samples are always read in chronological order per benchmark.
+		index.Fields("benchmark_id", "sampled_at"),
+	}
+}
+
+// Annotations function adds annotations to the SystemMetricSample schema.
+func (SystemMetricSample) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("System metric samples are the raw, timestamped samples that we polled from the system while the benchmark was running. The system metrics are aggregated from them."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// Tag struct extends ent.Schema, defines the Tag table in the database.
+type Tag struct {
+	ent.Schema
+}
+
+// TagMixin is a struct with embedded mixin.Schema.
+type TagMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the Tag database table.
+func (TagMixin) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("key").
+			NotEmpty().
+			Immutable().
+			Comment("The key of the tag, e.g. env."),
+		field.String("value").
+			NotEmpty().
+			Immutable().
+			Comment("The value of the tag, e.g. staging."),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the Tag schema.
+func (Tag) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "tag"),
+		// The Tag itself
+		TagMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the Tag schema.
+func (Tag) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmarks", Benchmark.Type).
+			Ref("tags").
+			Comment("The benchmarks that are labeled with this tag."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the Tag schema.
+func (Tag) Indexes() []ent.Index {
+	return []ent.Index{
+		// Each key-value pair is stored once and shared by all benchmarks that are labeled with it.
+		index.Fields("key", "value").
+			Unique(),
+	}
+}
+
+// Annotations function adds annotations to the Tag schema.
+func (Tag) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Tags are key-value labels like env=staging or pg=16.1. It is a many-to-many relation to the benchmark table."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+	"entgo.io/ent/schema/mixin"
+
+	"github.com/nikoksr/dbench/ent/schema/datetime"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+// WaitEvent struct extends ent.Schema, defines the WaitEvent table in the database.
+type WaitEvent struct {
+	ent.Schema
+}
+
+// WaitEventMixin is a struct with embedded mixin.Schema.
+type WaitEventMixin struct {
+	mixin.Schema
+}
+
+// Fields method defines the fields within the WaitEvent database table.
+func (WaitEventMixin) Fields() []ent.Field {
+	return []ent.Field{
+		// The benchmark this wait event belongs to. A benchmark has one row per observed wait event.
+		field.String("benchmark_id").
+			GoType(pulid.ID("")).
+			Immutable(),
+		field.String("wait_event_type").
+			NotEmpty().
+			Immutable().
+			Comment("The wait_event_type as reported by pg_stat_activity. Backends that were not waiting are recorded as 'CPU'."),
+		field.String("wait_event").
+			NotEmpty().
+			Immutable().
+			Comment("The wait_event as reported by pg_stat_activity. Backends that were not waiting are recorded as 'CPU'."),
+		field.Int("count").
+			NonNegative().
+			Immutable().
+			Comment("The number of times a pgbench backend was observed in this wait event across all samples."),
+	}
+}
+
+// Mixin function defines the mixins to be incorporated into the WaitEvent schema.
+func (WaitEvent) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		// Primary key using PULIDs
+		pulid.NewMixinWithPrefix("id", "wevt"),
+		// The WaitEvent itself
+		WaitEventMixin{},
+		// CreatedAt and UpdatedAt timestamps
+		datetime.NewMixin(),
+	}
+}
+
+// Edges function defines the relations/edges of the WaitEvent schema.
+func (WaitEvent) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("benchmark", Benchmark.Type).
+			Ref("wait_events").
+			Field("benchmark_id").
+			Unique().
+			Required().
+			Immutable().
+			Comment("The benchmark during which this wait event was observed."),
+	}
+}
+
+// Indexes function defines the indexed fields for faster queries on the WaitEvent schema.
+func (WaitEvent) Indexes() []ent.Index {
+	return []ent.Index{
+		// A wait event is recorded at most once per benchmark.
+		index.Fields("benchmark_id", "wait_event_type", "wait_event").
+			Unique(),
+	}
+}
+
+// Annotations function adds annotations to the WaitEvent schema.
+func (WaitEvent) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.WithComments(true),
+		schema.Comment("Wait events hold the distribution of wait_event_type/wait_event pairs of the pgbench backends that we sampled from pg_stat_activity while the benchmark was running. It is a one-to-many relation to the benchmark table."),
+		edge.Annotation{StructTag: `json:"-"`},
+	}
+}
@@ -66,8 +66,10 @@ func Extract(ctx context.Context, r io.Reader, outDir string) error {
 		if f.IsDir() {
 			return nil
 		}
-		// Skip non JSON files
-		if !strings.HasSuffix(f.Name(), ".json") {
+		// Skip files that aren't exports
+		switch filepath.Ext(f.Name()) {
+		case ".json", ".ndjson", ".csv", ".parquet", ".sqlite":
+		default:
 			return nil
 		}
 
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
 	"strconv"
@@ -14,9 +15,9 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/internal/collector"
 	"github.com/nikoksr/dbench/internal/events"
 	"github.com/nikoksr/dbench/internal/models"
-	"github.com/nikoksr/dbench/internal/system"
 )
 
 // parseDuration converts a string like "5.359 ms" or "1.2 s" to a time.Duration.
@@ -123,8 +124,96 @@ func ParseOutput(output string) (*models.Benchmark, error) {
 	return benchmark, nil
 }
 
-func roundToTwoDecimals(f float64) float64 {
-	return float64(int(f*100)) / 100
+// progressReport is a single progress report of pgbench, which it prints to stderr when run with --progress.
+type progressReport struct {
+	elapsed               time.Duration
+	transactionsPerSecond float64
+	averageLatency        time.Duration
+}
+
+// parseProgress parses the progress reports of pgbench. A report looks like
+// "progress: 5.0 s, 1234.5 tps, lat 3.245 ms stddev 1.203, 0 failed". Reports that can't be parsed, e.g. of intervals
+// without any transactions, are skipped.
+func parseProgress(output string) []progressReport {
+	var reports []progressReport
+
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "progress:") {
+			continue
+		}
+
+		fields := strings.Fields(strings.ReplaceAll(line, ",", " "))
+		if len(fields) < 8 || fields[2] != "s" || fields[4] != "tps" || fields[5] != "lat" {
+			continue
+		}
+
+		elapsed, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		tps, err := strconv.ParseFloat(fields[3], 64)
+		if err != nil || math.IsNaN(tps) {
+			continue
+		}
+		if latency, err := strconv.ParseFloat(fields[6], 64); err != nil || math.IsNaN(latency) {
+			continue
+		}
+
+		reports = append(reports, progressReport{
+			elapsed:               time.Duration(elapsed * float64(time.Second)),
+			transactionsPerSecond: tps,
+			averageLatency:        parseDuration(fields[6], fields[7]),
+		})
+	}
+
+	return reports
+}
+
+// withoutProgress removes the progress reports from the given pgbench output, so that only actual messages remain.
+func withoutProgress(output string) string {
+	lines := strings.Split(output, "\n")
+	lines = slices.DeleteFunc(lines, func(line string) bool {
+		return strings.HasPrefix(line, "progress:")
+	})
+
+	return strings.Join(lines, "\n")
+}
+
+// attachProgress matches each sample with the closest progress report of pgbench.
+func attachProgress(samples []*models.SystemMetricSample, reports []progressReport, progressInterval time.Duration) {
+	for _, sample := range samples {
+		report := closestProgressReport(reports, time.Duration(sample.Elapsed), progressInterval)
+		if report == nil {
+			continue
+		}
+
+		tps := report.transactionsPerSecond
+		latency := duration.Duration(report.averageLatency)
+
+		sample.TransactionsPerSecond = &tps
+		sample.AverageLatency = &latency
+	}
+}
+
+// closestProgressReport returns the report that is closest to the given elapsed time. Reports that are further away
+// than maxDistance are not considered; nil is returned if there's no such report.
+func closestProgressReport(reports []progressReport, elapsed, maxDistance time.Duration) *progressReport {
+	var (
+		closest  *progressReport
+		distance = maxDistance
+	)
+
+	for idx := range reports {
+		d := reports[idx].elapsed - elapsed
+		if d < 0 {
+			d = -d
+		}
+		if d <= distance {
+			closest, distance = &reports[idx], d
+		}
+	}
+
+	return closest
 }
 
 const (
@@ -141,7 +230,6 @@ func Run(ctx context.Context, config *models.BenchmarkConfig) (*models.Benchmark
 	var (
 		totalBenchmarkDuration string
 		samplingRate           time.Duration
-		predictedSamplesCount  int
 	)
 
 	switch config.Mode {
@@ -150,22 +238,18 @@ func Run(ctx context.Context, config *models.BenchmarkConfig) (*models.Benchmark
 		totalBenchmarkDuration = "5"
 		samplingRate = 1 * time.Second
 
-		// Predict the number of samples we will get
-		// 5 seconds / 1 second = 5 samples
-		predictedSamplesCount = 5
-
 	case models.ModeThorough:
 		// Thorough mode runs for 10 minutes total, so we probe every 30 seconds
 		totalBenchmarkDuration = "600"
 		samplingRate = 30 * time.Second
-
-		// Predict the number of samples we will get
-		// 10 minutes / 30 seconds = 20 samples
-		predictedSamplesCount = 20
 	default:
 		return nil, fmt.Errorf("unknown benchmark mode: %q", config.Mode)
 	}
 
+	// Let pgbench report its progress once per sampling interval, so that we can line up the system samples with the
+	// throughput at that time. pgbench only accepts whole seconds.
+	progressInterval := max(time.Second, samplingRate.Truncate(time.Second))
+
 	// Create errgoup to monitor the system while the benchmark is running
 	eg, ctx := errgroup.WithContext(ctx)
 
@@ -181,6 +265,7 @@ func Run(ctx context.Context, config *models.BenchmarkConfig) (*models.Benchmark
 		"-j", strconv.Itoa(config.NumThreads),
 		"-c", strconv.Itoa(config.NumClients),
 		"-T", totalBenchmarkDuration,
+		"-P", strconv.Itoa(int(progressInterval.Seconds())),
 		// Database name is expected as the last argument
 		config.DBName,
 	)
@@ -193,48 +278,57 @@ func Run(ctx context.Context, config *models.BenchmarkConfig) (*models.Benchmark
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	// Start system monitoring
-	stopChan := make(chan struct{})
-	systemSampleChan := make(chan models.SystemSample)
+	// Create and prepare the enabled collectors before pgbench is started, so that we can bail out early
+	collectors := make([]collector.Collector, 0, len(config.Collectors))
+	defer func() {
+		for _, c := range collectors {
+			_ = c.Close()
+		}
+	}()
 
-	// Start monitoring the system
-	eg.Go(func() error {
-		return system.CollectMetrics(samplingRate, stopChan, systemSampleChan)
-	})
+	for _, name := range config.Collectors {
+		c, err := collector.New(name, config, samplingRate)
+		if err != nil {
+			return nil, fmt.Errorf("create collector: %w", err)
+		}
+		if err := c.Prepare(ctx); err != nil {
+			return nil, fmt.Errorf("prepare %s collector: %w", name, err)
+		}
+
+		collectors = append(collectors, c)
+	}
 
-	// Handle system metrics
-	cpuLoad := make([]float64, 0, predictedSamplesCount)
-	totalCPULoad := 0.0
+	// Execute pgbench. We start the command before the collectors, so that we know the PID of pgbench.
+	events.PublishEvent(events.Event{
+		Type:    RunCommandRunning,
+		Message: cmd.String(),
+	})
 
-	memoryUsage := make([]float64, 0, predictedSamplesCount)
-	totalMemoryUsage := 0.0
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start pgbench: %w", err)
+	}
 
-	go func() {
-		for sample := range systemSampleChan {
-			cpuLoad = append(cpuLoad, sample.CPULoad)
-			totalCPULoad += sample.CPULoad
+	target := collector.Target{
+		PID:       int32(cmd.Process.Pid),
+		StartedAt: time.Now(),
+	}
 
-			memoryUsage = append(memoryUsage, sample.MemoryLoad)
-			totalMemoryUsage += sample.MemoryLoad
-		}
-	}()
+	// Sample the collectors until pgbench has finished
+	stopChan := make(chan struct{})
 
-	// Execute pgbench
 	eg.Go(func() error {
-		// Execute the command
-		events.PublishEvent(events.Event{
-			Type:    RunCommandRunning,
-			Message: cmd.String(),
-		})
+		return collector.Run(ctx, collectors, target, stopChan)
+	})
 
-		err := cmd.Run()
-		close(stopChan) // Stop system monitoring
+	eg.Go(func() error {
+		err := cmd.Wait()
+		close(stopChan) // Stop the collectors
 		return err
 	})
 
-	// Wait for the group to finish
+	// Wait for the group to finish. Once it did, all collectors have stopped and their samples can be safely read.
 	if err := eg.Wait(); err != nil {
-		return nil, fmt.Errorf("%w: %s", err, stderr.String())
+		return nil, fmt.Errorf("%w: %s", err, withoutProgress(stderr.String()))
 	}
 
 	// Parse the pgbench output
@@ -244,43 +338,28 @@ func Run(ctx context.Context, config *models.BenchmarkConfig) (*models.Benchmark
 		return nil, fmt.Errorf("parse pgbench output: %w", err)
 	}
 
-	// Sort CPU and memory usage slices, so we can calculate the median and percentiles
-	slices.Sort(cpuLoad)
-	slices.Sort(memoryUsage)
-
-	// Calculate the median and percentiles
-	metrics := new(models.SystemMetric)
-
-	totalCPUSamples := float64(len(cpuLoad))
-	metrics.CPUMinLoad = roundToTwoDecimals(cpuLoad[0])
-	metrics.CPUMaxLoad = roundToTwoDecimals(cpuLoad[len(cpuLoad)-1])
-	metrics.CPUAverageLoad = roundToTwoDecimals(totalCPULoad / totalCPUSamples)
-	metrics.CPU50thLoad = roundToTwoDecimals(cpuLoad[int(totalCPUSamples*0.50)])
-	metrics.CPU75thLoad = roundToTwoDecimals(cpuLoad[int(totalCPUSamples*0.75)])
-	metrics.CPU90thLoad = roundToTwoDecimals(cpuLoad[int(totalCPUSamples*0.90)])
-	metrics.CPU95thLoad = roundToTwoDecimals(cpuLoad[int(totalCPUSamples*0.95)])
-	metrics.CPU99thLoad = roundToTwoDecimals(cpuLoad[int(totalCPUSamples*0.99)])
-
-	totalMemorySamples := float64(len(memoryUsage)) // We could use cpuSamplesCount here, but just to be safe
-	metrics.MemoryMinLoad = roundToTwoDecimals(memoryUsage[0])
-	metrics.MemoryMaxLoad = roundToTwoDecimals(memoryUsage[len(memoryUsage)-1])
-	metrics.MemoryAverageLoad = roundToTwoDecimals(totalMemoryUsage / totalMemorySamples)
-	metrics.Memory50thLoad = roundToTwoDecimals(memoryUsage[int(totalMemorySamples*0.50)])
-	metrics.Memory75thLoad = roundToTwoDecimals(memoryUsage[int(totalMemorySamples*0.75)])
-	metrics.Memory90thLoad = roundToTwoDecimals(memoryUsage[int(totalMemorySamples*0.90)])
-	metrics.Memory95thLoad = roundToTwoDecimals(memoryUsage[int(totalMemorySamples*0.95)])
-	metrics.Memory99thLoad = roundToTwoDecimals(memoryUsage[int(totalMemorySamples*0.99)])
-
 	// Add the missing pieces to the benchmark
 
+	// Keep the raw output, so that the benchmark can be re-parsed once the parser improves
+	benchmark.Edges.Output, err = newOutput(stdout.Bytes(), stderr.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("store pgbench output: %w", err)
+	}
+
 	// Store meta information
 	benchmark.Command = cmd.String()
 	if config.Comment != "" {
 		benchmark.Comment = &config.Comment
 	}
+	benchmark.Edges.Tags = config.Tags
+
+	// Store the aggregated metrics of each collector
+	for _, c := range collectors {
+		c.Apply(benchmark)
+	}
 
-	// Store the system metrics
-	benchmark.Edges.SystemMetric = metrics
+	// Line up the system samples with the progress that pgbench reported on stderr
+	attachProgress(benchmark.Edges.SystemMetricSamples, parseProgress(stderr.String()), progressInterval)
 
 	return benchmark, nil
 }
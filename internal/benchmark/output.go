@@ -0,0 +1,94 @@
+package benchmark
+
+import (
+	"fmt"
+
+	"github.com/nikoksr/dbench/ent/benchmarkoutput"
+	"github.com/nikoksr/dbench/internal/compress"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// ParserVersion is the version of ParseOutput. Bump it whenever the parser changes in a way that affects its results,
+// so that stored outputs that were parsed by an older version can be found and re-parsed.
+const ParserVersion = 1
+
+// newOutput compresses the raw stdout and stderr of a pgbench invocation for storage.
+func newOutput(stdout, stderr []byte) (*models.BenchmarkOutput, error) {
+	compressedStdout, err := compress.Zstd(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("compress stdout: %w", err)
+	}
+
+	compressedStderr, err := compress.Zstd(stderr)
+	if err != nil {
+		return nil, fmt.Errorf("compress stderr: %w", err)
+	}
+
+	return &models.BenchmarkOutput{
+		Compression:   benchmarkoutput.CompressionZstd,
+		Stdout:        compressedStdout,
+		Stderr:        compressedStderr,
+		ParserVersion: ParserVersion,
+	}, nil
+}
+
+// DecompressOutput returns the raw stdout and stderr of the given stored output.
+func DecompressOutput(output *models.BenchmarkOutput) (stdout, stderr string, err error) {
+	if output == nil {
+		return "", "", fmt.Errorf("benchmark output is nil")
+	}
+	if output.Compression != benchmarkoutput.CompressionZstd {
+		return "", "", fmt.Errorf("unsupported compression %q", output.Compression)
+	}
+
+	rawStdout, err := compress.Unzstd(output.Stdout)
+	if err != nil {
+		return "", "", fmt.Errorf("decompress stdout: %w", err)
+	}
+
+	rawStderr, err := compress.Unzstd(output.Stderr)
+	if err != nil {
+		return "", "", fmt.Errorf("decompress stderr: %w", err)
+	}
+
+	return string(rawStdout), string(rawStderr), nil
+}
+
+// Reparse runs the current parser over the stored output of a benchmark. The returned benchmark only holds the parsed
+// fields and its result.
+func Reparse(output *models.BenchmarkOutput) (*models.Benchmark, error) {
+	stdout, _, err := DecompressOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	benchmark, err := ParseOutput(stdout)
+	if err != nil {
+		return nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+
+	return benchmark, nil
+}
+
+// FromOutput creates a benchmark from pgbench output that was saved outside of dbench, e.g. to a text file. Like the
+// benchmarks that dbench runs itself, it keeps the raw output, so that it can be re-parsed later on. Information that
+// isn't part of the output, such as system metrics, is absent.
+func FromOutput(stdout []byte) (*models.Benchmark, error) {
+	benchmark, err := ParseOutput(string(stdout))
+	if err != nil {
+		return nil, fmt.Errorf("parse pgbench output: %w", err)
+	}
+
+	// The parser skips unknown lines, so arbitrary text would result in an empty benchmark
+	result := benchmark.Edges.Result
+	if benchmark.Clients == 0 && result.Transactions == 0 && result.TransactionsPerSecond == 0 {
+		return nil, fmt.Errorf("no pgbench results found")
+	}
+
+	benchmark.Edges.Output, err = newOutput(stdout, nil)
+	if err != nil {
+		return nil, fmt.Errorf("store pgbench output: %w", err)
+	}
+
+	return benchmark, nil
+}
@@ -0,0 +1,16 @@
+package collector
+
+import (
+	"github.com/nikoksr/dbench/internal/stats"
+)
+
+// decimals is the precision of the aggregated metrics.
+const decimals = 2
+
+// averageAndMax returns the average and the maximum of the given values, rounded to the precision of the metrics.
+func averageAndMax(values []float64) (float64, float64) {
+	var summary stats.Summary
+	summary.AddAll(values...)
+
+	return stats.Round(summary.Mean(), decimals), stats.Round(summary.Max(), decimals)
+}
@@ -0,0 +1,116 @@
+// Package collector samples metrics while a benchmark is running. Each source of metrics, e.g. the host system or the
+// PostgreSQL server, is implemented as a Collector that can be enabled per benchmark run.
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+const (
+	NameHost     = "host"     // NameHost is the name of the collector that samples the metrics of the whole system
+	NameProcess  = "process"  // NameProcess is the name of the collector that samples the PostgreSQL and pgbench processes
+	NamePostgres = "postgres" // NamePostgres is the name of the collector that samples PostgreSQL's statistics views
+)
+
+// Names holds the names of all available collectors.
+var Names = []string{NameHost, NameProcess, NamePostgres}
+
+// Target describes the pgbench run that is being monitored.
+type Target struct {
+	PID       int32     // PID is the process ID of pgbench
+	StartedAt time.Time // StartedAt is the time at which pgbench was started
+}
+
+// Collector samples a source of metrics while pgbench is running. The methods are called in the following order:
+// Prepare, Start, Collect (repeatedly), Apply and Close. Collect is never called concurrently and Apply is only called
+// once sampling has stopped, so implementations don't need to synchronize access to their samples.
+type Collector interface {
+	// Name returns the name by which the collector is enabled.
+	Name() string
+	// Interval returns the interval at which the collector is sampled.
+	Interval() time.Duration
+	// Prepare is called before pgbench is started. A collector that can't work at all should fail here, so that we
+	// don't waste a benchmark run.
+	Prepare(ctx context.Context) error
+	// Start is called right after pgbench was started. It's typically used to take a baseline.
+	Start(ctx context.Context, target Target) error
+	// Collect takes a single sample.
+	Collect(ctx context.Context) error
+	// Apply aggregates the samples and stores them in the given benchmark. Collectors that didn't take any samples
+	// leave the benchmark untouched.
+	Apply(benchmark *models.Benchmark)
+	// Close releases the resources of the collector.
+	Close() error
+}
+
+// Validate returns an error if any of the given names doesn't belong to an available collector.
+func Validate(names ...string) error {
+	for _, name := range names {
+		if !slices.Contains(Names, name) {
+			return fmt.Errorf("unknown collector %q, available collectors are: %s", name, strings.Join(Names, ", "))
+		}
+	}
+
+	return nil
+}
+
+// New creates the collector with the given name. Collectors that sample the host and processes use the given interval,
+// the postgres collector uses the wait event interval of the config if set.
+func New(name string, config *models.BenchmarkConfig, interval time.Duration) (Collector, error) {
+	switch name {
+	case NameHost:
+		return newHostCollector(interval), nil
+	case NameProcess:
+		return newProcessCollector(interval, config.PGDataDir, config.PGProcessName), nil
+	case NamePostgres:
+		if config.WaitEventInterval > 0 {
+			interval = config.WaitEventInterval
+		}
+		return newPostgresCollector(config, interval), nil
+	default:
+		return nil, Validate(name)
+	}
+}
+
+// Run starts the given collectors and samples each of them at its interval until the stop channel is closed or the
+// context is canceled. It only returns once all collectors have stopped, so that their samples can be safely applied
+// afterwards. The first error of any collector stops all collectors and is returned.
+func Run(ctx context.Context, collectors []Collector, target Target, stopChan <-chan struct{}) error {
+	eg, ctx := errgroup.WithContext(ctx)
+
+	for _, collector := range collectors {
+		collector := collector
+
+		eg.Go(func() error {
+			if err := collector.Start(ctx, target); err != nil {
+				return fmt.Errorf("start %s collector: %w", collector.Name(), err)
+			}
+
+			ticker := time.NewTicker(collector.Interval())
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-stopChan:
+					return nil
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+					if err := collector.Collect(ctx); err != nil {
+						return fmt.Errorf("collect %s metrics: %w", collector.Name(), err)
+					}
+				}
+			}
+		})
+	}
+
+	return eg.Wait()
+}
@@ -0,0 +1,82 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+type fakeCollector struct {
+	err      error
+	started  bool
+	collects int
+}
+
+func (c *fakeCollector) Name() string                        { return "fake" }
+func (c *fakeCollector) Interval() time.Duration             { return time.Millisecond }
+func (c *fakeCollector) Prepare(context.Context) error       { return nil }
+func (c *fakeCollector) Start(context.Context, Target) error { c.started = true; return nil }
+func (c *fakeCollector) Apply(*models.Benchmark)             {}
+func (c *fakeCollector) Close() error                        { return nil }
+func (c *fakeCollector) Collect(context.Context) error {
+	c.collects++
+	return c.err
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	collectors := []*fakeCollector{{}, {}}
+
+	stopChan := make(chan struct{})
+	time.AfterFunc(20*time.Millisecond, func() { close(stopChan) })
+
+	err := Run(context.Background(), []Collector{collectors[0], collectors[1]}, Target{}, stopChan)
+	assert.NoError(t, err)
+
+	// Run must only return once all collectors have stopped, so reading their state here is safe
+	for _, c := range collectors {
+		assert.True(t, c.started)
+		assert.Positive(t, c.collects)
+	}
+}
+
+func TestRunError(t *testing.T) {
+	t.Parallel()
+
+	failing := &fakeCollector{err: errors.New("boom")}
+	healthy := &fakeCollector{}
+
+	// The stop channel is never closed; the failing collector has to stop the healthy one
+	err := Run(context.Background(), []Collector{failing, healthy}, Target{}, make(chan struct{}))
+	assert.ErrorIs(t, err, failing.err)
+	assert.Equal(t, 1, failing.collects)
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, Validate())
+	assert.NoError(t, Validate(NameHost, NameProcess, NamePostgres))
+	assert.Error(t, Validate(NameHost, "unknown"))
+}
+
+func TestApplyWithoutSamples(t *testing.T) {
+	t.Parallel()
+
+	benchmark := new(models.Benchmark)
+
+	newHostCollector(time.Second).Apply(benchmark)
+	newProcessCollector(time.Second, "", "postgres").Apply(benchmark)
+	newPostgresCollector(new(models.BenchmarkConfig), time.Second).Apply(benchmark)
+
+	assert.Nil(t, benchmark.Edges.SystemMetric)
+	assert.Empty(t, benchmark.Edges.SystemMetricSamples)
+	assert.Empty(t, benchmark.Edges.ProcessMetrics)
+	assert.Empty(t, benchmark.Edges.WaitEvents)
+}
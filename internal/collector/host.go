@@ -0,0 +1,256 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/stats"
+	"github.com/nikoksr/dbench/internal/system"
+)
+
+// hostCollector samples the metrics of the whole system, i.e. CPU, memory, load average, swap and the IO of all block
+// devices and network interfaces.
+type hostCollector struct {
+	interval  time.Duration
+	sampler   *system.Sampler
+	startedAt time.Time
+	samples   []models.SystemSample
+}
+
+var _ Collector = (*hostCollector)(nil)
+
+func newHostCollector(interval time.Duration) *hostCollector {
+	return &hostCollector{interval: interval}
+}
+
+func (c *hostCollector) Name() string { return NameHost }
+
+func (c *hostCollector) Interval() time.Duration { return c.interval }
+
+func (c *hostCollector) Prepare(context.Context) error { return nil }
+
+func (c *hostCollector) Start(_ context.Context, target Target) error {
+	c.sampler = system.NewSampler()
+	c.startedAt = target.StartedAt
+
+	return nil
+}
+
+func (c *hostCollector) Collect(context.Context) error {
+	sample, err := c.sampler.Sample()
+	if err != nil {
+		return fmt.Errorf("get system metrics: %w", err)
+	}
+
+	c.samples = append(c.samples, sample)
+
+	return nil
+}
+
+func (c *hostCollector) Apply(benchmark *models.Benchmark) {
+	if len(c.samples) == 0 {
+		return
+	}
+
+	// Store the system metrics and the samples they were aggregated from
+	samples := newSystemMetricSamples(c.samples, c.startedAt)
+	benchmark.Edges.SystemMetric = aggregateSystemMetricSamples(samples)
+	benchmark.Edges.SystemMetricSamples = samples
+
+	// Store the disk and network metrics
+	benchmark.Edges.DiskMetrics = aggregateDiskSamples(c.samples)
+	benchmark.Edges.NetworkMetrics = aggregateNetworkSamples(c.samples)
+}
+
+func (c *hostCollector) Close() error { return nil }
+
+// newSystemMetricSamples converts the raw system samples into their persistable form. Disk and network rates are summed
+// up over all devices and interfaces.
+func newSystemMetricSamples(samples []models.SystemSample, startedAt time.Time) []*models.SystemMetricSample {
+	metricSamples := make([]*models.SystemMetricSample, 0, len(samples))
+
+	for _, sample := range samples {
+		elapsed := sample.SampledAt.Sub(startedAt)
+
+		metricSample := &models.SystemMetricSample{
+			SampledAt:   sample.SampledAt,
+			Elapsed:     duration.Duration(elapsed),
+			CPULoad:     sample.CPULoad,
+			MemoryLoad:  sample.MemoryLoad,
+			IowaitLoad:  sample.IOWait,
+			SwapLoad:    sample.SwapLoad,
+			LoadAverage: sample.LoadAverage,
+		}
+
+		for _, disk := range sample.Disks {
+			metricSample.DiskReadBytesPerSecond += disk.ReadBytesPerSecond
+			metricSample.DiskWriteBytesPerSecond += disk.WriteBytesPerSecond
+		}
+		for _, network := range sample.Networks {
+			metricSample.NetworkReceivedBytesPerSecond += network.BytesReceivedPerSecond
+			metricSample.NetworkSentBytesPerSecond += network.BytesSentPerSecond
+		}
+
+		metricSamples = append(metricSamples, metricSample)
+	}
+
+	return metricSamples
+}
+
+// aggregateSystemMetricSamples reduces the system metric samples to their distributions. Percentiles are interpolated
+// between samples, so that they stay meaningful for short runs with only a few samples. An empty series yields zero
+// values.
+func aggregateSystemMetricSamples(samples []*models.SystemMetricSample) *models.SystemMetric {
+	var cpuLoad, memoryLoad, loadAverage, ioWait, swapLoad []float64
+	for _, sample := range samples {
+		cpuLoad = append(cpuLoad, sample.CPULoad)
+		memoryLoad = append(memoryLoad, sample.MemoryLoad)
+		loadAverage = append(loadAverage, sample.LoadAverage)
+		ioWait = append(ioWait, sample.IowaitLoad)
+		swapLoad = append(swapLoad, sample.SwapLoad)
+	}
+
+	metrics := new(models.SystemMetric)
+
+	cpu := stats.NewDistribution(cpuLoad).Round(decimals)
+	metrics.CPUMinLoad = cpu.Min
+	metrics.CPUMaxLoad = cpu.Max
+	metrics.CPUAverageLoad = cpu.Mean
+	metrics.CPU50thLoad = cpu.P50
+	metrics.CPU75thLoad = cpu.P75
+	metrics.CPU90thLoad = cpu.P90
+	metrics.CPU95thLoad = cpu.P95
+	metrics.CPU99thLoad = cpu.P99
+
+	memory := stats.NewDistribution(memoryLoad).Round(decimals)
+	metrics.MemoryMinLoad = memory.Min
+	metrics.MemoryMaxLoad = memory.Max
+	metrics.MemoryAverageLoad = memory.Mean
+	metrics.Memory50thLoad = memory.P50
+	metrics.Memory75thLoad = memory.P75
+	metrics.Memory90thLoad = memory.P90
+	metrics.Memory95thLoad = memory.P95
+	metrics.Memory99thLoad = memory.P99
+
+	load := stats.NewDistribution(loadAverage).Round(decimals)
+	metrics.LoadAverageMin = load.Min
+	metrics.LoadAverageMax = load.Max
+	metrics.LoadAverageAverage = load.Mean
+	metrics.LoadAverage50th = load.P50
+	metrics.LoadAverage75th = load.P75
+	metrics.LoadAverage90th = load.P90
+	metrics.LoadAverage95th = load.P95
+	metrics.LoadAverage99th = load.P99
+
+	iowait := stats.NewDistribution(ioWait).Round(decimals)
+	metrics.IowaitMinLoad = iowait.Min
+	metrics.IowaitMaxLoad = iowait.Max
+	metrics.IowaitAverageLoad = iowait.Mean
+	metrics.Iowait50thLoad = iowait.P50
+	metrics.Iowait75thLoad = iowait.P75
+	metrics.Iowait90thLoad = iowait.P90
+	metrics.Iowait95thLoad = iowait.P95
+	metrics.Iowait99thLoad = iowait.P99
+
+	swap := stats.NewDistribution(swapLoad).Round(decimals)
+	metrics.SwapMinLoad = swap.Min
+	metrics.SwapMaxLoad = swap.Max
+	metrics.SwapAverageLoad = swap.Mean
+	metrics.Swap50thLoad = swap.P50
+	metrics.Swap75thLoad = swap.P75
+	metrics.Swap90thLoad = swap.P90
+	metrics.Swap95thLoad = swap.P95
+	metrics.Swap99thLoad = swap.P99
+
+	return metrics
+}
+
+// aggregateDiskSamples reduces the samples of each block device to a disk metric. Devices that had no IO at all are
+// skipped.
+func aggregateDiskSamples(systemSamples []models.SystemSample) []*models.DiskMetric {
+	samples := make(map[string][]models.DiskSample)
+	for _, systemSample := range systemSamples {
+		for _, disk := range systemSample.Disks {
+			samples[disk.Device] = append(samples[disk.Device], disk)
+		}
+	}
+
+	metrics := make([]*models.DiskMetric, 0, len(samples))
+
+	for device, deviceSamples := range samples {
+		var readBytes, writeBytes, readIOPS, writeIOPS, utilization []float64
+		for _, sample := range deviceSamples {
+			readBytes = append(readBytes, sample.ReadBytesPerSecond)
+			writeBytes = append(writeBytes, sample.WriteBytesPerSecond)
+			readIOPS = append(readIOPS, sample.ReadIOPS)
+			writeIOPS = append(writeIOPS, sample.WriteIOPS)
+			utilization = append(utilization, sample.Utilization)
+		}
+
+		metric := &models.DiskMetric{Device: device}
+		metric.ReadBytesPerSecondAverage, metric.ReadBytesPerSecondMax = averageAndMax(readBytes)
+		metric.WriteBytesPerSecondAverage, metric.WriteBytesPerSecondMax = averageAndMax(writeBytes)
+		metric.ReadIopsAverage, metric.ReadIopsMax = averageAndMax(readIOPS)
+		metric.WriteIopsAverage, metric.WriteIopsMax = averageAndMax(writeIOPS)
+		metric.UtilizationAverage, metric.UtilizationMax = averageAndMax(utilization)
+
+		if metric.ReadIopsMax == 0 && metric.WriteIopsMax == 0 {
+			continue // Idle device
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	slices.SortFunc(metrics, func(a, b *models.DiskMetric) int {
+		return strings.Compare(a.Device, b.Device)
+	})
+
+	return metrics
+}
+
+// aggregateNetworkSamples reduces the samples of each network interface to a network metric. Interfaces that had no
+// traffic at all are skipped.
+func aggregateNetworkSamples(systemSamples []models.SystemSample) []*models.NetworkMetric {
+	samples := make(map[string][]models.NetworkSample)
+	for _, systemSample := range systemSamples {
+		for _, network := range systemSample.Networks {
+			samples[network.Interface] = append(samples[network.Interface], network)
+		}
+	}
+
+	metrics := make([]*models.NetworkMetric, 0, len(samples))
+
+	for iface, ifaceSamples := range samples {
+		var bytesReceived, bytesSent, packetsReceived, packetsSent []float64
+		for _, sample := range ifaceSamples {
+			bytesReceived = append(bytesReceived, sample.BytesReceivedPerSecond)
+			bytesSent = append(bytesSent, sample.BytesSentPerSecond)
+			packetsReceived = append(packetsReceived, sample.PacketsReceivedPerSecond)
+			packetsSent = append(packetsSent, sample.PacketsSentPerSecond)
+		}
+
+		metric := &models.NetworkMetric{InterfaceName: iface}
+		metric.BytesReceivedPerSecondAverage, metric.BytesReceivedPerSecondMax = averageAndMax(bytesReceived)
+		metric.BytesSentPerSecondAverage, metric.BytesSentPerSecondMax = averageAndMax(bytesSent)
+		metric.PacketsReceivedPerSecondAverage, metric.PacketsReceivedPerSecondMax = averageAndMax(packetsReceived)
+		metric.PacketsSentPerSecondAverage, metric.PacketsSentPerSecondMax = averageAndMax(packetsSent)
+
+		if metric.PacketsReceivedPerSecondMax == 0 && metric.PacketsSentPerSecondMax == 0 {
+			continue // Idle interface
+		}
+
+		metrics = append(metrics, metric)
+	}
+
+	slices.SortFunc(metrics, func(a, b *models.NetworkMetric) int {
+		return strings.Compare(a.InterfaceName, b.InterfaceName)
+	})
+
+	return metrics
+}
@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/pgstat"
+)
+
+// postgresCollector samples the wait events of the pgbench backends from pg_stat_activity. It uses a dedicated
+// connection to the target database.
+type postgresCollector struct {
+	config   *models.BenchmarkConfig
+	interval time.Duration
+	db       *sql.DB
+	samples  [][]models.WaitEventSample
+}
+
+var _ Collector = (*postgresCollector)(nil)
+
+func newPostgresCollector(config *models.BenchmarkConfig, interval time.Duration) *postgresCollector {
+	return &postgresCollector{
+		config:   config,
+		interval: interval,
+	}
+}
+
+func (c *postgresCollector) Name() string { return NamePostgres }
+
+func (c *postgresCollector) Interval() time.Duration { return c.interval }
+
+// Prepare opens the connection to the target database, so that we can bail out before pgbench is started.
+func (c *postgresCollector) Prepare(ctx context.Context) error {
+	db, err := pgstat.Open(ctx, c.config)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+
+	c.db = db
+
+	return nil
+}
+
+func (c *postgresCollector) Start(context.Context, Target) error { return nil }
+
+func (c *postgresCollector) Collect(ctx context.Context) error {
+	samples, err := pgstat.WaitEvents(ctx, c.db)
+	if err != nil {
+		return fmt.Errorf("get wait events: %w", err)
+	}
+
+	c.samples = append(c.samples, samples)
+
+	return nil
+}
+
+func (c *postgresCollector) Apply(benchmark *models.Benchmark) {
+	if len(c.samples) == 0 {
+		return
+	}
+
+	benchmark.Edges.WaitEvents = aggregateWaitEvents(c.samples)
+}
+
+func (c *postgresCollector) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// aggregateWaitEvents sums up the wait event samples per wait_event_type and wait_event.
+func aggregateWaitEvents(samples [][]models.WaitEventSample) []*models.WaitEvent {
+	type key struct{ typ, event string }
+
+	counts := make(map[key]int)
+	for _, sample := range samples {
+		for _, waitEvent := range sample {
+			counts[key{waitEvent.Type, waitEvent.Event}] += waitEvent.Count
+		}
+	}
+
+	waitEvents := make([]*models.WaitEvent, 0, len(counts))
+	for k, count := range counts {
+		waitEvents = append(waitEvents, &models.WaitEvent{
+			WaitEventType: k.typ,
+			WaitEvent:     k.event,
+			Count:         count,
+		})
+	}
+
+	// Sort by count, so that the most frequent wait events come first
+	slices.SortFunc(waitEvents, func(a, b *models.WaitEvent) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		if a.WaitEventType != b.WaitEventType {
+			return strings.Compare(a.WaitEventType, b.WaitEventType)
+		}
+		return strings.Compare(a.WaitEvent, b.WaitEvent)
+	})
+
+	return waitEvents
+}
@@ -0,0 +1,133 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nikoksr/dbench/ent/processmetric"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/stats"
+	"github.com/nikoksr/dbench/internal/system"
+)
+
+// processCollector samples the process trees of the PostgreSQL server and pgbench separately.
+type processCollector struct {
+	interval    time.Duration
+	dataDir     string
+	processName string
+
+	serverPID int32 // Zero if the server couldn't be found
+	trackers  []*system.ProcessTracker
+	samples   map[string][]models.ProcessSample
+}
+
+var _ Collector = (*processCollector)(nil)
+
+func newProcessCollector(interval time.Duration, dataDir, processName string) *processCollector {
+	return &processCollector{
+		interval:    interval,
+		dataDir:     dataDir,
+		processName: processName,
+		samples:     make(map[string][]models.ProcessSample, 2),
+	}
+}
+
+func (c *processCollector) Name() string { return NameProcess }
+
+func (c *processCollector) Interval() time.Duration { return c.interval }
+
+// Prepare finds the PostgreSQL server process. Unless a data directory was given, it's not fatal if we can't find it,
+// e.g. because the server runs on a different machine; we just won't have server-side process metrics then. If there
+// are several servers, we'd monitor an arbitrary one, so the user has to tell us which one they're benchmarking.
+func (c *processCollector) Prepare(ctx context.Context) error {
+	pid, err := system.FindPostgresPID(ctx, c.dataDir, c.processName)
+	if err != nil && (c.dataDir != "" || !errors.Is(err, system.ErrPostgresNotFound)) {
+		// The user explicitly told us where to look, so we should tell them if that didn't work
+		return fmt.Errorf("find postgres server process: %w", err)
+	}
+
+	c.serverPID = pid // Zero if not found
+
+	return nil
+}
+
+func (c *processCollector) Start(ctx context.Context, target Target) error {
+	if c.serverPID != 0 {
+		c.trackers = append(c.trackers, system.NewProcessTracker(ctx, models.ProcessRoleServer, c.serverPID))
+	}
+	c.trackers = append(c.trackers, system.NewProcessTracker(ctx, models.ProcessRoleClient, target.PID))
+
+	return nil
+}
+
+func (c *processCollector) Collect(ctx context.Context) error {
+	for _, tracker := range c.trackers {
+		sample, err := tracker.Sample(ctx)
+		if err != nil {
+			continue // The process is gone, e.g. pgbench has just finished
+		}
+
+		c.samples[sample.Role] = append(c.samples[sample.Role], sample)
+	}
+
+	return nil
+}
+
+func (c *processCollector) Apply(benchmark *models.Benchmark) {
+	for _, role := range []string{models.ProcessRoleServer, models.ProcessRoleClient} {
+		if metric := aggregateProcessSamples(role, c.samples[role]); metric != nil {
+			benchmark.Edges.ProcessMetrics = append(benchmark.Edges.ProcessMetrics, metric)
+		}
+	}
+}
+
+func (c *processCollector) Close() error { return nil }
+
+// aggregateProcessSamples reduces the samples of a process tree to a process metric. It returns nil if there are no
+// samples.
+func aggregateProcessSamples(role string, samples []models.ProcessSample) *models.ProcessMetric {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	metric := &models.ProcessMetric{
+		Role: processmetric.Role(role),
+	}
+
+	var (
+		cpuLoad               stats.Summary
+		totalRSS              uint64
+		ioAvailable           = true
+		readBytes, writeBytes uint64
+	)
+
+	for _, sample := range samples {
+		metric.ProcessCount = max(metric.ProcessCount, sample.ProcessCount)
+
+		cpuLoad.Add(sample.CPULoad)
+
+		totalRSS += sample.RSS
+		metric.MemoryRssMax = max(metric.MemoryRssMax, sample.RSS)
+
+		ioAvailable = ioAvailable && sample.IOAvailable
+		readBytes += sample.ReadBytes
+		writeBytes += sample.WriteBytes
+
+		metric.VoluntaryContextSwitches += sample.VoluntaryCtxSwitches
+		metric.InvoluntaryContextSwitches += sample.InvoluntaryCtxSwitches
+	}
+
+	metric.CPUAverageLoad = stats.Round(cpuLoad.Mean(), decimals)
+	metric.CPUMaxLoad = stats.Round(cpuLoad.Max(), decimals)
+	metric.MemoryRssAverage = totalRSS / uint64(len(samples))
+
+	// IO counters are only meaningful if we could read them for every process in every sample
+	if ioAvailable {
+		metric.IoReadBytes = &readBytes
+		metric.IoWriteBytes = &writeBytes
+	}
+
+	return metric
+}
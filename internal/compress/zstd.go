@@ -0,0 +1,44 @@
+// Package compress provides helpers to compress and decompress blobs that are stored in the database.
+package compress
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The encoder and decoder are safe for concurrent use of EncodeAll and DecodeAll, so we share them.
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// Zstd compresses the given data with zstd.
+func Zstd(data []byte) ([]byte, error) {
+	encoder, err := zstdEncoder()
+	if err != nil {
+		return nil, fmt.Errorf("create zstd encoder: %w", err)
+	}
+
+	return encoder.EncodeAll(data, nil), nil
+}
+
+// Unzstd decompresses the given zstd compressed data.
+func Unzstd(data []byte) ([]byte, error) {
+	decoder, err := zstdDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("create zstd decoder: %w", err)
+	}
+
+	decompressed, err := decoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decompress zstd: %w", err)
+	}
+
+	return decompressed, nil
+}
@@ -0,0 +1,25 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZstd(t *testing.T) {
+	t.Parallel()
+
+	data := bytes.Repeat([]byte("progress: 5.0 s, 1234.5 tps, lat 3.245 ms stddev 1.203, 0 failed\n"), 100)
+
+	compressed, err := Zstd(data)
+	assert.NoError(t, err)
+	assert.Less(t, len(compressed), len(data))
+
+	decompressed, err := Unzstd(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decompressed)
+
+	_, err = Unzstd([]byte("not zstd"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/appconfig"
+	"github.com/nikoksr/dbench/ent/benchmark"
+)
+
+// Info summarizes the contents of a database, so that two databases can be compared before one replaces the other.
+type Info struct {
+	Benchmarks       int
+	LatestRecordedAt *time.Time
+}
+
+// Backup writes a consistent copy of a SQLite database to the given path, which must not exist yet. Unlike copying the
+// database file, this is safe while the database is in WAL mode and in use.
+func (db *DB) Backup(ctx context.Context, path string) error {
+	if db.dialect != dialect.SQLite {
+		return fmt.Errorf("backups are only supported for SQLite stores, use pg_dump for PostgreSQL")
+	}
+
+	_, err := db.driver.DB().ExecContext(ctx, "VACUUM INTO '"+strings.ReplaceAll(path, "'", "''")+"'")
+
+	return err
+}
+
+// AppVersion returns the version of dbench that migrated the database last. It's empty if the database was never
+// migrated.
+func (db *DB) AppVersion(ctx context.Context) (string, error) {
+	config, err := db.client.AppConfig.Query().
+		Select(appconfig.FieldVersion).
+		Only(ctx)
+	if ent.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("query app config: %w", err)
+	}
+
+	return config.Version, nil
+}
+
+// Info returns a summary of the benchmarks in the database. The database has to be migrated to the current schema.
+func (db *DB) Info(ctx context.Context) (*Info, error) {
+	count, err := db.client.Benchmark.Query().Count(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("count benchmarks: %w", err)
+	}
+
+	info := &Info{Benchmarks: count}
+	if count == 0 {
+		return info, nil
+	}
+
+	latest, err := db.client.Benchmark.Query().
+		Order(ent.Desc(benchmark.FieldRecordedAt)).
+		Select(benchmark.FieldRecordedAt).
+		First(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query latest benchmark: %w", err)
+	}
+
+	info.LatestRecordedAt = &latest.RecordedAt
+
+	return info, nil
+}
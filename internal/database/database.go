@@ -3,16 +3,17 @@ package database
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"entgo.io/ent/dialect"
-	"github.com/Masterminds/semver/v3"
-	_ "github.com/lib/pq"          // PostgreSQL driver
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/lib/pq"
 	_ "github.com/xiaoqidun/entps" // Modernc wrapper for ent
+	"golang.org/x/exp/slices"
 
 	"github.com/nikoksr/dbench/ent"
-	"github.com/nikoksr/dbench/ent/appconfig"
-	"github.com/nikoksr/dbench/ent/migrate"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
 	"github.com/nikoksr/dbench/ent/schema/pulid"
 	"github.com/nikoksr/dbench/internal/models"
 )
@@ -23,21 +24,40 @@ var _ Store = (*DB)(nil)
 type Store interface {
 	Save(ctx context.Context, res *models.Benchmark) (*models.Benchmark, error)
 	SaveMany(ctx context.Context, res []*models.Benchmark) ([]*models.Benchmark, error)
+	Import(ctx context.Context, bmarks []*models.Benchmark, strategy ConflictStrategy) (*ImportSummary, error)
 
 	Fetch(ctx context.Context, options ...QueryOption) ([]*models.Benchmark, error)
 	FetchByIDs(ctx context.Context, ids []string, options ...QueryOption) ([]*models.Benchmark, error)
 	FetchByGroupIDs(ctx context.Context, ids []string, options ...QueryOption) ([]*models.Benchmark, error)
+	FetchIDs(ctx context.Context, options ...QueryOption) ([]string, error)
 	FetchGroupIDs(ctx context.Context, options ...QueryOption) ([]string, error)
+	FetchOutputs(ctx context.Context, ids []string) ([]*models.BenchmarkOutput, error)
 	Count(ctx context.Context, options ...QueryOption) (uint64, error)
 	CountAll(ctx context.Context) (uint64, error)
 
+	UpdateParsed(ctx context.Context, id string, parsed *models.Benchmark, parserVersion int) error
+
 	RemoveByIDs(ctx context.Context, ids []string) error
 	RemoveByGroupIDs(ctx context.Context, ids []string) error
+
+	TagByGroupIDs(ctx context.Context, ids []string, tags []*models.Tag, removeKeys []string) error
+
+	CreateGroup(ctx context.Context, group *models.BenchmarkGroup) (*models.BenchmarkGroup, error)
+	FinishGroup(ctx context.Context, id string, status benchmarkgroup.Status) error
+	ResolveGroupIDs(ctx context.Context, namesOrIDs []string) ([]string, error)
+
+	MigrationStatus(ctx context.Context) ([]MigrationStatus, error)
+	MigrateUp(ctx context.Context, appVersion string) (int, error)
+	MigrateDown(ctx context.Context, steps int) (int, error)
+	BackupBeforeMigration(ctx context.Context) (string, error)
 }
 
 // DB is a struct that represents a database connection.
 type DB struct {
 	client      *ent.Client
+	driver      *entsql.Driver
+	dialect     string
+	path        string // Path of the database file, only set for SQLite
 	connectOnce sync.Once
 	err         error
 }
@@ -47,85 +67,72 @@ func New() *DB {
 	return &DB{}
 }
 
-// Connect establishes a connection to the database.
-// It uses the sync.Once.Do function to ensure that the connection is only established once.
-func (db *DB) Connect(_ context.Context, dsn string) (*DB, error) {
-	db.connectOnce.Do(func() {
-		dsn += "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=journal_size_limit(200000000)&_pragma=synchronous(NORMAL)&_pragma=foreign_keys(ON)&_pragma=temp_store(MEMORY)&_pragma=cache_size(-16000)"
+// postgresKeywords are the keywords of a PostgreSQL keyword/value connection string.
+var postgresKeywords = []string{
+	"host", "hostaddr", "port", "dbname", "user", "password", "passfile", "connect_timeout", "options",
+	"application_name", "fallback_application_name", "sslmode", "sslcert", "sslkey", "sslrootcert", "sslcrl", "service",
+	"target_session_attrs",
+}
 
-		// Open database connection
-		db.client, db.err = ent.Open(dialect.SQLite, dsn)
-		if db.err != nil {
-			return
+// ValidatePostgresDSN returns an error unless the given DSN is a valid PostgreSQL connection URL or keyword/value
+// connection string.
+func ValidatePostgresDSN(dsn string) error {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		if _, err := pq.ParseURL(dsn); err != nil {
+			return fmt.Errorf("parse connection URL: %w", err)
 		}
-	})
-
-	return db, db.err
-}
 
-// ShouldMigrate checks if the database needs to be migrated.
-func (db *DB) ShouldMigrate(ctx context.Context, appVersion string) (bool, error) {
-	if db.client == nil {
-		return false, fmt.Errorf("no database connection")
+		return nil
 	}
 
-	config, err := db.client.AppConfig.Query().
-		Select(appconfig.FieldVersion).
-		Only(ctx)
-	if err != nil {
-		// Error most likely indicates that the database is empty. If it's something else, the migration will fail.
-		return true, nil
+	// The keywords of a connection string are only checked by the server, but a path never starts with one
+	keyword, _, found := strings.Cut(dsn, "=")
+	if !found || !slices.Contains(postgresKeywords, strings.TrimSpace(keyword)) {
+		return fmt.Errorf("neither a postgres:// URL nor a keyword/value connection string")
 	}
 
-	// 'dev' indicates that this is a development build. AutoMigrate.
-	if appVersion == "dev" || config.Version == "dev" {
-		return true, nil
+	if _, err := pq.NewConnector(dsn); err != nil {
+		return fmt.Errorf("parse connection string: %w", err)
 	}
 
-	// AutoMigrate if the app version is greater than the database version. Otherwise, don't migrate.
-	// Examples:
-	//  - appVersion: 1.0.0, dbVersion: 0.9.0 -> migrate
-	//  - appVersion: 1.0.0, dbVersion: 1.0.0 -> don't migrate
-	_appVersion, err := semver.NewVersion(appVersion)
-	if err != nil {
-		return false, fmt.Errorf("invalid app version: %w", err) // What's the best way to handle this?
-	}
+	return nil
+}
 
-	dbVersion, err := semver.NewVersion(config.Version)
-	if err != nil {
-		return false, fmt.Errorf("invalid database version: %w", err) // What's the best way to handle this?
-	}
+// IsPostgresDSN reports whether the given DSN is a valid PostgreSQL connection URL or keyword/value connection string.
+func IsPostgresDSN(dsn string) bool {
+	return ValidatePostgresDSN(dsn) == nil
+}
 
-	if _appVersion.GreaterThan(dbVersion) {
-		return true, nil
-	}
+// Connect establishes a connection to the database. PostgreSQL DSNs open a shared PostgreSQL store, all others are
+// taken as the path of a SQLite database file.
+// It uses the sync.Once.Do function to ensure that the connection is only established once.
+func (db *DB) Connect(ctx context.Context, dsn string) (*DB, error) {
+	db.connectOnce.Do(func() {
+		db.dialect = dialect.SQLite
+		if IsPostgresDSN(dsn) {
+			db.dialect = dialect.Postgres
+		} else {
+			db.path = strings.TrimPrefix(dsn, "file:")
+			dsn += "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=journal_size_limit(200000000)&_pragma=synchronous(NORMAL)&_pragma=foreign_keys(ON)&_pragma=temp_store(MEMORY)&_pragma=cache_size(-16000)"
+		}
 
-	// Otherwise, don't migrate.
-	return false, nil
-}
+		// Open database connection
+		db.driver, db.err = entsql.Open(db.dialect, dsn)
+		if db.err != nil {
+			return
+		}
 
-// AutoMigrate runs the database migration.
-func (db *DB) AutoMigrate(ctx context.Context, appVersion string) error {
-	err := db.client.Schema.Create(ctx,
-		migrate.WithDropIndex(true),
-		migrate.WithDropColumn(true),
-	)
-	if err != nil {
-		return err
-	}
+		db.client = ent.NewClient(ent.Driver(db.driver))
 
-	// Upsert app config
-	err = db.client.AppConfig.Create().
-		SetID(1).
-		SetVersion(appVersion).
-		OnConflict().
-		UpdateNewValues().
-		Exec(ctx)
-	if err != nil {
-		return fmt.Errorf("create app config: %w", err)
-	}
+		// SQLite creates the database file on demand, a PostgreSQL server has to be reachable right away
+		if db.dialect == dialect.Postgres {
+			if err := db.driver.DB().PingContext(ctx); err != nil {
+				db.err = fmt.Errorf("ping postgres store: %w", err)
+			}
+		}
+	})
 
-	return nil
+	return db, db.err
 }
 
 // Close closes the database connection.
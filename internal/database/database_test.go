@@ -0,0 +1,32 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPostgresDSN(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		dsn  string
+		want bool
+	}{
+		{"postgres://dbench@localhost:5432/dbench?sslmode=disable", true},
+		{"postgresql://localhost/dbench", true},
+		{"host=localhost port=5432 user=dbench dbname=dbench", true},
+		{"dbname = 'bench results' sslmode=disable", true},
+		{"postgres://localhost:port/dbench", false},      // Invalid port
+		{"host=localhost dbname='dbench", false},         // Unterminated quote
+		{"host=localhost client_encoding=LATIN1", false}, // Not supported by the driver
+		{"file:/home/user/.local/share/dbench/dbench.db", false},
+		{"/data/user=dbench/dbench.db", false},
+		{"dbench.db", false},
+		{"", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, IsPostgresDSN(c.dsn), c.dsn)
+	}
+}
@@ -9,18 +9,36 @@ import (
 
 	"github.com/nikoksr/dbench/ent"
 	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/systemmetricsample"
 	"github.com/nikoksr/dbench/internal/models"
 )
 
-// Fetch fetches benchmarks from the database.
+// Fetch fetches benchmarks from the database. The raw pgbench output and the system metric samples are only loaded if
+// requested by WithEdgesForCopy or WithSampleSeries.
 func (db *DB) Fetch(ctx context.Context, options ...QueryOption) ([]*models.Benchmark, error) {
-	query := applyQueryOptions(db.client.Benchmark.Query(), options...)
+	qo := newQueryOptions(options...)
 
-	return query.
+	query := qo.apply(db.client.Benchmark.Query()).
+		WithGroup().
 		WithResult().
 		WithSystemMetric().
 		WithSystem().
-		All(ctx)
+		WithWaitEvents().
+		WithProcessMetrics().
+		WithDiskMetrics().
+		WithNetworkMetrics().
+		WithTags()
+
+	if qo.LoadOutput {
+		query.WithOutput()
+	}
+	if qo.LoadSamples {
+		query.WithSystemMetricSamples(func(query *ent.SystemMetricSampleQuery) {
+			query.Order(ent.Asc(systemmetricsample.FieldSampledAt))
+		})
+	}
+
+	return query.All(ctx)
 }
 
 // FetchByIDs fetches benchmarks by their IDs.
@@ -67,6 +85,13 @@ func (db *DB) FetchByGroupIDs(ctx context.Context, ids []string, options ...Quer
 	return db.Fetch(ctx, options...)
 }
 
+// FetchIDs fetches benchmark IDs from the database.
+func (db *DB) FetchIDs(ctx context.Context, options ...QueryOption) ([]string, error) {
+	query := applyQueryOptions(db.client.Benchmark.Query(), options...)
+
+	return query.Select(benchmark.FieldID).Strings(ctx)
+}
+
 // FetchGroupIDs fetches group IDs from the database.
 func (db *DB) FetchGroupIDs(ctx context.Context, options ...QueryOption) ([]string, error) {
 	query := applyQueryOptions(db.client.Benchmark.Query(), options...)
@@ -95,10 +120,18 @@ func (db *DB) CountAll(ctx context.Context) (uint64, error) {
 
 	queries := []counter{
 		db.client.AppConfig.Query(),
+		db.client.BenchmarkGroup.Query(),
 		db.client.SystemMetric.Query(),
+		db.client.SystemMetricSample.Query(),
 		db.client.SystemConfig.Query(),
 		db.client.Benchmark.Query(),
 		db.client.BenchmarkResult.Query(),
+		db.client.BenchmarkOutput.Query(),
+		db.client.WaitEvent.Query(),
+		db.client.ProcessMetric.Query(),
+		db.client.DiskMetric.Query(),
+		db.client.NetworkMetric.Query(),
+		db.client.Tag.Query(),
 	}
 
 	var count atomic.Uint64
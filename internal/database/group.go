@@ -0,0 +1,127 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+const groupIDPrefix = "bmkgrp_"
+
+// CreateGroup creates a new benchmark group. Its ID is generated unless given.
+func (db *DB) CreateGroup(ctx context.Context, group *models.BenchmarkGroup) (*models.BenchmarkGroup, error) {
+	if group == nil {
+		return nil, fmt.Errorf("benchmark group is nil")
+	}
+
+	create := db.client.BenchmarkGroup.Create().
+		SetNillableName(group.Name).
+		SetNillableDescription(group.Description).
+		SetStatus(benchmarkgroup.StatusRunning)
+
+	if group.ID != "" {
+		create.SetID(group.ID)
+	}
+	if group.Config != nil {
+		create.SetConfig(group.Config)
+	}
+	if !group.StartedAt.IsZero() {
+		create.SetStartedAt(group.StartedAt)
+	}
+
+	return create.Save(ctx)
+}
+
+// FinishGroup sets the final status of a benchmark group and marks it as finished.
+func (db *DB) FinishGroup(ctx context.Context, id string, status benchmarkgroup.Status) error {
+	return db.client.BenchmarkGroup.UpdateOneID(pulid.ID(id)).
+		SetStatus(status).
+		SetFinishedAt(time.Now().UTC()).
+		Exec(ctx)
+}
+
+// ResolveGroupIDs resolves the given benchmark group names or IDs to IDs. Arguments with the prefix of group IDs are
+// taken as they are, all others are looked up by name.
+func (db *DB) ResolveGroupIDs(ctx context.Context, namesOrIDs []string) ([]string, error) {
+	ids := make([]string, 0, len(namesOrIDs))
+	for _, nameOrID := range namesOrIDs {
+		if strings.HasPrefix(nameOrID, groupIDPrefix) {
+			ids = append(ids, nameOrID)
+			continue
+		}
+
+		id, err := db.client.BenchmarkGroup.Query().
+			Where(benchmarkgroup.Name(nameOrID)).
+			OnlyID(ctx)
+		if ent.IsNotFound(err) {
+			return nil, fmt.Errorf("no benchmark-group with the name %q found", nameOrID)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("query benchmark-group %q: %w", nameOrID, err)
+		}
+
+		ids = append(ids, id.String())
+	}
+
+	return ids, nil
+}
+
+// ensureGroup makes sure that the group of the given benchmark exists. This is the case for benchmarks that were just
+// run, but not necessarily for imported ones. Imports that carry their group restore its name and description,
+// otherwise the group is derived from the benchmark.
+func (db *DB) ensureGroup(ctx context.Context, tx *ent.Tx, bmark *models.Benchmark) error {
+	if tx == nil {
+		return fmt.Errorf("transaction is nil")
+	}
+
+	exists, err := tx.BenchmarkGroup.Query().
+		Where(benchmarkgroup.ID(bmark.GroupID)).
+		Exist(ctx)
+	if err != nil || exists {
+		return err
+	}
+
+	create := tx.BenchmarkGroup.Create().
+		SetID(bmark.GroupID).
+		SetStatus(benchmarkgroup.StatusCompleted).
+		SetStartedAt(bmark.RecordedAt).
+		SetFinishedAt(bmark.RecordedAt)
+
+	if group := bmark.Edges.Group; group != nil {
+		create.
+			SetNillableName(group.Name).
+			SetNillableDescription(group.Description).
+			SetStatus(group.Status).
+			SetStartedAt(group.StartedAt).
+			SetNillableFinishedAt(group.FinishedAt)
+
+		if group.Config != nil {
+			create.SetConfig(group.Config)
+		}
+	}
+
+	return create.Exec(ctx)
+}
+
+// removeEmptyGroups removes those of the given groups that are finished and don't hold any benchmarks anymore.
+func removeEmptyGroups(ctx context.Context, client *ent.Client, ids []pulid.ID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	_, err := client.BenchmarkGroup.Delete().
+		Where(
+			benchmarkgroup.IDIn(ids...),
+			benchmarkgroup.Not(benchmarkgroup.HasBenchmarks()),
+			benchmarkgroup.StatusNEQ(benchmarkgroup.StatusRunning),
+		).
+		Exec(ctx)
+
+	return err
+}
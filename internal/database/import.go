@@ -0,0 +1,356 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// ConflictStrategy defines how imported benchmarks that already exist in the database are handled. A benchmark exists
+// if a benchmark with the same ID or the same content is stored.
+type ConflictStrategy string
+
+const (
+	ConflictSkip    ConflictStrategy = "skip"    // ConflictSkip keeps the existing benchmark and drops the imported one
+	ConflictReplace ConflictStrategy = "replace" // ConflictReplace replaces the existing benchmark with the imported one
+	ConflictNewID   ConflictStrategy = "new-id"  // ConflictNewID imports the benchmark anyway, under a new ID if needed
+)
+
+// ConflictStrategies returns all conflict strategies.
+func ConflictStrategies() []ConflictStrategy {
+	return []ConflictStrategy{ConflictSkip, ConflictReplace, ConflictNewID}
+}
+
+// ParseConflictStrategy parses the given conflict strategy.
+func ParseConflictStrategy(s string) (ConflictStrategy, error) {
+	for _, strategy := range ConflictStrategies() {
+		if string(strategy) == s {
+			return strategy, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid conflict strategy %q, must be one of skip, replace or new-id", s)
+}
+
+// ImportSummary summarizes what happened to the benchmarks of an import.
+type ImportSummary struct {
+	Imported   int // Benchmarks that didn't exist yet
+	Skipped    int // Existing benchmarks that were kept
+	Conflicted int // Benchmarks that were dropped because their ID is taken by a benchmark with other content
+	Replaced   int // Existing benchmarks that were replaced
+	Duplicated int // Existing benchmarks that were imported again, under a new ID if their ID was taken
+}
+
+// Add adds the counts of the given summary to the summary.
+func (s *ImportSummary) Add(other *ImportSummary) {
+	s.Imported += other.Imported
+	s.Skipped += other.Skipped
+	s.Conflicted += other.Conflicted
+	s.Replaced += other.Replaced
+	s.Duplicated += other.Duplicated
+}
+
+// importChunkSize is the number of imported benchmarks whose stored counterparts are looked up at once.
+const importChunkSize = 1000
+
+// Import saves imported benchmarks to the database. Unlike SaveMany, it preserves the IDs of the benchmarks and
+// handles benchmarks that already exist according to the given strategy. The benchmarks that are left to save are
+// inserted in bulk.
+func (db *DB) Import(ctx context.Context, bmarks []*models.Benchmark, strategy ConflictStrategy) (*ImportSummary, error) {
+	for _, bmark := range bmarks {
+		if bmark == nil {
+			return nil, fmt.Errorf("benchmark is nil")
+		}
+	}
+
+	tx, err := db.client.Tx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("start transaction: %w", err)
+	}
+
+	summary := &ImportSummary{}
+	batch := newImportBatch(len(bmarks))
+	replacedGroups := make(map[pulid.ID]bool)
+	for start := 0; start < len(bmarks); start += importChunkSize {
+		chunk := bmarks[start:min(start+importChunkSize, len(bmarks))]
+
+		stored, err := lookUpStored(ctx, tx, chunk)
+		if err != nil {
+			return nil, rollback(tx, fmt.Errorf("look up existing benchmarks: %w", err))
+		}
+
+		for _, bmark := range chunk {
+			// Benchmarks may be duplicated within the import itself, these aren't stored yet
+			if idx, found := batch.find(bmark); found {
+				batch.resolve(idx, bmark, strategy, summary)
+				continue
+			}
+
+			save, err := resolveConflict(ctx, tx, stored, bmark, strategy, summary, replacedGroups)
+			if err != nil {
+				return nil, rollback(tx, err)
+			}
+			if save {
+				batch.add(bmark)
+			}
+		}
+	}
+
+	if err := db.saveMany(ctx, tx, batch.bmarks); err != nil {
+		return nil, rollback(tx, err)
+	}
+
+	// Replaced benchmarks may leave their group behind
+	if err := removeEmptyGroups(ctx, tx.Client(), maps.Keys(replacedGroups)); err != nil {
+		return nil, rollback(tx, fmt.Errorf("remove empty groups: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return summary, nil
+}
+
+// resolveConflict handles a stored benchmark that matches the given one according to the strategy. It reports whether
+// the given benchmark has to be saved. The groups of replaced benchmarks are added to replacedGroups.
+func resolveConflict(ctx context.Context, tx *ent.Tx, stored *storedBenchmarks, bmark *models.Benchmark, strategy ConflictStrategy, summary *ImportSummary, replacedGroups map[pulid.ID]bool) (bool, error) {
+	existing := stored.find(bmark)
+	if existing == nil {
+		summary.Imported++
+		return true, nil
+	}
+
+	switch strategy {
+	case ConflictReplace:
+		if err := tx.Benchmark.DeleteOneID(existing.ID).Exec(ctx); err != nil {
+			return false, fmt.Errorf("remove existing benchmark %s: %w", existing.ID, err)
+		}
+		stored.remove(existing)
+		summary.Replaced++
+		replacedGroups[existing.GroupID] = true
+	case ConflictNewID:
+		// Only the ID of the benchmark itself has to be unique, its content may be stored twice
+		if existing.ID == bmark.ID {
+			bmark.ID = ""
+		}
+		summary.Duplicated++
+	default:
+		// The stored benchmark only matches the given one by its ID, but not by its content
+		if existing.ID == bmark.ID && !matchesFingerprint(existing, bmark) {
+			summary.Conflicted++
+		} else {
+			summary.Skipped++
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// storedBenchmarks holds the stored benchmarks that may match a chunk of imported benchmarks by their ID or content.
+type storedBenchmarks struct {
+	byID         map[pulid.ID]*models.Benchmark
+	byRecordedAt map[int64][]*models.Benchmark
+}
+
+// lookUpStored loads the stored benchmarks that have the ID or the recording time of any of the given benchmarks, along
+// with the edges that their content is compared by.
+func lookUpStored(ctx context.Context, tx *ent.Tx, bmarks []*models.Benchmark) (*storedBenchmarks, error) {
+	ids := make([]pulid.ID, 0, len(bmarks))
+	recordedAt := make([]time.Time, 0, len(bmarks))
+	for _, bmark := range bmarks {
+		if bmark.ID != "" {
+			ids = append(ids, bmark.ID)
+		}
+		recordedAt = append(recordedAt, bmark.RecordedAt)
+	}
+
+	stored := &storedBenchmarks{
+		byID:         make(map[pulid.ID]*models.Benchmark, len(ids)),
+		byRecordedAt: make(map[int64][]*models.Benchmark),
+	}
+
+	if len(ids) > 0 {
+		existing, err := tx.Benchmark.Query().
+			Where(benchmark.IDIn(ids...)).
+			WithResult().
+			WithSystem().
+			WithOutput().
+			All(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("query benchmarks by ID: %w", err)
+		}
+
+		for _, bmark := range existing {
+			stored.byID[bmark.ID] = bmark
+		}
+	}
+
+	existing, err := tx.Benchmark.Query().
+		Where(benchmark.RecordedAtIn(recordedAt...)).
+		WithResult().
+		WithSystem().
+		WithOutput().
+		All(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("query benchmarks by content: %w", err)
+	}
+
+	for _, bmark := range existing {
+		key := bmark.RecordedAt.UnixNano()
+		stored.byRecordedAt[key] = append(stored.byRecordedAt[key], bmark)
+	}
+
+	return stored, nil
+}
+
+// find returns the stored benchmark that has the same ID as the given one or, failing that, the same content. It
+// returns nil if there's no such benchmark.
+func (s *storedBenchmarks) find(bmark *models.Benchmark) *models.Benchmark {
+	if existing, found := s.byID[bmark.ID]; found && bmark.ID != "" {
+		return existing
+	}
+
+	for _, existing := range s.byRecordedAt[bmark.RecordedAt.UnixNano()] {
+		if matchesFingerprint(existing, bmark) {
+			return existing
+		}
+	}
+
+	return nil
+}
+
+// remove forgets the given stored benchmark, e.g. after it was replaced.
+func (s *storedBenchmarks) remove(bmark *models.Benchmark) {
+	delete(s.byID, bmark.ID)
+
+	key := bmark.RecordedAt.UnixNano()
+	s.byRecordedAt[key] = slices.DeleteFunc(s.byRecordedAt[key], func(existing *models.Benchmark) bool {
+		return existing.ID == bmark.ID
+	})
+}
+
+// importBatch holds the benchmarks of an import that are yet to be saved. It matches benchmarks by their ID and content
+// just like storedBenchmarks does for stored ones.
+type importBatch struct {
+	bmarks        []*models.Benchmark
+	byID          map[pulid.ID]int
+	byFingerprint map[string]int
+}
+
+func newImportBatch(size int) *importBatch {
+	return &importBatch{
+		bmarks:        make([]*models.Benchmark, 0, size),
+		byID:          make(map[pulid.ID]int, size),
+		byFingerprint: make(map[string]int, size),
+	}
+}
+
+func (b *importBatch) find(bmark *models.Benchmark) (int, bool) {
+	if idx, found := b.byID[bmark.ID]; found && bmark.ID != "" {
+		return idx, true
+	}
+
+	idx, found := b.byFingerprint[fingerprintKey(bmark)]
+	return idx, found
+}
+
+func (b *importBatch) add(bmark *models.Benchmark) {
+	b.set(len(b.bmarks), bmark)
+}
+
+func (b *importBatch) set(idx int, bmark *models.Benchmark) {
+	if idx == len(b.bmarks) {
+		b.bmarks = append(b.bmarks, bmark)
+	} else {
+		b.bmarks[idx] = bmark
+	}
+
+	if bmark.ID != "" {
+		b.byID[bmark.ID] = idx
+	}
+	b.byFingerprint[fingerprintKey(bmark)] = idx
+}
+
+// resolve handles a benchmark that matches the batched benchmark at the given index according to the strategy.
+func (b *importBatch) resolve(idx int, bmark *models.Benchmark, strategy ConflictStrategy, summary *ImportSummary) {
+	switch strategy {
+	case ConflictReplace:
+		b.set(idx, bmark)
+		summary.Replaced++
+	case ConflictNewID:
+		if b.bmarks[idx].ID == bmark.ID {
+			bmark.ID = ""
+		}
+		b.add(bmark)
+		summary.Duplicated++
+	default:
+		if b.bmarks[idx].ID == bmark.ID && fingerprintKey(b.bmarks[idx]) != fingerprintKey(bmark) {
+			summary.Conflicted++
+		} else {
+			summary.Skipped++
+		}
+	}
+}
+
+// matchesFingerprint reports whether the stored benchmark has the content that identifies the given one, regardless of
+// their IDs. A benchmark is identified by when, how and on which machine it was run, and by its raw output. The output
+// tells benchmarks apart that were imported from pgbench output files, which lack the command and runtime and may share
+// their recording time. Only fields that never change are used, so that a benchmark is still recognized after its
+// output was re-parsed. Edges that the given benchmark lacks aren't compared.
+func matchesFingerprint(existing, bmark *models.Benchmark) bool {
+	if !existing.RecordedAt.Equal(bmark.RecordedAt) || existing.Command != bmark.Command {
+		return false
+	}
+
+	if result := bmark.Edges.Result; result != nil {
+		if existing.Edges.Result == nil || existing.Edges.Result.TotalRuntime != result.TotalRuntime {
+			return false
+		}
+	}
+
+	if system := bmark.Edges.System; system != nil && system.MachineID != nil {
+		existingSystem := existing.Edges.System
+		if existingSystem == nil || existingSystem.MachineID == nil || *existingSystem.MachineID != *system.MachineID {
+			return false
+		}
+	}
+
+	if output := bmark.Edges.Output; output != nil {
+		if existing.Edges.Output == nil || !bytes.Equal(existing.Edges.Output.Stdout, output.Stdout) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fingerprintKey returns the content that matchesFingerprint compares benchmarks by as a string.
+func fingerprintKey(bmark *models.Benchmark) string {
+	key := fmt.Sprintf("%d|%s", bmark.RecordedAt.UnixNano(), bmark.Command)
+
+	if result := bmark.Edges.Result; result != nil {
+		key += fmt.Sprintf("|%d", result.TotalRuntime)
+	}
+
+	if system := bmark.Edges.System; system != nil && system.MachineID != nil {
+		key += "|" + *system.MachineID
+	}
+
+	if output := bmark.Edges.Output; output != nil {
+		key += fmt.Sprintf("|%x", sha256.Sum256(output.Stdout))
+	}
+
+	return key
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/pointer"
+	"github.com/nikoksr/dbench/internal/portability/converter"
+	"github.com/nikoksr/dbench/internal/portability/exporter"
+	"github.com/nikoksr/dbench/internal/portability/importer"
+)
+
+func TestFingerprintSurvivesCSVRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	bmarks := []*models.Benchmark{
+		{
+			ID:         pulid.ID("bmk_1"),
+			GroupID:    pulid.ID("bmkgrp_1"),
+			Comment:    pointer.To("-"),
+			Command:    "pgbench -c 8 -j 2 -T 10 postgres",
+			Clients:    8,
+			Threads:    2,
+			RecordedAt: time.Date(2024, 1, 31, 15, 4, 5, 123456789, time.UTC),
+			Edges: ent.BenchmarkEdges{
+				Result: &models.BenchmarkResult{
+					Transactions:          12345,
+					TransactionsPerSecond: 1234.56789,
+					TotalRuntime:          duration.Duration(10*time.Second + 123456789),
+				},
+				System: &models.SystemConfig{MachineID: pointer.To("machine")},
+			},
+		},
+		{
+			ID:         pulid.ID("bmk_2"),
+			GroupID:    pulid.ID("bmkgrp_1"),
+			Command:    "pgbench -c 16 -j 2 -T 10 postgres",
+			Clients:    16,
+			Threads:    2,
+			RecordedAt: time.Date(2024, 1, 31, 15, 4, 5, 987654321, time.UTC),
+			Edges: ent.BenchmarkEdges{
+				Result: &models.BenchmarkResult{TotalRuntime: duration.Duration(10 * time.Second)},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, exporter.ToCSV(&buf, converter.BenchmarksToCSV(bmarks)))
+
+	records, err := importer.FromCSV[[]*models.BenchmarkCSV](&buf)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	imported, err := converter.BenchmarksFromCSV(records)
+	if !assert.NoError(t, err) || !assert.Len(t, imported, len(bmarks)) {
+		return
+	}
+
+	for i, bmark := range bmarks {
+		assert.Equal(t, fingerprintKey(bmark), fingerprintKey(imported[i]))
+		assert.Equal(t, bmark.Comment, imported[i].Comment)
+		assert.Equal(t, bmark.Edges.Result.TransactionsPerSecond, imported[i].Edges.Result.TransactionsPerSecond)
+	}
+}
+
+func TestImportMatchesStoredBenchmarks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := New().Connect(ctx, "file:"+filepath.Join(t.TempDir(), "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	_, err = db.MigrateUp(ctx, "dev")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	newBenchmark := func(id, command string, recordedAt time.Time) *models.Benchmark {
+		return &models.Benchmark{
+			ID:         pulid.ID(id),
+			GroupID:    pulid.ID("bmkgrp_1"),
+			Command:    command,
+			RecordedAt: recordedAt,
+			Edges: ent.BenchmarkEdges{
+				Result: &models.BenchmarkResult{TotalRuntime: duration.Duration(10 * time.Second)},
+			},
+		}
+	}
+
+	_, err = db.Import(ctx, []*models.Benchmark{
+		newBenchmark("bmk_1", "pgbench -c 1", start),
+		newBenchmark("bmk_2", "pgbench -c 2", start.Add(time.Minute)),
+	}, ConflictSkip)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	summary, err := db.Import(ctx, []*models.Benchmark{
+		newBenchmark("bmk_1", "pgbench -c 1", start),                  // Same ID and content
+		newBenchmark("bmk_3", "pgbench -c 1", start),                  // Same content
+		newBenchmark("bmk_2", "pgbench -c 4", start.Add(time.Minute)), // Same ID, other content
+		newBenchmark("bmk_4", "pgbench -c 8", start.Add(time.Hour)),   // New
+	}, ConflictSkip)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &ImportSummary{Imported: 1, Skipped: 2, Conflicted: 1}, summary)
+
+	// Replacing by content swaps the stored benchmark for the imported one
+	summary, err = db.Import(ctx, []*models.Benchmark{
+		newBenchmark("bmk_5", "pgbench -c 1", start),
+	}, ConflictReplace)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &ImportSummary{Replaced: 1}, summary)
+
+	ids, err := db.client.Benchmark.Query().IDs(ctx)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []pulid.ID{"bmk_2", "bmk_4", "bmk_5"}, ids)
+}
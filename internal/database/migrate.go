@@ -0,0 +1,655 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/Masterminds/semver/v3"
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/ent/appconfig"
+)
+
+// ErrNewerDatabase is returned if the database was migrated by a newer version of dbench than the running one. Such a
+// database is refused instead of being migrated, since an older version can't know how to handle its schema.
+var ErrNewerDatabase = errors.New("the database was migrated by a newer version of dbench; update dbench or restore the backup that was taken before the migration")
+
+// migrationsTable holds the versions of the applied migrations.
+const migrationsTable = "schema_migrations"
+
+// migrationFiles holds the migrations. They are laid out as migrations/<dialect>/<version>_<name>.<up|down>.sql.
+//
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migration is a single, versioned step of the database schema. The first migration is the baseline, which creates
+// the schema of the last version of dbench that didn't version its migrations.
+type migration struct {
+	version int
+	name    string
+	up      string
+	down    string // Empty, if the migration can't be reverted
+}
+
+// MigrationStatus describes a known migration and whether it's applied.
+type MigrationStatus struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// migrations returns all migrations for the dialect of the database, ordered by version.
+func (db *DB) migrations() ([]migration, error) {
+	return loadMigrations(db.dialect)
+}
+
+// loadMigrations loads the migrations of the given dialect, ordered by version. Every migration needs an up file, the
+// down file is optional.
+func loadMigrations(dialectName string) ([]migration, error) {
+	dir := path.Join("migrations", dialectName)
+
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".sql") {
+			continue
+		}
+
+		// E.g. 20240131120000_add_benchmark_notes.up.sql
+		base, direction, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), ".")
+		rawVersion, migrationName, found := strings.Cut(base, "_")
+		if !ok || !found || (direction != "up" && direction != "down") {
+			return nil, fmt.Errorf("invalid migration file name %q", name)
+		}
+
+		version, err := strconv.Atoi(rawVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration file %q: %w", name, err)
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read migration file %q: %w", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: migrationName}
+			byVersion[version] = m
+		}
+		if m.name != migrationName {
+			return nil, fmt.Errorf("duplicate migration version %d", version)
+		}
+
+		if direction == "up" {
+			m.up = string(content)
+		} else {
+			m.down = string(content)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.up == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", m.version, m.name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	slices.SortFunc(migrations, func(a, b migration) int {
+		return a.version - b.version
+	})
+
+	return migrations, nil
+}
+
+// runMigration applies or reverts the given migration and records it within the same transaction. Some changes of
+// SQLite databases rebuild tables that others refer to, so their foreign keys are only checked once all statements ran.
+func (db *DB) runMigration(ctx context.Context, m migration, up bool) (err error) {
+	statements := m.up
+	if !up {
+		statements = m.down
+	}
+
+	// Pragmas apply to a single connection and can't be changed within a transaction
+	conn, err := db.driver.DB().Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if db.dialect == dialect.SQLite {
+		if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+			return fmt.Errorf("disable foreign keys: %w", err)
+		}
+		defer func() {
+			if _, ferr := conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys = ON"); ferr != nil {
+				err = errors.Join(err, fmt.Errorf("enable foreign keys: %w", ferr))
+			}
+		}()
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("start transaction: %w", err)
+	}
+
+	if err := db.runStatements(ctx, tx, m, statements, up); err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			err = fmt.Errorf("%w: %v", err, rerr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// runStatements runs the statements of the given migration within the given transaction and records the migration.
+func (db *DB) runStatements(ctx context.Context, tx *sql.Tx, m migration, statements string, up bool) error {
+	if _, err := tx.ExecContext(ctx, statements); err != nil {
+		return err
+	}
+
+	if db.dialect == dialect.SQLite {
+		if err := checkForeignKeys(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	return db.recordMigration(ctx, tx, m, up)
+}
+
+// checkForeignKeys returns an error if any row of a SQLite database refers to a row that doesn't exist.
+func checkForeignKeys(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, "PRAGMA foreign_key_check")
+	if err != nil {
+		return fmt.Errorf("check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var (
+			table, parent string
+			rowID         sql.NullInt64
+			fkID          int
+		)
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return fmt.Errorf("scan foreign key violation: %w", err)
+		}
+
+		return fmt.Errorf("rows of table %s refer to missing rows of table %s", table, parent)
+	}
+
+	return rows.Err()
+}
+
+// ensureMigrationsTable creates the table of applied migrations if it doesn't exist yet.
+func (db *DB) ensureMigrationsTable(ctx context.Context) error {
+	_, err := db.driver.DB().ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationsTable+
+		" (version BIGINT PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("create migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// tableExists reports whether the table with the given name exists.
+func (db *DB) tableExists(ctx context.Context, name string) (bool, error) {
+	query := "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?"
+	if db.dialect == dialect.Postgres {
+		query = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = $1"
+	}
+
+	var count int
+	if err := db.driver.DB().QueryRowContext(ctx, query, name).Scan(&count); err != nil {
+		return false, fmt.Errorf("look up table %s: %w", name, err)
+	}
+
+	return count > 0, nil
+}
+
+// appliedMigrations returns the applied migrations by version. It doesn't change the database, so no migration is
+// applied if the table of applied migrations doesn't exist yet.
+func (db *DB) appliedMigrations(ctx context.Context) (map[int]MigrationStatus, error) {
+	exists, err := db.tableExists(ctx, migrationsTable)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return map[int]MigrationStatus{}, nil
+	}
+
+	query, args := entsql.Dialect(db.dialect).
+		Select("version", "name", "applied_at").
+		From(entsql.Table(migrationsTable)).
+		Query()
+
+	rows, err := db.driver.DB().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]MigrationStatus)
+	for rows.Next() {
+		var (
+			status    = MigrationStatus{Applied: true}
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&status.Version, &status.Name, &appliedAt); err != nil {
+			return nil, fmt.Errorf("scan applied migration: %w", err)
+		}
+
+		status.AppliedAt = &appliedAt
+		applied[status.Version] = status
+	}
+
+	return applied, rows.Err()
+}
+
+// execer executes statements, e.g. a database or a transaction.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// recordMigration marks the given migration as applied or, when reverted, removes its mark.
+func (db *DB) recordMigration(ctx context.Context, exec execer, m migration, applied bool) error {
+	var (
+		query string
+		args  []any
+	)
+
+	if applied {
+		query, args = entsql.Dialect(db.dialect).
+			Insert(migrationsTable).
+			Columns("version", "name", "applied_at").
+			Values(m.version, m.name, time.Now().UTC()).
+			Query()
+	} else {
+		query, args = entsql.Dialect(db.dialect).
+			Delete(migrationsTable).
+			Where(entsql.EQ("version", m.version)).
+			Query()
+	}
+
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("record migration %d: %w", m.version, err)
+	}
+
+	return nil
+}
+
+// checkNotNewer returns ErrNewerDatabase if any of the applied migrations is unknown to this version of dbench.
+func checkNotNewer(known []migration, applied map[int]MigrationStatus) error {
+	latest := 0
+	if len(known) > 0 {
+		latest = known[len(known)-1].version
+	}
+
+	for version := range applied {
+		if version > latest {
+			return ErrNewerDatabase
+		}
+	}
+
+	return nil
+}
+
+// MigrationStatus returns the status of all migrations that this version of dbench knows about.
+func (db *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	known, err := db.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(known))
+	for _, m := range known {
+		status, ok := applied[m.version]
+		if !ok {
+			status = MigrationStatus{Version: m.version, Name: m.name}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, checkNotNewer(known, applied)
+}
+
+// pendingMigrations returns the known migrations that aren't applied yet. It returns ErrNewerDatabase if the database
+// was migrated by a newer version of dbench.
+func (db *DB) pendingMigrations(ctx context.Context) ([]migration, error) {
+	known, err := db.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotNewer(known, applied); err != nil {
+		return nil, err
+	}
+
+	var pending []migration
+	for _, m := range known {
+		if _, ok := applied[m.version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+
+	return pending, nil
+}
+
+// ShouldMigrate checks if the database needs to be migrated. It returns ErrNewerDatabase if the database was migrated
+// by a newer version of dbench.
+func (db *DB) ShouldMigrate(ctx context.Context, appVersion string) (bool, error) {
+	if db.client == nil {
+		return false, fmt.Errorf("no database connection")
+	}
+
+	pending, err := db.pendingMigrations(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if len(pending) > 0 {
+		return true, nil
+	}
+
+	config, err := db.client.AppConfig.Query().
+		Select(appconfig.FieldVersion).
+		Only(ctx)
+	if err != nil {
+		// Error most likely indicates that the app config is missing. If it's something else, the migration will fail.
+		return true, nil
+	}
+
+	// 'dev' indicates a development build. Its version isn't comparable, so it's only recorded along with migrations.
+	if appVersion == "dev" {
+		return false, nil
+	}
+	if config.Version == "dev" {
+		return true, nil
+	}
+
+	// Record the app version if it's greater than the database version, so that we know which version of dbench
+	// migrated the database last.
+	_appVersion, err := semver.NewVersion(appVersion)
+	if err != nil {
+		return false, fmt.Errorf("invalid app version: %w", err)
+	}
+
+	dbVersion, err := semver.NewVersion(config.Version)
+	if err != nil {
+		return false, fmt.Errorf("invalid database version: %w", err)
+	}
+
+	return _appVersion.GreaterThan(dbVersion), nil
+}
+
+// MigrateUp applies all pending migrations and returns the number of applied migrations.
+func (db *DB) MigrateUp(ctx context.Context, appVersion string) (int, error) {
+	unlock, err := db.lockMigration(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := db.ensureMigrationsTable(ctx); err != nil {
+		return 0, err
+	}
+
+	known, err := db.migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := checkNotNewer(known, applied); err != nil {
+		return 0, err
+	}
+
+	// Databases that were created before migrations were versioned already have the schema of the baseline
+	if len(applied) == 0 && len(known) > 0 {
+		legacy, err := db.tableExists(ctx, "benchmarks")
+		if err != nil {
+			return 0, err
+		}
+
+		if legacy {
+			if err := db.recordMigration(ctx, db.driver.DB(), known[0], true); err != nil {
+				return 0, err
+			}
+			applied[known[0].version] = MigrationStatus{Version: known[0].version, Name: known[0].name, Applied: true}
+		}
+	}
+
+	count := 0
+	for _, m := range known {
+		if _, ok := applied[m.version]; ok {
+			continue
+		}
+
+		if err := db.runMigration(ctx, m, true); err != nil {
+			return count, fmt.Errorf("apply migration %d (%s): %w", m.version, m.name, err)
+		}
+
+		count++
+	}
+
+	// Upsert app config
+	err = db.client.AppConfig.Create().
+		SetID(1).
+		SetVersion(appVersion).
+		OnConflict().
+		UpdateNewValues().
+		Exec(ctx)
+	if err != nil {
+		return count, fmt.Errorf("create app config: %w", err)
+	}
+
+	return count, nil
+}
+
+// MigrateDown reverts the given number of most recently applied migrations and returns the number of reverted
+// migrations.
+func (db *DB) MigrateDown(ctx context.Context, steps int) (int, error) {
+	unlock, err := db.lockMigration(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	known, err := db.migrations()
+	if err != nil {
+		return 0, err
+	}
+
+	applied, err := db.appliedMigrations(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := checkNotNewer(known, applied); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(known) - 1; i >= 0 && count < steps; i-- {
+		m := known[i]
+		if _, ok := applied[m.version]; !ok {
+			continue
+		}
+
+		if m.down == "" {
+			return count, fmt.Errorf("migration %d (%s) can't be reverted", m.version, m.name)
+		}
+
+		if err := db.runMigration(ctx, m, false); err != nil {
+			return count, fmt.Errorf("revert migration %d (%s): %w", m.version, m.name, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// AutoMigrate applies all pending migrations. The database is backed up first if any migration is pending. It returns
+// the path of the backup, which is empty if no backup was taken.
+func (db *DB) AutoMigrate(ctx context.Context, appVersion string) (string, error) {
+	pending, err := db.pendingMigrations(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Recording a newer version of dbench doesn't change the schema, there's nothing to lose
+	var backupPath string
+	if len(pending) > 0 {
+		if backupPath, err = db.BackupBeforeMigration(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := db.MigrateUp(ctx, appVersion); err != nil {
+		return backupPath, err
+	}
+
+	return backupPath, nil
+}
+
+// migrationBackupSuffix is the suffix of the backups that are taken before migrations.
+const migrationBackupSuffix = ".migration.bak"
+
+// migrationBackupsKept is the number of backups before migrations that are kept, older ones are removed.
+const migrationBackupsKept = 3
+
+// BackupBeforeMigration backs up a SQLite database that holds any data and returns the path of the backup. The path is
+// empty if no backup was taken. Only the most recent of these backups are kept. Shared PostgreSQL stores are backed up
+// by their operators.
+func (db *DB) BackupBeforeMigration(ctx context.Context) (string, error) {
+	if db.dialect != dialect.SQLite {
+		return "", nil
+	}
+
+	var tables int
+	err := db.driver.DB().QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('"+migrationsTable+"', 'sqlite_sequence')",
+	).Scan(&tables)
+	if err != nil {
+		return "", fmt.Errorf("inspect database: %w", err)
+	}
+
+	// Nothing to lose in a fresh database
+	if tables == 0 {
+		return "", nil
+	}
+
+	backupPath := fmt.Sprintf("%s.%s%s", db.path, time.Now().UTC().Format("20060102T150405Z"), migrationBackupSuffix)
+	if err := db.Backup(ctx, backupPath); err != nil {
+		return "", fmt.Errorf("back up database before migration: %w", err)
+	}
+
+	if err := removeOldMigrationBackups(db.path, migrationBackupsKept); err != nil {
+		return backupPath, err
+	}
+
+	return backupPath, nil
+}
+
+// removeOldMigrationBackups removes all but the given number of most recent backups of the database at the given path
+// that were taken before migrations.
+func removeOldMigrationBackups(dbPath string, keep int) error {
+	dir, prefix := filepath.Dir(dbPath), filepath.Base(dbPath)+"."
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("list migration backups: %w", err)
+	}
+
+	// The timestamps in their names sort the backups from oldest to newest
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, migrationBackupSuffix) {
+			backups = append(backups, name)
+		}
+	}
+	slices.Sort(backups)
+
+	for len(backups) > keep {
+		if err := os.Remove(filepath.Join(dir, backups[0])); err != nil {
+			return fmt.Errorf("remove old migration backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+
+	return nil
+}
+
+// migrationLockID is the key of the PostgreSQL advisory lock that is held while migrating a shared store.
+const migrationLockID = 0x6462656e6368 // "dbench"
+
+// lockMigration makes sure that only one client at a time migrates a shared PostgreSQL store. The returned function
+// releases the lock. SQLite stores are private to a single client and aren't locked.
+func (db *DB) lockMigration(ctx context.Context) (func(), error) {
+	if db.dialect != dialect.Postgres {
+		return func() {}, nil
+	}
+
+	// Advisory locks are bound to a session, so lock and unlock have to use the same connection
+	conn, err := db.driver.DB().Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get connection: %w", err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("acquire migration lock: %w", err)
+	}
+
+	unlock := func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLockID)
+		_ = conn.Close()
+	}
+
+	return unlock, nil
+}
@@ -0,0 +1,231 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+)
+
+const insertLegacyBenchmark = `INSERT INTO benchmarks (id, group_id, recorded_at, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`
+
+func TestMigrateUpBackfillsGroupsOfLegacyBenchmarks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := New().Connect(ctx, "file:"+filepath.Join(t.TempDir(), "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	legacy := []struct {
+		id, groupID string
+		recordedAt  time.Time
+	}{
+		{"bmk_01", "bmkgrp_01", start},
+		{"bmk_02", "bmkgrp_01", start.Add(time.Minute)},
+		{"bmk_03", "bmkgrp_02", start.Add(time.Hour)},
+	}
+
+	// Databases of versions before migrations were versioned have the schema of the baseline, but no record of it
+	known, err := loadMigrations(dialect.SQLite)
+	if !assert.NoError(t, err) || !assert.NotEmpty(t, known) {
+		return
+	}
+
+	_, err = db.driver.DB().ExecContext(ctx, known[0].up)
+	assert.NoError(t, err)
+
+	for _, b := range legacy {
+		_, err = db.driver.DB().ExecContext(ctx, insertLegacyBenchmark, b.id, b.groupID, b.recordedAt, b.recordedAt, b.recordedAt)
+		assert.NoError(t, err)
+	}
+
+	_, err = db.MigrateUp(ctx, "dev")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	groups, err := db.client.BenchmarkGroup.Query().
+		Order(ent.Asc(benchmarkgroup.FieldID)).
+		All(ctx)
+	if !assert.NoError(t, err) || !assert.Len(t, groups, 2) {
+		return
+	}
+
+	// Each group spans the time its benchmarks were recorded at
+	assert.Equal(t, pulid.ID("bmkgrp_01"), groups[0].ID)
+	assert.Equal(t, benchmarkgroup.StatusCompleted, groups[0].Status)
+	assert.True(t, start.Equal(groups[0].StartedAt))
+	if assert.NotNil(t, groups[0].FinishedAt) {
+		assert.True(t, start.Add(time.Minute).Equal(*groups[0].FinishedAt))
+	}
+	assert.Equal(t, pulid.ID("bmkgrp_02"), groups[1].ID)
+
+	// The benchmarks kept their groups, and new ones can't refer to a missing group anymore
+	count, err := db.client.Benchmark.Query().Count(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, len(legacy), count)
+
+	_, err = db.driver.DB().ExecContext(ctx, insertLegacyBenchmark, "bmk_04", "bmkgrp_02", start, start, start)
+	assert.NoError(t, err)
+
+	_, err = db.driver.DB().ExecContext(ctx, insertLegacyBenchmark, "bmk_05", "bmkgrp_03", start, start, start)
+	assert.Error(t, err)
+}
+
+func TestMigrateDownRevertsAllMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := New().Connect(ctx, "file:"+filepath.Join(t.TempDir(), "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	known, err := db.migrations()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	applied, err := db.MigrateUp(ctx, "dev")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, len(known), applied)
+
+	reverted, err := db.MigrateDown(ctx, len(known))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, len(known), reverted)
+
+	// Only the table of applied migrations is left
+	for _, table := range []string{"benchmarks", "benchmark_groups", "app_configs"} {
+		exists, err := db.tableExists(ctx, table)
+		assert.NoError(t, err)
+		assert.False(t, exists, table)
+	}
+
+	// The reverted migrations can be applied again
+	applied, err = db.MigrateUp(ctx, "dev")
+	assert.NoError(t, err)
+	assert.Equal(t, len(known), applied)
+}
+
+func TestMigrationsOfAllDialectsMatch(t *testing.T) {
+	t.Parallel()
+
+	sqlite, err := loadMigrations(dialect.SQLite)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	postgres, err := loadMigrations(dialect.Postgres)
+	if !assert.NoError(t, err) || !assert.Len(t, postgres, len(sqlite)) {
+		return
+	}
+
+	for i := range sqlite {
+		assert.Equal(t, sqlite[i].version, postgres[i].version)
+		assert.Equal(t, sqlite[i].name, postgres[i].name)
+		assert.NotEmpty(t, sqlite[i].down, sqlite[i].name)
+		assert.NotEmpty(t, postgres[i].down, postgres[i].name)
+	}
+}
+
+func TestShouldMigrateDoesNotChangeTheDatabase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	db, err := New().Connect(ctx, "file:"+filepath.Join(t.TempDir(), "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	shouldMigrate, err := db.ShouldMigrate(ctx, "1.0.0")
+	assert.NoError(t, err)
+	assert.True(t, shouldMigrate)
+
+	exists, err := db.tableExists(ctx, migrationsTable)
+	assert.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestRemoveOldMigrationBackups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "dbench.db")
+
+	names := []string{
+		"dbench.db.20240101T000000Z.migration.bak",
+		"dbench.db.20240201T000000Z.migration.bak",
+		"dbench.db.20240301T000000Z.migration.bak",
+		"dbench.db.20240401T000000Z.migration.bak",
+		"dbench.db.20240101T000000Z.bak", // Taken before a restore
+		"other.db.20240101T000000Z.migration.bak",
+	}
+	for _, name := range names {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	assert.NoError(t, removeOldMigrationBackups(dbPath, 2))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+
+	assert.ElementsMatch(t, []string{
+		"dbench.db.20240301T000000Z.migration.bak",
+		"dbench.db.20240401T000000Z.migration.bak",
+		"dbench.db.20240101T000000Z.bak",
+		"other.db.20240101T000000Z.migration.bak",
+	}, left)
+}
+
+func TestAutoMigrateOnlyBacksUpPendingMigrations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	db, err := New().Connect(ctx, "file:"+filepath.Join(dir, "dbench.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	// A fresh database holds nothing to back up
+	backupPath, err := db.AutoMigrate(ctx, "dev")
+	assert.NoError(t, err)
+	assert.Empty(t, backupPath)
+
+	// Nothing is pending anymore
+	backupPath, err = db.AutoMigrate(ctx, "dev")
+	assert.NoError(t, err)
+	assert.Empty(t, backupPath)
+
+	shouldMigrate, err := db.ShouldMigrate(ctx, "dev")
+	assert.NoError(t, err)
+	assert.False(t, shouldMigrate)
+}
@@ -0,0 +1,50 @@
+-- create "app_configs" table
+CREATE TABLE "app_configs" ("id" bigint NOT NULL GENERATED BY DEFAULT AS IDENTITY, "version" character varying NOT NULL DEFAULT 'dev', "initial_version" character varying NULL DEFAULT 'dev', "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "appconfig_version" to table: "app_configs"
+CREATE INDEX "appconfig_version" ON "app_configs" ("version");
+-- set comment to table: "app_configs"
+COMMENT ON TABLE "app_configs" IS 'AppConfig holds the configuration of the application. There can only be one AppConfig in the database. It''s name is always ''self''.';
+-- set comment to column: "version" on table: "app_configs"
+COMMENT ON COLUMN "app_configs"."version" IS 'Version of the app itself that created this schema. We use it to check if the schema is compatible with the current version of dbench and whether a migration is required.';
+-- set comment to column: "initial_version" on table: "app_configs"
+COMMENT ON COLUMN "app_configs"."initial_version" IS 'Version of the app itself that originally created this schema.';
+-- create "benchmarks" table
+CREATE TABLE "benchmarks" ("id" character varying NOT NULL, "group_id" character varying NOT NULL, "comment" character varying NULL, "version" character varying NULL, "command" character varying NULL, "transaction_type" character varying NULL, "scaling_factor" double precision NULL, "query_mode" character varying NULL, "clients" bigint NULL, "threads" bigint NULL, "recorded_at" timestamptz NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "benchmark_group_id_id" to table: "benchmarks"
+CREATE INDEX "benchmark_group_id_id" ON "benchmarks" ("group_id", "id");
+-- create index "benchmark_group_id" to table: "benchmarks"
+CREATE INDEX "benchmark_group_id" ON "benchmarks" ("group_id");
+-- create index "benchmark_clients" to table: "benchmarks"
+CREATE INDEX "benchmark_clients" ON "benchmarks" ("clients");
+-- create index "benchmark_recorded_at" to table: "benchmarks"
+CREATE INDEX "benchmark_recorded_at" ON "benchmarks" ("recorded_at");
+-- set comment to table: "benchmarks"
+COMMENT ON TABLE "benchmarks" IS 'Benchmarks are the main entity of this application. They represent a single pgbench run. This table stores the config of the run.';
+-- create "benchmark_results" table
+CREATE TABLE "benchmark_results" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "transactions" bigint NULL, "failed_transactions" bigint NULL, "transactions_per_second" double precision NULL, "average_latency" bigint NULL, "connection_time" bigint NULL, "total_runtime" bigint NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "benchmark_results_benchmarks_result" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "benchmark_results_benchmark_id_key" to table: "benchmark_results"
+CREATE UNIQUE INDEX "benchmark_results_benchmark_id_key" ON "benchmark_results" ("benchmark_id");
+-- set comment to table: "benchmark_results"
+COMMENT ON TABLE "benchmark_results" IS 'Benchmark results are the produced and parsed results of a benchmark (pgbench) run. It is a one-to-one relation with the benchmark.';
+-- create "system_configs" table
+CREATE TABLE "system_configs" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "machine_id" character varying NULL, "os_name" character varying NULL, "os_arch" character varying NULL, "cpu_vendor" character varying NULL, "cpu_model" character varying NULL, "cpu_count" bigint NULL, "cpu_cores" bigint NULL, "cpu_threads" bigint NULL, "ram_physical" bigint NULL, "ram_usable" bigint NULL, "disk_count" bigint NULL, "disk_space_total" bigint NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "system_configs_benchmarks_system" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "system_configs_benchmark_id_key" to table: "system_configs"
+CREATE UNIQUE INDEX "system_configs_benchmark_id_key" ON "system_configs" ("benchmark_id");
+-- create index "systemconfig_machine_id" to table: "system_configs"
+CREATE INDEX "systemconfig_machine_id" ON "system_configs" ("machine_id");
+-- set comment to table: "system_configs"
+COMMENT ON TABLE "system_configs" IS 'SystemConfig holds detailed information about the system the benchmark was run on.';
+-- set comment to column: "cpu_vendor" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cpu_vendor" IS 'In case of a multi-CPU system, this is the vendor of the first CPU.';
+-- set comment to column: "cpu_model" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cpu_model" IS 'In case of a multi-CPU system, this is the model of the first CPU.';
+-- set comment to column: "ram_physical" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."ram_physical" IS 'The amount of RAM in bytes that is contained in all memory banks (DIMMs) that are attached to the motherboard. This is the total amount of RAM that is physically installed in the system.';
+-- set comment to column: "ram_usable" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."ram_usable" IS 'The amount of physical RAM in bytes that is usable by the operating system. This is the total amount of RAM that is physically installed in the system minus the amount of RAM that is reserved for the system.';
+-- create "system_metrics" table
+CREATE TABLE "system_metrics" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "cpu_min_load" numeric(7,2) NOT NULL, "cpu_max_load" numeric(7,2) NOT NULL, "cpu_average_load" numeric(7,2) NOT NULL, "cpu_50th_load" numeric(7,2) NOT NULL, "cpu_75th_load" numeric(7,2) NOT NULL, "cpu_90th_load" numeric(7,2) NOT NULL, "cpu_95th_load" numeric(7,2) NOT NULL, "cpu_99th_load" numeric(7,2) NOT NULL, "memory_min_load" numeric(7,2) NOT NULL, "memory_max_load" numeric(7,2) NOT NULL, "memory_average_load" numeric(7,2) NOT NULL, "memory_50th_load" numeric(7,2) NOT NULL, "memory_75th_load" numeric(7,2) NOT NULL, "memory_90th_load" numeric(7,2) NOT NULL, "memory_95th_load" numeric(7,2) NOT NULL, "memory_99th_load" numeric(7,2) NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "system_metrics_benchmarks_system_metric" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "system_metrics_benchmark_id_key" to table: "system_metrics"
+CREATE UNIQUE INDEX "system_metrics_benchmark_id_key" ON "system_metrics" ("benchmark_id");
+-- set comment to table: "system_metrics"
+COMMENT ON TABLE "system_metrics" IS 'System metrics are the system metrics of the host system we polled while the benchmark was running. It is a one-to-one relation to the benchmark table. We probe for CPU and memory usage while the benchmark is running, calculate the median and percentiles and store them here.';
@@ -0,0 +1,26 @@
+-- reverse: create "benchmark_tags" table
+DROP TABLE "benchmark_tags";
+-- reverse: create "tags" table
+DROP TABLE "tags";
+-- reverse: create "network_metrics" table
+DROP TABLE "network_metrics";
+-- reverse: create "disk_metrics" table
+DROP TABLE "disk_metrics";
+-- reverse: create "process_metrics" table
+DROP TABLE "process_metrics";
+-- reverse: create "wait_events" table
+DROP TABLE "wait_events";
+-- reverse: create "system_metric_samples" table
+DROP TABLE "system_metric_samples";
+-- reverse: create "benchmark_outputs" table
+DROP TABLE "benchmark_outputs";
+-- reverse: set comment to table: "system_metrics"
+COMMENT ON TABLE "system_metrics" IS 'System metrics are the system metrics of the host system we polled while the benchmark was running. It is a one-to-one relation to the benchmark table. We probe for CPU and memory usage while the benchmark is running, calculate the median and percentiles and store them here.';
+-- reverse: modify "system_metrics" table
+ALTER TABLE "system_metrics" DROP COLUMN "swap_99th_load", DROP COLUMN "swap_95th_load", DROP COLUMN "swap_90th_load", DROP COLUMN "swap_75th_load", DROP COLUMN "swap_50th_load", DROP COLUMN "swap_average_load", DROP COLUMN "swap_max_load", DROP COLUMN "swap_min_load", DROP COLUMN "iowait_99th_load", DROP COLUMN "iowait_95th_load", DROP COLUMN "iowait_90th_load", DROP COLUMN "iowait_75th_load", DROP COLUMN "iowait_50th_load", DROP COLUMN "iowait_average_load", DROP COLUMN "iowait_max_load", DROP COLUMN "iowait_min_load", DROP COLUMN "load_average_99th", DROP COLUMN "load_average_95th", DROP COLUMN "load_average_90th", DROP COLUMN "load_average_75th", DROP COLUMN "load_average_50th", DROP COLUMN "load_average_average", DROP COLUMN "load_average_max", DROP COLUMN "load_average_min";
+-- reverse: modify "system_configs" table
+ALTER TABLE "system_configs" DROP COLUMN "cgroup_io_limits", DROP COLUMN "cgroup_memory_limit", DROP COLUMN "cgroup_cpu_quota", DROP COLUMN "cgroup_version", DROP COLUMN "disk_type", DROP COLUMN "data_dir_mount_options", DROP COLUMN "data_dir_filesystem", DROP COLUMN "transparent_huge_pages", DROP COLUMN "numa_nodes", DROP COLUMN "cpu_governor", DROP COLUMN "kernel_version";
+-- reverse: modify "benchmarks" table
+ALTER TABLE "benchmarks" DROP CONSTRAINT "benchmarks_benchmark_groups_benchmarks";
+-- reverse: create "benchmark_groups" table
+DROP TABLE "benchmark_groups";
@@ -0,0 +1,126 @@
+-- create "benchmark_groups" table
+CREATE TABLE "benchmark_groups" ("id" character varying NOT NULL, "name" character varying NULL, "description" character varying NULL, "config" jsonb NULL, "status" character varying NOT NULL DEFAULT 'running', "started_at" timestamptz NOT NULL, "finished_at" timestamptz NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "benchmark_groups_name_key" to table: "benchmark_groups"
+CREATE UNIQUE INDEX "benchmark_groups_name_key" ON "benchmark_groups" ("name");
+-- create index "benchmarkgroup_started_at" to table: "benchmark_groups"
+CREATE INDEX "benchmarkgroup_started_at" ON "benchmark_groups" ("started_at");
+-- set comment to table: "benchmark_groups"
+COMMENT ON TABLE "benchmark_groups" IS 'Benchmark groups represent a single dbench run, i.e. a series of pgbench runs with different client counts. It is a one-to-many relation to the benchmark table.';
+-- set comment to column: "name" on table: "benchmark_groups"
+COMMENT ON COLUMN "benchmark_groups"."name" IS 'A unique, human-readable name that can be used instead of the ID.';
+-- set comment to column: "config" on table: "benchmark_groups"
+COMMENT ON COLUMN "benchmark_groups"."config" IS 'A snapshot of the configuration the group was run with. Groups that were imported or recorded before groups were stored don''t have one.';
+-- set comment to column: "status" on table: "benchmark_groups"
+COMMENT ON COLUMN "benchmark_groups"."status" IS 'The completion status of the run. Groups that were recorded before groups were stored are considered completed.';
+-- create the groups of the existing benchmarks
+INSERT INTO "benchmark_groups" ("id", "status", "started_at", "finished_at", "created_at", "updated_at") SELECT "group_id", 'completed', MIN("recorded_at"), MAX("recorded_at"), MIN("created_at"), MAX("updated_at") FROM "benchmarks" GROUP BY "group_id";
+-- modify "benchmarks" table
+ALTER TABLE "benchmarks" ADD CONSTRAINT "benchmarks_benchmark_groups_benchmarks" FOREIGN KEY ("group_id") REFERENCES "benchmark_groups" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- modify "system_configs" table
+ALTER TABLE "system_configs" ADD COLUMN "kernel_version" character varying NULL, ADD COLUMN "cpu_governor" character varying NULL, ADD COLUMN "numa_nodes" bigint NULL, ADD COLUMN "transparent_huge_pages" character varying NULL, ADD COLUMN "data_dir_filesystem" character varying NULL, ADD COLUMN "data_dir_mount_options" character varying NULL, ADD COLUMN "disk_type" character varying NULL, ADD COLUMN "cgroup_version" smallint NULL, ADD COLUMN "cgroup_cpu_quota" double precision NULL, ADD COLUMN "cgroup_memory_limit" bigint NULL, ADD COLUMN "cgroup_io_limits" character varying NULL;
+-- set comment to column: "cpu_governor" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cpu_governor" IS 'The CPU frequency scaling governor of the first CPU, e.g. performance or powersave.';
+-- set comment to column: "transparent_huge_pages" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."transparent_huge_pages" IS 'The transparent huge pages setting, i.e. always, madvise or never.';
+-- set comment to column: "data_dir_filesystem" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."data_dir_filesystem" IS 'The filesystem of the mount that holds the PostgreSQL data directory, e.g. ext4 or xfs.';
+-- set comment to column: "data_dir_mount_options" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."data_dir_mount_options" IS 'The mount options of the mount that holds the PostgreSQL data directory.';
+-- set comment to column: "disk_type" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."disk_type" IS 'The type of the disk that holds the PostgreSQL data directory, i.e. hdd, ssd or nvme. If the data directory is unknown, this is a comma separated list of the types of all disks.';
+-- set comment to column: "cgroup_cpu_quota" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cgroup_cpu_quota" IS 'The number of CPUs the cgroup may use, e.g. 1.5.';
+-- set comment to column: "cgroup_memory_limit" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cgroup_memory_limit" IS 'The memory limit of the cgroup in bytes.';
+-- set comment to column: "cgroup_io_limits" on table: "system_configs"
+COMMENT ON COLUMN "system_configs"."cgroup_io_limits" IS 'The IO throttles of the cgroup per device in the format of io.max, separated by commas, e.g. 8:0 rbps=1048576 wiops=100.';
+-- modify "system_metrics" table
+ALTER TABLE "system_metrics" ADD COLUMN "load_average_min" numeric(7,2) NULL, ADD COLUMN "load_average_max" numeric(7,2) NULL, ADD COLUMN "load_average_average" numeric(7,2) NULL, ADD COLUMN "load_average_50th" numeric(7,2) NULL, ADD COLUMN "load_average_75th" numeric(7,2) NULL, ADD COLUMN "load_average_90th" numeric(7,2) NULL, ADD COLUMN "load_average_95th" numeric(7,2) NULL, ADD COLUMN "load_average_99th" numeric(7,2) NULL, ADD COLUMN "iowait_min_load" numeric(7,2) NULL, ADD COLUMN "iowait_max_load" numeric(7,2) NULL, ADD COLUMN "iowait_average_load" numeric(7,2) NULL, ADD COLUMN "iowait_50th_load" numeric(7,2) NULL, ADD COLUMN "iowait_75th_load" numeric(7,2) NULL, ADD COLUMN "iowait_90th_load" numeric(7,2) NULL, ADD COLUMN "iowait_95th_load" numeric(7,2) NULL, ADD COLUMN "iowait_99th_load" numeric(7,2) NULL, ADD COLUMN "swap_min_load" numeric(7,2) NULL, ADD COLUMN "swap_max_load" numeric(7,2) NULL, ADD COLUMN "swap_average_load" numeric(7,2) NULL, ADD COLUMN "swap_50th_load" numeric(7,2) NULL, ADD COLUMN "swap_75th_load" numeric(7,2) NULL, ADD COLUMN "swap_90th_load" numeric(7,2) NULL, ADD COLUMN "swap_95th_load" numeric(7,2) NULL, ADD COLUMN "swap_99th_load" numeric(7,2) NULL;
+-- set comment to table: "system_metrics"
+COMMENT ON TABLE "system_metrics" IS 'System metrics are the system metrics of the host system we polled while the benchmark was running. It is a one-to-one relation to the benchmark table. We probe for CPU, memory, load average, IO wait and swap usage while the benchmark is running, calculate the median and percentiles and store them here.';
+-- create "benchmark_outputs" table
+CREATE TABLE "benchmark_outputs" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "compression" character varying NOT NULL DEFAULT 'zstd', "stdout" bytea NOT NULL, "stderr" bytea NOT NULL, "parser_version" bigint NOT NULL DEFAULT 0, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "benchmark_outputs_benchmarks_output" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "benchmark_outputs_benchmark_id_key" to table: "benchmark_outputs"
+CREATE UNIQUE INDEX "benchmark_outputs_benchmark_id_key" ON "benchmark_outputs" ("benchmark_id");
+-- set comment to table: "benchmark_outputs"
+COMMENT ON TABLE "benchmark_outputs" IS 'Benchmark outputs hold the raw, compressed output of the pgbench invocation of a benchmark, so that it can be audited and re-parsed later on. It is a one-to-one relation with the benchmark.';
+-- set comment to column: "compression" on table: "benchmark_outputs"
+COMMENT ON COLUMN "benchmark_outputs"."compression" IS 'The algorithm that stdout and stderr are compressed with.';
+-- set comment to column: "stdout" on table: "benchmark_outputs"
+COMMENT ON COLUMN "benchmark_outputs"."stdout" IS 'The compressed stdout of the pgbench invocation.';
+-- set comment to column: "stderr" on table: "benchmark_outputs"
+COMMENT ON COLUMN "benchmark_outputs"."stderr" IS 'The compressed stderr of the pgbench invocation, including its progress reports.';
+-- set comment to column: "parser_version" on table: "benchmark_outputs"
+COMMENT ON COLUMN "benchmark_outputs"."parser_version" IS 'The version of the parser that produced the current results of the benchmark.';
+-- create "system_metric_samples" table
+CREATE TABLE "system_metric_samples" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "sampled_at" timestamptz NOT NULL, "elapsed" bigint NULL, "cpu_load" numeric(7,2) NOT NULL, "memory_load" numeric(7,2) NOT NULL, "iowait_load" numeric(7,2) NOT NULL, "swap_load" numeric(7,2) NOT NULL, "load_average" numeric(7,2) NOT NULL, "disk_read_bytes_per_second" double precision NOT NULL, "disk_write_bytes_per_second" double precision NOT NULL, "network_received_bytes_per_second" double precision NOT NULL, "network_sent_bytes_per_second" double precision NOT NULL, "transactions_per_second" double precision NULL, "average_latency" bigint NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "system_metric_samples_benchmarks_system_metric_samples" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "systemmetricsample_benchmark_id_sampled_at" to table: "system_metric_samples"
+CREATE INDEX "systemmetricsample_benchmark_id_sampled_at" ON "system_metric_samples" ("benchmark_id", "sampled_at");
+-- set comment to table: "system_metric_samples"
+COMMENT ON TABLE "system_metric_samples" IS 'System metric samples are the raw, timestamped samples that we polled from the system while the benchmark was running. The system metrics are aggregated from them.';
+-- set comment to column: "sampled_at" on table: "system_metric_samples"
+COMMENT ON COLUMN "system_metric_samples"."sampled_at" IS 'The time at which the sample was taken.';
+-- set comment to column: "elapsed" on table: "system_metric_samples"
+COMMENT ON COLUMN "system_metric_samples"."elapsed" IS 'The time that passed between the start of pgbench and the sample.';
+-- set comment to column: "transactions_per_second" on table: "system_metric_samples"
+COMMENT ON COLUMN "system_metric_samples"."transactions_per_second" IS 'The transactions per second that pgbench reported for the progress interval closest to the sample.';
+-- set comment to column: "average_latency" on table: "system_metric_samples"
+COMMENT ON COLUMN "system_metric_samples"."average_latency" IS 'The average latency that pgbench reported for the progress interval closest to the sample.';
+-- create "wait_events" table
+CREATE TABLE "wait_events" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "wait_event_type" character varying NOT NULL, "wait_event" character varying NOT NULL, "count" bigint NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "wait_events_benchmarks_wait_events" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "waitevent_benchmark_id_wait_event_type_wait_event" to table: "wait_events"
+CREATE UNIQUE INDEX "waitevent_benchmark_id_wait_event_type_wait_event" ON "wait_events" ("benchmark_id", "wait_event_type", "wait_event");
+-- set comment to table: "wait_events"
+COMMENT ON TABLE "wait_events" IS 'Wait events hold the distribution of wait_event_type/wait_event pairs of the pgbench backends that we sampled from pg_stat_activity while the benchmark was running. It is a one-to-many relation to the benchmark table.';
+-- set comment to column: "wait_event_type" on table: "wait_events"
+COMMENT ON COLUMN "wait_events"."wait_event_type" IS 'The wait_event_type as reported by pg_stat_activity. Backends that were not waiting are recorded as ''CPU''.';
+-- set comment to column: "wait_event" on table: "wait_events"
+COMMENT ON COLUMN "wait_events"."wait_event" IS 'The wait_event as reported by pg_stat_activity. Backends that were not waiting are recorded as ''CPU''.';
+-- set comment to column: "count" on table: "wait_events"
+COMMENT ON COLUMN "wait_events"."count" IS 'The number of times a pgbench backend was observed in this wait event across all samples.';
+-- create "process_metrics" table
+CREATE TABLE "process_metrics" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "role" character varying NOT NULL, "process_count" bigint NOT NULL, "cpu_average_load" numeric(7,2) NOT NULL, "cpu_max_load" numeric(7,2) NOT NULL, "memory_rss_average" bigint NOT NULL, "memory_rss_max" bigint NOT NULL, "io_read_bytes" bigint NULL, "io_write_bytes" bigint NULL, "voluntary_context_switches" bigint NOT NULL, "involuntary_context_switches" bigint NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "process_metrics_benchmarks_process_metrics" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "processmetric_benchmark_id_role" to table: "process_metrics"
+CREATE UNIQUE INDEX "processmetric_benchmark_id_role" ON "process_metrics" ("benchmark_id", "role");
+-- set comment to table: "process_metrics"
+COMMENT ON TABLE "process_metrics" IS 'Process metrics are the resource metrics of the PostgreSQL server and the pgbench process trees that we polled while the benchmark was running. Unlike the system metrics, they don''t include unrelated processes, which allows to tell whether the server or the load generator is saturated.';
+-- set comment to column: "role" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."role" IS 'The role of the monitored process tree. ''server'' is the PostgreSQL server, ''client'' is pgbench.';
+-- set comment to column: "process_count" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."process_count" IS 'The maximum number of processes that we observed in the process tree.';
+-- set comment to column: "memory_rss_average" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."memory_rss_average" IS 'The average resident set size in bytes of all processes in the tree. Shared memory is counted once per process.';
+-- set comment to column: "memory_rss_max" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."memory_rss_max" IS 'The maximum resident set size in bytes of all processes in the tree. Shared memory is counted once per process.';
+-- set comment to column: "io_read_bytes" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."io_read_bytes" IS 'The number of bytes the process tree read from storage during the benchmark run.';
+-- set comment to column: "io_write_bytes" on table: "process_metrics"
+COMMENT ON COLUMN "process_metrics"."io_write_bytes" IS 'The number of bytes the process tree wrote to storage during the benchmark run.';
+-- create "disk_metrics" table
+CREATE TABLE "disk_metrics" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "device" character varying NOT NULL, "read_bytes_per_second_average" double precision NOT NULL, "read_bytes_per_second_max" double precision NOT NULL, "write_bytes_per_second_average" double precision NOT NULL, "write_bytes_per_second_max" double precision NOT NULL, "read_iops_average" double precision NOT NULL, "read_iops_max" double precision NOT NULL, "write_iops_average" double precision NOT NULL, "write_iops_max" double precision NOT NULL, "utilization_average" numeric(7,2) NOT NULL, "utilization_max" numeric(7,2) NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "disk_metrics_benchmarks_disk_metrics" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "diskmetric_benchmark_id_device" to table: "disk_metrics"
+CREATE UNIQUE INDEX "diskmetric_benchmark_id_device" ON "disk_metrics" ("benchmark_id", "device");
+-- set comment to table: "disk_metrics"
+COMMENT ON TABLE "disk_metrics" IS 'Disk metrics are the IO metrics per block device that we polled while the benchmark was running. Devices without any IO during the benchmark are not recorded.';
+-- set comment to column: "device" on table: "disk_metrics"
+COMMENT ON COLUMN "disk_metrics"."device" IS 'The name of the block device, e.g. sda or nvme0n1.';
+-- create "network_metrics" table
+CREATE TABLE "network_metrics" ("id" character varying NOT NULL, "benchmark_id" character varying NOT NULL, "interface_name" character varying NOT NULL, "bytes_received_per_second_average" double precision NOT NULL, "bytes_received_per_second_max" double precision NOT NULL, "bytes_sent_per_second_average" double precision NOT NULL, "bytes_sent_per_second_max" double precision NOT NULL, "packets_received_per_second_average" double precision NOT NULL, "packets_received_per_second_max" double precision NOT NULL, "packets_sent_per_second_average" double precision NOT NULL, "packets_sent_per_second_max" double precision NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"), CONSTRAINT "network_metrics_benchmarks_network_metrics" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
+-- create index "networkmetric_benchmark_id_interface_name" to table: "network_metrics"
+CREATE UNIQUE INDEX "networkmetric_benchmark_id_interface_name" ON "network_metrics" ("benchmark_id", "interface_name");
+-- set comment to table: "network_metrics"
+COMMENT ON TABLE "network_metrics" IS 'Network metrics are the IO metrics per network interface that we polled while the benchmark was running. Interfaces without any traffic during the benchmark are not recorded.';
+-- set comment to column: "interface_name" on table: "network_metrics"
+COMMENT ON COLUMN "network_metrics"."interface_name" IS 'The name of the network interface, e.g. eth0 or lo.';
+-- create "tags" table
+CREATE TABLE "tags" ("id" character varying NOT NULL, "key" character varying NOT NULL, "value" character varying NOT NULL, "created_at" timestamptz NOT NULL, "updated_at" timestamptz NOT NULL, PRIMARY KEY ("id"));
+-- create index "tag_key_value" to table: "tags"
+CREATE UNIQUE INDEX "tag_key_value" ON "tags" ("key", "value");
+-- set comment to table: "tags"
+COMMENT ON TABLE "tags" IS 'Tags are key-value labels like env=staging or pg=16.1. It is a many-to-many relation to the benchmark table.';
+-- set comment to column: "key" on table: "tags"
+COMMENT ON COLUMN "tags"."key" IS 'The key of the tag, e.g. env.';
+-- set comment to column: "value" on table: "tags"
+COMMENT ON COLUMN "tags"."value" IS 'The value of the tag, e.g. staging.';
+-- create "benchmark_tags" table
+CREATE TABLE "benchmark_tags" ("benchmark_id" character varying NOT NULL, "tag_id" character varying NOT NULL, PRIMARY KEY ("benchmark_id", "tag_id"), CONSTRAINT "benchmark_tags_benchmark_id" FOREIGN KEY ("benchmark_id") REFERENCES "benchmarks" ("id") ON UPDATE NO ACTION ON DELETE CASCADE, CONSTRAINT "benchmark_tags_tag_id" FOREIGN KEY ("tag_id") REFERENCES "tags" ("id") ON UPDATE NO ACTION ON DELETE CASCADE);
@@ -0,0 +1,10 @@
+-- reverse: create "system_metrics" table
+DROP TABLE `system_metrics`;
+-- reverse: create "system_configs" table
+DROP TABLE `system_configs`;
+-- reverse: create "benchmark_results" table
+DROP TABLE `benchmark_results`;
+-- reverse: create "benchmarks" table
+DROP TABLE `benchmarks`;
+-- reverse: create "app_configs" table
+DROP TABLE `app_configs`;
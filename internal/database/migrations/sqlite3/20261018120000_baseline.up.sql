@@ -0,0 +1,28 @@
+-- create "app_configs" table
+CREATE TABLE `app_configs` (`id` integer NOT NULL PRIMARY KEY AUTOINCREMENT, `version` text NOT NULL DEFAULT 'dev', `initial_version` text NULL DEFAULT 'dev', `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL);
+-- create index "appconfig_version" to table: "app_configs"
+CREATE INDEX `appconfig_version` ON `app_configs` (`version`);
+-- create "benchmarks" table
+CREATE TABLE `benchmarks` (`id` text NOT NULL, `group_id` text NOT NULL, `comment` text NULL, `version` text NULL, `command` text NULL, `transaction_type` text NULL, `scaling_factor` real NULL, `query_mode` text NULL, `clients` integer NULL, `threads` integer NULL, `recorded_at` datetime NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`));
+-- create index "benchmark_group_id_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id_id` ON `benchmarks` (`group_id`, `id`);
+-- create index "benchmark_group_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id` ON `benchmarks` (`group_id`);
+-- create index "benchmark_clients" to table: "benchmarks"
+CREATE INDEX `benchmark_clients` ON `benchmarks` (`clients`);
+-- create index "benchmark_recorded_at" to table: "benchmarks"
+CREATE INDEX `benchmark_recorded_at` ON `benchmarks` (`recorded_at`);
+-- create "benchmark_results" table
+CREATE TABLE `benchmark_results` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `transactions` integer NULL, `failed_transactions` integer NULL, `transactions_per_second` real NULL, `average_latency` bigint NULL, `connection_time` bigint NULL, `total_runtime` bigint NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `benchmark_results_benchmarks_result` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "benchmark_results_benchmark_id_key" to table: "benchmark_results"
+CREATE UNIQUE INDEX `benchmark_results_benchmark_id_key` ON `benchmark_results` (`benchmark_id`);
+-- create "system_configs" table
+CREATE TABLE `system_configs` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `machine_id` text NULL, `os_name` text NULL, `os_arch` text NULL, `cpu_vendor` text NULL, `cpu_model` text NULL, `cpu_count` integer NULL, `cpu_cores` integer NULL, `cpu_threads` integer NULL, `ram_physical` integer NULL, `ram_usable` integer NULL, `disk_count` integer NULL, `disk_space_total` integer NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `system_configs_benchmarks_system` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "system_configs_benchmark_id_key" to table: "system_configs"
+CREATE UNIQUE INDEX `system_configs_benchmark_id_key` ON `system_configs` (`benchmark_id`);
+-- create index "systemconfig_machine_id" to table: "system_configs"
+CREATE INDEX `systemconfig_machine_id` ON `system_configs` (`machine_id`);
+-- create "system_metrics" table
+CREATE TABLE `system_metrics` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `cpu_min_load` real NOT NULL, `cpu_max_load` real NOT NULL, `cpu_average_load` real NOT NULL, `cpu_50th_load` real NOT NULL, `cpu_75th_load` real NOT NULL, `cpu_90th_load` real NOT NULL, `cpu_95th_load` real NOT NULL, `cpu_99th_load` real NOT NULL, `memory_min_load` real NOT NULL, `memory_max_load` real NOT NULL, `memory_average_load` real NOT NULL, `memory_50th_load` real NOT NULL, `memory_75th_load` real NOT NULL, `memory_90th_load` real NOT NULL, `memory_95th_load` real NOT NULL, `memory_99th_load` real NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `system_metrics_benchmarks_system_metric` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "system_metrics_benchmark_id_key" to table: "system_metrics"
+CREATE UNIQUE INDEX `system_metrics_benchmark_id_key` ON `system_metrics` (`benchmark_id`);
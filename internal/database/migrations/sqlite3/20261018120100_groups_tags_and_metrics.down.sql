@@ -0,0 +1,104 @@
+-- reverse: create "benchmark_tags" table
+DROP TABLE `benchmark_tags`;
+-- reverse: create "tags" table
+DROP TABLE `tags`;
+-- reverse: create "network_metrics" table
+DROP TABLE `network_metrics`;
+-- reverse: create "disk_metrics" table
+DROP TABLE `disk_metrics`;
+-- reverse: create "process_metrics" table
+DROP TABLE `process_metrics`;
+-- reverse: create "wait_events" table
+DROP TABLE `wait_events`;
+-- reverse: create "system_metric_samples" table
+DROP TABLE `system_metric_samples`;
+-- reverse: create "benchmark_outputs" table
+DROP TABLE `benchmark_outputs`;
+-- reverse: add column "swap_99th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_99th_load`;
+-- reverse: add column "swap_95th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_95th_load`;
+-- reverse: add column "swap_90th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_90th_load`;
+-- reverse: add column "swap_75th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_75th_load`;
+-- reverse: add column "swap_50th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_50th_load`;
+-- reverse: add column "swap_average_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_average_load`;
+-- reverse: add column "swap_max_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_max_load`;
+-- reverse: add column "swap_min_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `swap_min_load`;
+-- reverse: add column "iowait_99th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_99th_load`;
+-- reverse: add column "iowait_95th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_95th_load`;
+-- reverse: add column "iowait_90th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_90th_load`;
+-- reverse: add column "iowait_75th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_75th_load`;
+-- reverse: add column "iowait_50th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_50th_load`;
+-- reverse: add column "iowait_average_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_average_load`;
+-- reverse: add column "iowait_max_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_max_load`;
+-- reverse: add column "iowait_min_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `iowait_min_load`;
+-- reverse: add column "load_average_99th" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_99th`;
+-- reverse: add column "load_average_95th" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_95th`;
+-- reverse: add column "load_average_90th" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_90th`;
+-- reverse: add column "load_average_75th" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_75th`;
+-- reverse: add column "load_average_50th" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_50th`;
+-- reverse: add column "load_average_average" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_average`;
+-- reverse: add column "load_average_max" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_max`;
+-- reverse: add column "load_average_min" to table: "system_metrics"
+ALTER TABLE `system_metrics` DROP COLUMN `load_average_min`;
+-- reverse: add column "cgroup_io_limits" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `cgroup_io_limits`;
+-- reverse: add column "cgroup_memory_limit" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `cgroup_memory_limit`;
+-- reverse: add column "cgroup_cpu_quota" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `cgroup_cpu_quota`;
+-- reverse: add column "cgroup_version" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `cgroup_version`;
+-- reverse: add column "disk_type" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `disk_type`;
+-- reverse: add column "data_dir_mount_options" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `data_dir_mount_options`;
+-- reverse: add column "data_dir_filesystem" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `data_dir_filesystem`;
+-- reverse: add column "transparent_huge_pages" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `transparent_huge_pages`;
+-- reverse: add column "numa_nodes" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `numa_nodes`;
+-- reverse: add column "cpu_governor" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `cpu_governor`;
+-- reverse: add column "kernel_version" to table: "system_configs"
+ALTER TABLE `system_configs` DROP COLUMN `kernel_version`;
+-- create "new_benchmarks" table
+CREATE TABLE `new_benchmarks` (`id` text NOT NULL, `group_id` text NOT NULL, `comment` text NULL, `version` text NULL, `command` text NULL, `transaction_type` text NULL, `scaling_factor` real NULL, `query_mode` text NULL, `clients` integer NULL, `threads` integer NULL, `recorded_at` datetime NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`));
+-- copy rows from old table "benchmarks" to new temporary table "new_benchmarks"
+INSERT INTO `new_benchmarks` (`id`, `group_id`, `comment`, `version`, `command`, `transaction_type`, `scaling_factor`, `query_mode`, `clients`, `threads`, `recorded_at`, `created_at`, `updated_at`) SELECT `id`, `group_id`, `comment`, `version`, `command`, `transaction_type`, `scaling_factor`, `query_mode`, `clients`, `threads`, `recorded_at`, `created_at`, `updated_at` FROM `benchmarks`;
+-- drop "benchmarks" table after copying rows
+DROP TABLE `benchmarks`;
+-- rename temporary table "new_benchmarks" to "benchmarks"
+ALTER TABLE `new_benchmarks` RENAME TO `benchmarks`;
+-- create index "benchmark_group_id_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id_id` ON `benchmarks` (`group_id`, `id`);
+-- create index "benchmark_group_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id` ON `benchmarks` (`group_id`);
+-- create index "benchmark_clients" to table: "benchmarks"
+CREATE INDEX `benchmark_clients` ON `benchmarks` (`clients`);
+-- create index "benchmark_recorded_at" to table: "benchmarks"
+CREATE INDEX `benchmark_recorded_at` ON `benchmarks` (`recorded_at`);
+-- reverse: create "benchmark_groups" table
+DROP TABLE `benchmark_groups`;
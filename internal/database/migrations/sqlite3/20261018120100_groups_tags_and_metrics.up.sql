@@ -0,0 +1,124 @@
+-- create "benchmark_groups" table
+CREATE TABLE `benchmark_groups` (`id` text NOT NULL, `name` text NULL, `description` text NULL, `config` json NULL, `status` text NOT NULL DEFAULT 'running', `started_at` datetime NOT NULL, `finished_at` datetime NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`));
+-- create index "benchmark_groups_name_key" to table: "benchmark_groups"
+CREATE UNIQUE INDEX `benchmark_groups_name_key` ON `benchmark_groups` (`name`);
+-- create index "benchmarkgroup_started_at" to table: "benchmark_groups"
+CREATE INDEX `benchmarkgroup_started_at` ON `benchmark_groups` (`started_at`);
+-- create the groups of the existing benchmarks
+INSERT INTO `benchmark_groups` (`id`, `status`, `started_at`, `finished_at`, `created_at`, `updated_at`) SELECT `group_id`, 'completed', MIN(`recorded_at`), MAX(`recorded_at`), MIN(`created_at`), MAX(`updated_at`) FROM `benchmarks` GROUP BY `group_id`;
+-- create "new_benchmarks" table
+CREATE TABLE `new_benchmarks` (`id` text NOT NULL, `group_id` text NOT NULL, `comment` text NULL, `version` text NULL, `command` text NULL, `transaction_type` text NULL, `scaling_factor` real NULL, `query_mode` text NULL, `clients` integer NULL, `threads` integer NULL, `recorded_at` datetime NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `benchmarks_benchmark_groups_benchmarks` FOREIGN KEY (`group_id`) REFERENCES `benchmark_groups` (`id`) ON DELETE CASCADE);
+-- copy rows from old table "benchmarks" to new temporary table "new_benchmarks"
+INSERT INTO `new_benchmarks` (`id`, `group_id`, `comment`, `version`, `command`, `transaction_type`, `scaling_factor`, `query_mode`, `clients`, `threads`, `recorded_at`, `created_at`, `updated_at`) SELECT `id`, `group_id`, `comment`, `version`, `command`, `transaction_type`, `scaling_factor`, `query_mode`, `clients`, `threads`, `recorded_at`, `created_at`, `updated_at` FROM `benchmarks`;
+-- drop "benchmarks" table after copying rows
+DROP TABLE `benchmarks`;
+-- rename temporary table "new_benchmarks" to "benchmarks"
+ALTER TABLE `new_benchmarks` RENAME TO `benchmarks`;
+-- create index "benchmark_group_id_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id_id` ON `benchmarks` (`group_id`, `id`);
+-- create index "benchmark_group_id" to table: "benchmarks"
+CREATE INDEX `benchmark_group_id` ON `benchmarks` (`group_id`);
+-- create index "benchmark_clients" to table: "benchmarks"
+CREATE INDEX `benchmark_clients` ON `benchmarks` (`clients`);
+-- create index "benchmark_recorded_at" to table: "benchmarks"
+CREATE INDEX `benchmark_recorded_at` ON `benchmarks` (`recorded_at`);
+-- add column "kernel_version" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `kernel_version` text NULL;
+-- add column "cpu_governor" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `cpu_governor` text NULL;
+-- add column "numa_nodes" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `numa_nodes` integer NULL;
+-- add column "transparent_huge_pages" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `transparent_huge_pages` text NULL;
+-- add column "data_dir_filesystem" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `data_dir_filesystem` text NULL;
+-- add column "data_dir_mount_options" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `data_dir_mount_options` text NULL;
+-- add column "disk_type" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `disk_type` text NULL;
+-- add column "cgroup_version" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `cgroup_version` integer NULL;
+-- add column "cgroup_cpu_quota" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `cgroup_cpu_quota` real NULL;
+-- add column "cgroup_memory_limit" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `cgroup_memory_limit` integer NULL;
+-- add column "cgroup_io_limits" to table: "system_configs"
+ALTER TABLE `system_configs` ADD COLUMN `cgroup_io_limits` text NULL;
+-- add column "load_average_min" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_min` real NULL;
+-- add column "load_average_max" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_max` real NULL;
+-- add column "load_average_average" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_average` real NULL;
+-- add column "load_average_50th" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_50th` real NULL;
+-- add column "load_average_75th" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_75th` real NULL;
+-- add column "load_average_90th" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_90th` real NULL;
+-- add column "load_average_95th" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_95th` real NULL;
+-- add column "load_average_99th" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `load_average_99th` real NULL;
+-- add column "iowait_min_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_min_load` real NULL;
+-- add column "iowait_max_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_max_load` real NULL;
+-- add column "iowait_average_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_average_load` real NULL;
+-- add column "iowait_50th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_50th_load` real NULL;
+-- add column "iowait_75th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_75th_load` real NULL;
+-- add column "iowait_90th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_90th_load` real NULL;
+-- add column "iowait_95th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_95th_load` real NULL;
+-- add column "iowait_99th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `iowait_99th_load` real NULL;
+-- add column "swap_min_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_min_load` real NULL;
+-- add column "swap_max_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_max_load` real NULL;
+-- add column "swap_average_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_average_load` real NULL;
+-- add column "swap_50th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_50th_load` real NULL;
+-- add column "swap_75th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_75th_load` real NULL;
+-- add column "swap_90th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_90th_load` real NULL;
+-- add column "swap_95th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_95th_load` real NULL;
+-- add column "swap_99th_load" to table: "system_metrics"
+ALTER TABLE `system_metrics` ADD COLUMN `swap_99th_load` real NULL;
+-- create "benchmark_outputs" table
+CREATE TABLE `benchmark_outputs` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `compression` text NOT NULL DEFAULT 'zstd', `stdout` blob NOT NULL, `stderr` blob NOT NULL, `parser_version` integer NOT NULL DEFAULT 0, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `benchmark_outputs_benchmarks_output` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "benchmark_outputs_benchmark_id_key" to table: "benchmark_outputs"
+CREATE UNIQUE INDEX `benchmark_outputs_benchmark_id_key` ON `benchmark_outputs` (`benchmark_id`);
+-- create "system_metric_samples" table
+CREATE TABLE `system_metric_samples` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `sampled_at` datetime NOT NULL, `elapsed` bigint NULL, `cpu_load` real NOT NULL, `memory_load` real NOT NULL, `iowait_load` real NOT NULL, `swap_load` real NOT NULL, `load_average` real NOT NULL, `disk_read_bytes_per_second` real NOT NULL, `disk_write_bytes_per_second` real NOT NULL, `network_received_bytes_per_second` real NOT NULL, `network_sent_bytes_per_second` real NOT NULL, `transactions_per_second` real NULL, `average_latency` bigint NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `system_metric_samples_benchmarks_system_metric_samples` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "systemmetricsample_benchmark_id_sampled_at" to table: "system_metric_samples"
+CREATE INDEX `systemmetricsample_benchmark_id_sampled_at` ON `system_metric_samples` (`benchmark_id`, `sampled_at`);
+-- create "wait_events" table
+CREATE TABLE `wait_events` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `wait_event_type` text NOT NULL, `wait_event` text NOT NULL, `count` integer NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `wait_events_benchmarks_wait_events` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "waitevent_benchmark_id_wait_event_type_wait_event" to table: "wait_events"
+CREATE UNIQUE INDEX `waitevent_benchmark_id_wait_event_type_wait_event` ON `wait_events` (`benchmark_id`, `wait_event_type`, `wait_event`);
+-- create "process_metrics" table
+CREATE TABLE `process_metrics` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `role` text NOT NULL, `process_count` integer NOT NULL, `cpu_average_load` real NOT NULL, `cpu_max_load` real NOT NULL, `memory_rss_average` integer NOT NULL, `memory_rss_max` integer NOT NULL, `io_read_bytes` integer NULL, `io_write_bytes` integer NULL, `voluntary_context_switches` integer NOT NULL, `involuntary_context_switches` integer NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `process_metrics_benchmarks_process_metrics` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "processmetric_benchmark_id_role" to table: "process_metrics"
+CREATE UNIQUE INDEX `processmetric_benchmark_id_role` ON `process_metrics` (`benchmark_id`, `role`);
+-- create "disk_metrics" table
+CREATE TABLE `disk_metrics` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `device` text NOT NULL, `read_bytes_per_second_average` real NOT NULL, `read_bytes_per_second_max` real NOT NULL, `write_bytes_per_second_average` real NOT NULL, `write_bytes_per_second_max` real NOT NULL, `read_iops_average` real NOT NULL, `read_iops_max` real NOT NULL, `write_iops_average` real NOT NULL, `write_iops_max` real NOT NULL, `utilization_average` real NOT NULL, `utilization_max` real NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `disk_metrics_benchmarks_disk_metrics` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "diskmetric_benchmark_id_device" to table: "disk_metrics"
+CREATE UNIQUE INDEX `diskmetric_benchmark_id_device` ON `disk_metrics` (`benchmark_id`, `device`);
+-- create "network_metrics" table
+CREATE TABLE `network_metrics` (`id` text NOT NULL, `benchmark_id` text NOT NULL, `interface_name` text NOT NULL, `bytes_received_per_second_average` real NOT NULL, `bytes_received_per_second_max` real NOT NULL, `bytes_sent_per_second_average` real NOT NULL, `bytes_sent_per_second_max` real NOT NULL, `packets_received_per_second_average` real NOT NULL, `packets_received_per_second_max` real NOT NULL, `packets_sent_per_second_average` real NOT NULL, `packets_sent_per_second_max` real NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`), CONSTRAINT `network_metrics_benchmarks_network_metrics` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE);
+-- create index "networkmetric_benchmark_id_interface_name" to table: "network_metrics"
+CREATE UNIQUE INDEX `networkmetric_benchmark_id_interface_name` ON `network_metrics` (`benchmark_id`, `interface_name`);
+-- create "tags" table
+CREATE TABLE `tags` (`id` text NOT NULL, `key` text NOT NULL, `value` text NOT NULL, `created_at` datetime NOT NULL, `updated_at` datetime NOT NULL, PRIMARY KEY (`id`));
+-- create index "tag_key_value" to table: "tags"
+CREATE UNIQUE INDEX `tag_key_value` ON `tags` (`key`, `value`);
+-- create "benchmark_tags" table
+CREATE TABLE `benchmark_tags` (`benchmark_id` text NOT NULL, `tag_id` text NOT NULL, PRIMARY KEY (`benchmark_id`, `tag_id`), CONSTRAINT `benchmark_tags_benchmark_id` FOREIGN KEY (`benchmark_id`) REFERENCES `benchmarks` (`id`) ON DELETE CASCADE, CONSTRAINT `benchmark_tags_tag_id` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON DELETE CASCADE);
@@ -1,8 +1,13 @@
 package database
 
 import (
+	"time"
+
 	"github.com/nikoksr/dbench/ent"
 	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/ent/tag"
+	"github.com/nikoksr/dbench/internal/models"
 )
 
 // FilterFunc is a type that defines a function that modifies a BenchmarkQuery.
@@ -17,6 +22,10 @@ type QueryOptions struct {
 	Filters []FilterFunc
 	Limit   int
 	Offset  int
+
+	// The raw pgbench output and the sample series are by far the largest edges, so they're only loaded on request
+	LoadOutput  bool
+	LoadSamples bool
 }
 
 // WithOrderBy is a function that returns a QueryOption that sets the OrderBy field of a QueryOptions.
@@ -47,13 +56,92 @@ func WithOffset(offset int) QueryOption {
 	}
 }
 
+// WithSampleSeries is a function that returns a QueryOption that loads the system metric samples of the benchmarks
+// along with them, e.g. to plot them over time.
+func WithSampleSeries() QueryOption {
+	return func(opts *QueryOptions) {
+		opts.LoadSamples = true
+	}
+}
+
+// WithEdgesForCopy is a function that returns a QueryOption that loads all edges of the benchmarks, including the raw
+// pgbench output and the system metric samples. Copies of benchmarks, e.g. JSON or SQLite exports, need all of them to
+// be lossless.
+func WithEdgesForCopy() QueryOption {
+	return func(opts *QueryOptions) {
+		opts.LoadOutput = true
+		opts.LoadSamples = true
+	}
+}
+
+// WithTags is a function that returns a QueryOption that only matches benchmarks that are labeled with all given tags.
+// A tag without a value matches any value of its key.
+func WithTags(tags ...*models.Tag) QueryOption {
+	return WithFilter(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+		for _, t := range tags {
+			if t.Value == "" {
+				query = query.Where(benchmark.HasTagsWith(tag.Key(t.Key)))
+			} else {
+				query = query.Where(benchmark.HasTagsWith(tag.Key(t.Key), tag.Value(t.Value)))
+			}
+		}
+
+		return query
+	})
+}
+
+// WithSelection is a function that returns a QueryOption that only matches benchmarks with one of the given IDs or
+// of one of the given groups.
+func WithSelection(ids, groupIDs []string) QueryOption {
+	return WithFilter(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+		return query.Where(benchmark.Or(
+			benchmark.IDIn(toPULIDs(ids)...),
+			benchmark.GroupIDIn(toPULIDs(groupIDs)...),
+		))
+	})
+}
+
+// WithRecordedBetween is a function that returns a QueryOption that only matches benchmarks that were recorded within
+// the given times, both inclusive. A zero time leaves the range open on its side.
+func WithRecordedBetween(since, until time.Time) QueryOption {
+	return WithFilter(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+		if !since.IsZero() {
+			query = query.Where(benchmark.RecordedAtGTE(since.UTC()))
+		}
+		if !until.IsZero() {
+			query = query.Where(benchmark.RecordedAtLTE(until.UTC()))
+		}
+
+		return query
+	})
+}
+
+func toPULIDs(ids []string) []pulid.ID {
+	pulids := make([]pulid.ID, 0, len(ids))
+	for _, id := range ids {
+		pulids = append(pulids, pulid.ID(id))
+	}
+
+	return pulids
+}
+
 // applyQueryOptions is a function that applies a list of QueryOptions to a BenchmarkQuery.
 func applyQueryOptions(query *ent.BenchmarkQuery, opts ...QueryOption) *ent.BenchmarkQuery {
+	return newQueryOptions(opts...).apply(query)
+}
+
+// newQueryOptions collects the given options.
+func newQueryOptions(opts ...QueryOption) *QueryOptions {
 	qo := &QueryOptions{} // Initialize with default options
 	for _, opt := range opts {
 		opt(qo) // Apply each option to the options
 	}
 
+	return qo
+}
+
+// apply applies the order, limit, offset and filters of the options to the given query.
+func (qo *QueryOptions) apply(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
 	// Apply OrderBy, Limit, and Offset if they are set
 	if qo.OrderBy != nil {
 		query = qo.OrderBy(query)
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmarkoutput"
+	"github.com/nikoksr/dbench/ent/benchmarkresult"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+func benchmarkOutputCreate(tx *ent.Tx, bmarkID pulid.ID, output *models.BenchmarkOutput) *ent.BenchmarkOutputCreate {
+	return tx.BenchmarkOutput.Create().
+		SetBenchmarkID(bmarkID).
+		SetCompression(output.Compression).
+		SetStdout(output.Stdout).
+		SetStderr(output.Stderr).
+		SetParserVersion(output.ParserVersion)
+}
+
+// FetchOutputs fetches the stored pgbench outputs of the given benchmarks. Benchmarks without a stored output are
+// skipped.
+func (db *DB) FetchOutputs(ctx context.Context, ids []string) ([]*models.BenchmarkOutput, error) {
+	pulids, err := convertToPULID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return db.client.BenchmarkOutput.Query().
+		Where(benchmarkoutput.BenchmarkIDIn(pulids...)).
+		Order(ent.Asc(benchmarkoutput.FieldBenchmarkID)).
+		All(ctx)
+}
+
+// UpdateParsed replaces the parsed fields and the result of the given benchmark with the ones of a re-parsed
+// benchmark and records the version of the parser that produced them.
+func (db *DB) UpdateParsed(ctx context.Context, id string, parsed *models.Benchmark, parserVersion int) error {
+	if parsed == nil || parsed.Edges.Result == nil {
+		return fmt.Errorf("parsed benchmark or its result is nil")
+	}
+
+	bmarkID := pulid.ID(id)
+	result := parsed.Edges.Result
+
+	tx, err := db.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("start transaction: %w", err)
+	}
+
+	err = tx.Benchmark.UpdateOneID(bmarkID).
+		SetVersion(parsed.Version).
+		SetTransactionType(parsed.TransactionType).
+		SetScalingFactor(parsed.ScalingFactor).
+		SetQueryMode(parsed.QueryMode).
+		SetClients(parsed.Clients).
+		SetThreads(parsed.Threads).
+		Exec(ctx)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("update benchmark: %w", err))
+	}
+
+	_, err = tx.BenchmarkResult.Update().
+		Where(benchmarkresult.BenchmarkID(bmarkID)).
+		SetTransactions(result.Transactions).
+		SetFailedTransactions(result.FailedTransactions).
+		SetTransactionsPerSecond(result.TransactionsPerSecond).
+		SetAverageLatency(result.AverageLatency).
+		SetConnectionTime(result.ConnectionTime).
+		Save(ctx)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("update benchmark result: %w", err))
+	}
+
+	_, err = tx.BenchmarkOutput.Update().
+		Where(benchmarkoutput.BenchmarkID(bmarkID)).
+		SetParserVersion(parserVersion).
+		Save(ctx)
+	if err != nil {
+		return rollback(tx, fmt.Errorf("update benchmark output: %w", err))
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
 )
 
 // RemoveByIDs removes benchmarks by their IDs.
@@ -14,11 +15,27 @@ func (db *DB) RemoveByIDs(ctx context.Context, ids []string) error {
 		return err
 	}
 
+	// Remember the groups of the benchmarks, they may be left empty
+	groupIDs, err := db.client.Benchmark.Query().
+		Where(benchmark.IDIn(pulids...)).
+		QueryGroup().
+		IDs(ctx)
+	if err != nil {
+		return err
+	}
+
 	// Delete benchmarks
 	_, err = db.client.Benchmark.Delete().
 		Where(benchmark.IDIn(pulids...)).Exec(ctx)
+	if err != nil {
+		return err
+	}
 
-	return err
+	if err := removeEmptyGroups(ctx, db.client, groupIDs); err != nil {
+		return err
+	}
+
+	return removeOrphanedTags(ctx, db.client)
 }
 
 // RemoveByGroupIDs removes benchmarks by their group IDs.
@@ -32,6 +49,16 @@ func (db *DB) RemoveByGroupIDs(ctx context.Context, ids []string) error {
 	// Delete benchmarks
 	_, err = db.client.Benchmark.Delete().
 		Where(benchmark.GroupIDIn(pulids...)).Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Delete the groups themselves
+	_, err = db.client.BenchmarkGroup.Delete().
+		Where(benchmarkgroup.IDIn(pulids...)).Exec(ctx)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return removeOrphanedTags(ctx, db.client)
 }
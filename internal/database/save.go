@@ -5,19 +5,22 @@ import (
 	"fmt"
 
 	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/benchmarkoutput"
+	"github.com/nikoksr/dbench/ent/benchmarkresult"
+	"github.com/nikoksr/dbench/ent/diskmetric"
+	"github.com/nikoksr/dbench/ent/networkmetric"
+	"github.com/nikoksr/dbench/ent/processmetric"
 	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/ent/systemconfig"
+	"github.com/nikoksr/dbench/ent/systemmetric"
+	"github.com/nikoksr/dbench/ent/systemmetricsample"
+	"github.com/nikoksr/dbench/ent/waitevent"
 	"github.com/nikoksr/dbench/internal/models"
 )
 
-func (db *DB) saveBenchmark(ctx context.Context, tx *ent.Tx, bmark *models.Benchmark) (*models.Benchmark, error) {
-	if tx == nil {
-		return nil, fmt.Errorf("transaction is nil")
-	}
-	if bmark == nil {
-		return nil, fmt.Errorf("benchmark is nil")
-	}
-
-	bmark, err := tx.Benchmark.Create().
+func benchmarkCreate(tx *ent.Tx, bmark *models.Benchmark, tagIDs []pulid.ID) *ent.BenchmarkCreate {
+	create := tx.Benchmark.Create().
 		SetGroupID(bmark.GroupID).
 		SetNillableComment(bmark.Comment).
 		SetVersion(bmark.Version).
@@ -28,41 +31,29 @@ func (db *DB) saveBenchmark(ctx context.Context, tx *ent.Tx, bmark *models.Bench
 		SetClients(bmark.Clients).
 		SetThreads(bmark.Threads).
 		SetRecordedAt(bmark.RecordedAt).
-		Save(ctx)
+		AddTagIDs(tagIDs...)
 
-	return bmark, err
-}
-
-func (db *DB) saveBenchmarkResult(ctx context.Context, tx *ent.Tx, bmarkID pulid.ID, result *models.BenchmarkResult) (*models.BenchmarkResult, error) {
-	if tx == nil {
-		return nil, fmt.Errorf("transaction is nil")
-	}
-	if result == nil {
-		return nil, fmt.Errorf("benchmark result is nil")
+	// Imported benchmarks keep their original ID
+	if bmark.ID != "" {
+		create.SetID(bmark.ID)
 	}
 
-	result, err := tx.BenchmarkResult.Create().
+	return create
+}
+
+func benchmarkResultCreate(tx *ent.Tx, bmarkID pulid.ID, result *models.BenchmarkResult) *ent.BenchmarkResultCreate {
+	return tx.BenchmarkResult.Create().
 		SetBenchmarkID(bmarkID).
 		SetTransactions(result.Transactions).
 		SetFailedTransactions(result.FailedTransactions).
 		SetTransactionsPerSecond(result.TransactionsPerSecond).
 		SetAverageLatency(result.AverageLatency).
 		SetConnectionTime(result.ConnectionTime).
-		SetTotalRuntime(result.TotalRuntime).
-		Save(ctx)
-
-	return result, err
+		SetTotalRuntime(result.TotalRuntime)
 }
 
-func (db *DB) saveSystemMetric(ctx context.Context, tx *ent.Tx, bmarkID pulid.ID, metric *models.SystemMetric) (*models.SystemMetric, error) {
-	if tx == nil {
-		return nil, fmt.Errorf("transaction is nil")
-	}
-	if metric == nil {
-		return nil, fmt.Errorf("system metric is nil")
-	}
-
-	metric, err := tx.SystemMetric.Create().
+func systemMetricCreate(tx *ent.Tx, bmarkID pulid.ID, metric *models.SystemMetric) *ent.SystemMetricCreate {
+	return tx.SystemMetric.Create().
 		// Benchmark
 		SetBenchmarkID(bmarkID).
 		// CPU
@@ -83,20 +74,37 @@ func (db *DB) saveSystemMetric(ctx context.Context, tx *ent.Tx, bmarkID pulid.ID
 		SetMemory90thLoad(metric.Memory90thLoad).
 		SetMemory95thLoad(metric.Memory95thLoad).
 		SetMemory99thLoad(metric.Memory99thLoad).
-		Save(ctx)
-
-	return metric, err
+		// Load average
+		SetLoadAverageMin(metric.LoadAverageMin).
+		SetLoadAverageMax(metric.LoadAverageMax).
+		SetLoadAverageAverage(metric.LoadAverageAverage).
+		SetLoadAverage50th(metric.LoadAverage50th).
+		SetLoadAverage75th(metric.LoadAverage75th).
+		SetLoadAverage90th(metric.LoadAverage90th).
+		SetLoadAverage95th(metric.LoadAverage95th).
+		SetLoadAverage99th(metric.LoadAverage99th).
+		// IO wait
+		SetIowaitMinLoad(metric.IowaitMinLoad).
+		SetIowaitMaxLoad(metric.IowaitMaxLoad).
+		SetIowaitAverageLoad(metric.IowaitAverageLoad).
+		SetIowait50thLoad(metric.Iowait50thLoad).
+		SetIowait75thLoad(metric.Iowait75thLoad).
+		SetIowait90thLoad(metric.Iowait90thLoad).
+		SetIowait95thLoad(metric.Iowait95thLoad).
+		SetIowait99thLoad(metric.Iowait99thLoad).
+		// Swap
+		SetSwapMinLoad(metric.SwapMinLoad).
+		SetSwapMaxLoad(metric.SwapMaxLoad).
+		SetSwapAverageLoad(metric.SwapAverageLoad).
+		SetSwap50thLoad(metric.Swap50thLoad).
+		SetSwap75thLoad(metric.Swap75thLoad).
+		SetSwap90thLoad(metric.Swap90thLoad).
+		SetSwap95thLoad(metric.Swap95thLoad).
+		SetSwap99thLoad(metric.Swap99thLoad)
 }
 
-func (db *DB) saveSystemConfig(ctx context.Context, tx *ent.Tx, bmarkID pulid.ID, systemConfig *models.SystemConfig) (*models.SystemConfig, error) {
-	if tx == nil {
-		return nil, fmt.Errorf("transaction is nil")
-	}
-	if systemConfig == nil {
-		return nil, fmt.Errorf("system config are nil")
-	}
-
-	systemConfig, err := tx.SystemConfig.Create().
+func systemConfigCreate(tx *ent.Tx, bmarkID pulid.ID, systemConfig *models.SystemConfig) *ent.SystemConfigCreate {
+	return tx.SystemConfig.Create().
 		SetBenchmarkID(bmarkID).
 		SetNillableMachineID(systemConfig.MachineID).
 		SetNillableOsName(systemConfig.OsName).
@@ -110,47 +118,360 @@ func (db *DB) saveSystemConfig(ctx context.Context, tx *ent.Tx, bmarkID pulid.ID
 		SetNillableRAMUsable(systemConfig.RAMUsable).
 		SetNillableDiskCount(systemConfig.DiskCount).
 		SetNillableDiskSpaceTotal(systemConfig.DiskSpaceTotal).
-		Save(ctx)
+		SetNillableKernelVersion(systemConfig.KernelVersion).
+		SetNillableCPUGovernor(systemConfig.CPUGovernor).
+		SetNillableNumaNodes(systemConfig.NumaNodes).
+		SetNillableTransparentHugePages(systemConfig.TransparentHugePages).
+		SetNillableDataDirFilesystem(systemConfig.DataDirFilesystem).
+		SetNillableDataDirMountOptions(systemConfig.DataDirMountOptions).
+		SetNillableDiskType(systemConfig.DiskType).
+		SetNillableCgroupVersion(systemConfig.CgroupVersion).
+		SetNillableCgroupCPUQuota(systemConfig.CgroupCPUQuota).
+		SetNillableCgroupMemoryLimit(systemConfig.CgroupMemoryLimit).
+		SetNillableCgroupIoLimits(systemConfig.CgroupIoLimits)
+}
+
+func appendWaitEvents(builders []*ent.WaitEventCreate, tx *ent.Tx, bmarkID pulid.ID, waitEvents []*models.WaitEvent) ([]*ent.WaitEventCreate, error) {
+	for _, waitEvent := range waitEvents {
+		if waitEvent == nil {
+			return nil, fmt.Errorf("wait event is nil")
+		}
+
+		builders = append(builders, tx.WaitEvent.Create().
+			SetBenchmarkID(bmarkID).
+			SetWaitEventType(waitEvent.WaitEventType).
+			SetWaitEvent(waitEvent.WaitEvent).
+			SetCount(waitEvent.Count),
+		)
+	}
 
-	return systemConfig, err
+	return builders, nil
 }
 
-func (db *DB) save(ctx context.Context, tx *ent.Tx, bmark *models.Benchmark) (*models.Benchmark, error) {
-	if tx == nil {
-		return nil, fmt.Errorf("transaction is nil")
+func appendSystemMetricSamples(builders []*ent.SystemMetricSampleCreate, tx *ent.Tx, bmarkID pulid.ID, samples []*models.SystemMetricSample) ([]*ent.SystemMetricSampleCreate, error) {
+	for _, sample := range samples {
+		if sample == nil {
+			return nil, fmt.Errorf("system metric sample is nil")
+		}
+
+		builders = append(builders, tx.SystemMetricSample.Create().
+			SetBenchmarkID(bmarkID).
+			SetSampledAt(sample.SampledAt).
+			SetElapsed(sample.Elapsed).
+			SetCPULoad(sample.CPULoad).
+			SetMemoryLoad(sample.MemoryLoad).
+			SetIowaitLoad(sample.IowaitLoad).
+			SetSwapLoad(sample.SwapLoad).
+			SetLoadAverage(sample.LoadAverage).
+			SetDiskReadBytesPerSecond(sample.DiskReadBytesPerSecond).
+			SetDiskWriteBytesPerSecond(sample.DiskWriteBytesPerSecond).
+			SetNetworkReceivedBytesPerSecond(sample.NetworkReceivedBytesPerSecond).
+			SetNetworkSentBytesPerSecond(sample.NetworkSentBytesPerSecond).
+			SetNillableTransactionsPerSecond(sample.TransactionsPerSecond).
+			SetNillableAverageLatency(sample.AverageLatency),
+		)
 	}
-	if bmark == nil {
-		return nil, fmt.Errorf("benchmark is nil")
+
+	return builders, nil
+}
+
+func appendDiskMetrics(builders []*ent.DiskMetricCreate, tx *ent.Tx, bmarkID pulid.ID, diskMetrics []*models.DiskMetric) ([]*ent.DiskMetricCreate, error) {
+	for _, diskMetric := range diskMetrics {
+		if diskMetric == nil {
+			return nil, fmt.Errorf("disk metric is nil")
+		}
+
+		builders = append(builders, tx.DiskMetric.Create().
+			SetBenchmarkID(bmarkID).
+			SetDevice(diskMetric.Device).
+			SetReadBytesPerSecondAverage(diskMetric.ReadBytesPerSecondAverage).
+			SetReadBytesPerSecondMax(diskMetric.ReadBytesPerSecondMax).
+			SetWriteBytesPerSecondAverage(diskMetric.WriteBytesPerSecondAverage).
+			SetWriteBytesPerSecondMax(diskMetric.WriteBytesPerSecondMax).
+			SetReadIopsAverage(diskMetric.ReadIopsAverage).
+			SetReadIopsMax(diskMetric.ReadIopsMax).
+			SetWriteIopsAverage(diskMetric.WriteIopsAverage).
+			SetWriteIopsMax(diskMetric.WriteIopsMax).
+			SetUtilizationAverage(diskMetric.UtilizationAverage).
+			SetUtilizationMax(diskMetric.UtilizationMax),
+		)
+	}
+
+	return builders, nil
+}
+
+func appendNetworkMetrics(builders []*ent.NetworkMetricCreate, tx *ent.Tx, bmarkID pulid.ID, networkMetrics []*models.NetworkMetric) ([]*ent.NetworkMetricCreate, error) {
+	for _, networkMetric := range networkMetrics {
+		if networkMetric == nil {
+			return nil, fmt.Errorf("network metric is nil")
+		}
+
+		builders = append(builders, tx.NetworkMetric.Create().
+			SetBenchmarkID(bmarkID).
+			SetInterfaceName(networkMetric.InterfaceName).
+			SetBytesReceivedPerSecondAverage(networkMetric.BytesReceivedPerSecondAverage).
+			SetBytesReceivedPerSecondMax(networkMetric.BytesReceivedPerSecondMax).
+			SetBytesSentPerSecondAverage(networkMetric.BytesSentPerSecondAverage).
+			SetBytesSentPerSecondMax(networkMetric.BytesSentPerSecondMax).
+			SetPacketsReceivedPerSecondAverage(networkMetric.PacketsReceivedPerSecondAverage).
+			SetPacketsReceivedPerSecondMax(networkMetric.PacketsReceivedPerSecondMax).
+			SetPacketsSentPerSecondAverage(networkMetric.PacketsSentPerSecondAverage).
+			SetPacketsSentPerSecondMax(networkMetric.PacketsSentPerSecondMax),
+		)
+	}
+
+	return builders, nil
+}
+
+func appendProcessMetrics(builders []*ent.ProcessMetricCreate, tx *ent.Tx, bmarkID pulid.ID, processMetrics []*models.ProcessMetric) ([]*ent.ProcessMetricCreate, error) {
+	for _, processMetric := range processMetrics {
+		if processMetric == nil {
+			return nil, fmt.Errorf("process metric is nil")
+		}
+
+		builders = append(builders, tx.ProcessMetric.Create().
+			SetBenchmarkID(bmarkID).
+			SetRole(processMetric.Role).
+			SetProcessCount(processMetric.ProcessCount).
+			SetCPUAverageLoad(processMetric.CPUAverageLoad).
+			SetCPUMaxLoad(processMetric.CPUMaxLoad).
+			SetMemoryRssAverage(processMetric.MemoryRssAverage).
+			SetMemoryRssMax(processMetric.MemoryRssMax).
+			SetNillableIoReadBytes(processMetric.IoReadBytes).
+			SetNillableIoWriteBytes(processMetric.IoWriteBytes).
+			SetVoluntaryContextSwitches(processMetric.VoluntaryContextSwitches).
+			SetInvoluntaryContextSwitches(processMetric.InvoluntaryContextSwitches),
+		)
 	}
 
-	// Now we can create the benchmark
-	_bmark, err := db.saveBenchmark(ctx, tx, bmark)
+	return builders, nil
+}
+
+// maxVariables is the maximum number of variables of a single statement in SQLite. Bulk inserts are split into chunks
+// that stay below it. PostgreSQL allows even more.
+const maxVariables = 32766
+
+// createInChunks saves the given builders with as few statements as the variable limit allows.
+func createInChunks[B any](ctx context.Context, builders []B, columns int, create func(ctx context.Context, builders ...B) error) error {
+	chunkSize := max(maxVariables/columns, 1)
+	for start := 0; start < len(builders); start += chunkSize {
+		end := min(start+chunkSize, len(builders))
+		if err := create(ctx, builders[start:end]...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagPair identifies a tag by its key-value pair.
+type tagPair struct {
+	key, value string
+}
+
+// upsertTagsOf returns the IDs of the tags of the given benchmarks by their key-value pair. Tags are shared between
+// benchmarks, so each pair is only upserted once.
+func (db *DB) upsertTagsOf(ctx context.Context, tx *ent.Tx, bmarks []*models.Benchmark) (map[tagPair]pulid.ID, error) {
+	var tags []*models.Tag
+	seen := make(map[tagPair]struct{})
+	for _, bmark := range bmarks {
+		for _, t := range bmark.Edges.Tags {
+			if t == nil {
+				return nil, fmt.Errorf("tag is nil")
+			}
+
+			pair := tagPair{key: t.Key, value: t.Value}
+			if _, found := seen[pair]; found {
+				continue
+			}
+
+			seen[pair] = struct{}{}
+			tags = append(tags, t)
+		}
+	}
+
+	ids, err := db.upsertTags(ctx, tx, tags)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set benchmark ID for foreign key compliance
-	bmark.ID = _bmark.ID
+	idsByPair := make(map[tagPair]pulid.ID, len(tags))
+	for idx, t := range tags {
+		idsByPair[tagPair{key: t.Key, value: t.Value}] = ids[idx]
+	}
+
+	return idsByPair, nil
+}
+
+// saveMany saves the given benchmarks along with their edges. Instead of a statement per record, all benchmarks and
+// all records of each edge are inserted in bulk. The benchmarks get the IDs they were stored with.
+func (db *DB) saveMany(ctx context.Context, tx *ent.Tx, bmarks []*models.Benchmark) error {
+	if tx == nil {
+		return fmt.Errorf("transaction is nil")
+	}
+
+	// The groups have to exist before any of their benchmarks
+	groups := make(map[pulid.ID]struct{})
+	for _, bmark := range bmarks {
+		if bmark == nil {
+			return fmt.Errorf("benchmark is nil")
+		}
+		if bmark.Edges.Result == nil {
+			return fmt.Errorf("benchmark result is nil")
+		}
 
-	// Save benchmark result
-	if _, err = db.saveBenchmarkResult(ctx, tx, bmark.ID, bmark.Edges.Result); err != nil {
-		return nil, fmt.Errorf("save benchmark result: %w", err)
+		if _, found := groups[bmark.GroupID]; found {
+			continue
+		}
+		if err := db.ensureGroup(ctx, tx, bmark); err != nil {
+			return fmt.Errorf("save benchmark group: %w", err)
+		}
+		groups[bmark.GroupID] = struct{}{}
 	}
 
-	// Save system metric
-	if _, err = db.saveSystemMetric(ctx, tx, bmark.ID, bmark.Edges.SystemMetric); err != nil {
-		return nil, fmt.Errorf("save system metric: %w", err)
+	tagIDs, err := db.upsertTagsOf(ctx, tx, bmarks)
+	if err != nil {
+		return err
 	}
 
-	// System config are optional, only save if they are given
-	if bmark.Edges.System != nil {
-		bmark.Edges.System, err = db.saveSystemConfig(ctx, tx, bmark.ID, bmark.Edges.System)
-		if err != nil {
-			return nil, fmt.Errorf("save system config: %w", err)
+	// Now we can create the benchmarks
+	benchmarkBuilders := make([]*ent.BenchmarkCreate, 0, len(bmarks))
+	for _, bmark := range bmarks {
+		ids := make([]pulid.ID, 0, len(bmark.Edges.Tags))
+		for _, t := range bmark.Edges.Tags {
+			ids = append(ids, tagIDs[tagPair{key: t.Key, value: t.Value}])
 		}
+
+		benchmarkBuilders = append(benchmarkBuilders, benchmarkCreate(tx, bmark, ids))
 	}
 
-	return bmark, nil
+	saved := make([]*models.Benchmark, 0, len(bmarks))
+	err = createInChunks(ctx, benchmarkBuilders, len(benchmark.Columns), func(ctx context.Context, builders ...*ent.BenchmarkCreate) error {
+		chunk, err := tx.Benchmark.CreateBulk(builders...).Save(ctx)
+		saved = append(saved, chunk...)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("save benchmarks: %w", err)
+	}
+
+	// Set benchmark IDs for foreign key compliance
+	for idx, bmark := range bmarks {
+		bmark.ID = saved[idx].ID
+	}
+
+	var (
+		results     []*ent.BenchmarkResultCreate
+		outputs     []*ent.BenchmarkOutputCreate
+		metrics     []*ent.SystemMetricCreate
+		configs     []*ent.SystemConfigCreate
+		samples     []*ent.SystemMetricSampleCreate
+		diskMetrics []*ent.DiskMetricCreate
+		netMetrics  []*ent.NetworkMetricCreate
+		procMetrics []*ent.ProcessMetricCreate
+		waitEvents  []*ent.WaitEventCreate
+	)
+
+	for _, bmark := range bmarks {
+		results = append(results, benchmarkResultCreate(tx, bmark.ID, bmark.Edges.Result))
+
+		// The raw output is missing for benchmarks that were recorded before it was stored
+		if bmark.Edges.Output != nil {
+			outputs = append(outputs, benchmarkOutputCreate(tx, bmark.ID, bmark.Edges.Output))
+		}
+
+		// System metric is optional, it's missing if the host collector was disabled
+		if bmark.Edges.SystemMetric != nil {
+			metrics = append(metrics, systemMetricCreate(tx, bmark.ID, bmark.Edges.SystemMetric))
+		}
+
+		// System config are optional, only save if they are given
+		if bmark.Edges.System != nil {
+			configs = append(configs, systemConfigCreate(tx, bmark.ID, bmark.Edges.System))
+		}
+
+		// Samples, disk, network and process metrics and wait events are optional, only save if they are given
+		if samples, err = appendSystemMetricSamples(samples, tx, bmark.ID, bmark.Edges.SystemMetricSamples); err != nil {
+			return fmt.Errorf("save system metric samples: %w", err)
+		}
+		if diskMetrics, err = appendDiskMetrics(diskMetrics, tx, bmark.ID, bmark.Edges.DiskMetrics); err != nil {
+			return fmt.Errorf("save disk metrics: %w", err)
+		}
+		if netMetrics, err = appendNetworkMetrics(netMetrics, tx, bmark.ID, bmark.Edges.NetworkMetrics); err != nil {
+			return fmt.Errorf("save network metrics: %w", err)
+		}
+		if procMetrics, err = appendProcessMetrics(procMetrics, tx, bmark.ID, bmark.Edges.ProcessMetrics); err != nil {
+			return fmt.Errorf("save process metrics: %w", err)
+		}
+		if waitEvents, err = appendWaitEvents(waitEvents, tx, bmark.ID, bmark.Edges.WaitEvents); err != nil {
+			return fmt.Errorf("save wait events: %w", err)
+		}
+	}
+
+	err = createInChunks(ctx, results, len(benchmarkresult.Columns), func(ctx context.Context, builders ...*ent.BenchmarkResultCreate) error {
+		return tx.BenchmarkResult.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save benchmark results: %w", err)
+	}
+
+	err = createInChunks(ctx, outputs, len(benchmarkoutput.Columns), func(ctx context.Context, builders ...*ent.BenchmarkOutputCreate) error {
+		return tx.BenchmarkOutput.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save benchmark outputs: %w", err)
+	}
+
+	err = createInChunks(ctx, metrics, len(systemmetric.Columns), func(ctx context.Context, builders ...*ent.SystemMetricCreate) error {
+		return tx.SystemMetric.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save system metrics: %w", err)
+	}
+
+	err = createInChunks(ctx, configs, len(systemconfig.Columns), func(ctx context.Context, builders ...*ent.SystemConfigCreate) error {
+		return tx.SystemConfig.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save system configs: %w", err)
+	}
+
+	err = createInChunks(ctx, samples, len(systemmetricsample.Columns), func(ctx context.Context, builders ...*ent.SystemMetricSampleCreate) error {
+		return tx.SystemMetricSample.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save system metric samples: %w", err)
+	}
+
+	err = createInChunks(ctx, diskMetrics, len(diskmetric.Columns), func(ctx context.Context, builders ...*ent.DiskMetricCreate) error {
+		return tx.DiskMetric.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save disk metrics: %w", err)
+	}
+
+	err = createInChunks(ctx, netMetrics, len(networkmetric.Columns), func(ctx context.Context, builders ...*ent.NetworkMetricCreate) error {
+		return tx.NetworkMetric.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save network metrics: %w", err)
+	}
+
+	err = createInChunks(ctx, procMetrics, len(processmetric.Columns), func(ctx context.Context, builders ...*ent.ProcessMetricCreate) error {
+		return tx.ProcessMetric.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save process metrics: %w", err)
+	}
+
+	err = createInChunks(ctx, waitEvents, len(waitevent.Columns), func(ctx context.Context, builders ...*ent.WaitEventCreate) error {
+		return tx.WaitEvent.CreateBulk(builders...).Exec(ctx)
+	})
+	if err != nil {
+		return fmt.Errorf("save wait events: %w", err)
+	}
+
+	return nil
 }
 
 // Save saves a benchmark to the database.
@@ -160,8 +481,7 @@ func (db *DB) Save(ctx context.Context, bmark *models.Benchmark) (*models.Benchm
 		return nil, fmt.Errorf("start transaction: %w", err)
 	}
 
-	bmark, err = db.save(ctx, tx, bmark)
-	if err != nil {
+	if err := db.saveMany(ctx, tx, []*models.Benchmark{bmark}); err != nil {
 		return nil, rollback(tx, err)
 	}
 
@@ -172,18 +492,15 @@ func (db *DB) Save(ctx context.Context, bmark *models.Benchmark) (*models.Benchm
 	return bmark, nil
 }
 
-// SaveMany saves multiple benchmarks to the database.
+// SaveMany saves multiple benchmarks to the database. They're inserted in bulk within a single transaction.
 func (db *DB) SaveMany(ctx context.Context, bmarks []*models.Benchmark) ([]*models.Benchmark, error) {
 	tx, err := db.client.Tx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("start transaction: %w", err)
 	}
 
-	for idx, bmark := range bmarks {
-		bmarks[idx], err = db.save(ctx, tx, bmark)
-		if err != nil {
-			return nil, rollback(tx, err)
-		}
+	if err := db.saveMany(ctx, tx, bmarks); err != nil {
+		return nil, rollback(tx, err)
 	}
 
 	if err := tx.Commit(); err != nil {
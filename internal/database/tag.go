@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/ent/tag"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// upsertTags returns the IDs of the given tags. Tags that don't exist yet are created, so that each key-value pair is
+// only stored once.
+func (db *DB) upsertTags(ctx context.Context, tx *ent.Tx, tags []*models.Tag) ([]pulid.ID, error) {
+	if tx == nil {
+		return nil, fmt.Errorf("transaction is nil")
+	}
+
+	ids := make([]pulid.ID, 0, len(tags))
+	for _, t := range tags {
+		if t == nil {
+			return nil, fmt.Errorf("tag is nil")
+		}
+
+		id, err := tx.Tag.Query().
+			Where(tag.Key(t.Key), tag.Value(t.Value)).
+			OnlyID(ctx)
+		if ent.IsNotFound(err) {
+			var created *models.Tag
+			created, err = tx.Tag.Create().
+				SetKey(t.Key).
+				SetValue(t.Value).
+				Save(ctx)
+			if err == nil {
+				id = created.ID
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("upsert tag %s=%s: %w", t.Key, t.Value, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// removeOrphanedTags removes the tags that aren't attached to any benchmark anymore.
+func removeOrphanedTags(ctx context.Context, client *ent.Client) error {
+	_, err := client.Tag.Delete().
+		Where(tag.Not(tag.HasBenchmarks())).
+		Exec(ctx)
+
+	return err
+}
+
+// TagByGroupIDs sets the given tags on all benchmarks of the given groups and removes the tags with the given keys. A
+// tag replaces the value of an existing tag with the same key.
+func (db *DB) TagByGroupIDs(ctx context.Context, ids []string, tags []*models.Tag, removeKeys []string) error {
+	// Convert string ids to pulid.ID
+	pulids, err := convertToPULID(ids)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.client.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("start transaction: %w", err)
+	}
+
+	if err := db.tagByGroupIDs(ctx, tx, pulids, tags, removeKeys); err != nil {
+		return rollback(tx, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (db *DB) tagByGroupIDs(ctx context.Context, tx *ent.Tx, groupIDs []pulid.ID, tags []*models.Tag, removeKeys []string) error {
+	// Detach all tags whose keys are either removed or about to get a new value
+	keys := append([]string(nil), removeKeys...)
+	for _, t := range tags {
+		keys = append(keys, t.Key)
+	}
+
+	detachIDs, err := tx.Tag.Query().
+		Where(tag.KeyIn(keys...)).
+		IDs(ctx)
+	if err != nil {
+		return fmt.Errorf("query tags: %w", err)
+	}
+
+	attachIDs, err := db.upsertTags(ctx, tx, tags)
+	if err != nil {
+		return err
+	}
+
+	// Edges are detached before they're attached, so re-setting a tag that is already attached is safe
+	err = tx.Benchmark.Update().
+		Where(benchmark.GroupIDIn(groupIDs...)).
+		RemoveTagIDs(detachIDs...).
+		AddTagIDs(attachIDs...).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("update benchmark tags: %w", err)
+	}
+
+	if err := removeOrphanedTags(ctx, tx.Client()); err != nil {
+		return fmt.Errorf("remove orphaned tags: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,495 @@
+package database
+
+import (
+	"time"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/benchmark"
+	"github.com/nikoksr/dbench/ent/benchmarkresult"
+	"github.com/nikoksr/dbench/ent/predicate"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/ent/systemconfig"
+	"github.com/nikoksr/dbench/ent/systemmetric"
+	"github.com/nikoksr/dbench/internal/where"
+)
+
+// fieldPredicate returns the predicate that compares a field with the given value.
+type fieldPredicate func(op where.Op, value any) predicate.Benchmark
+
+// fieldPredicates maps the columns of the fields of where expressions to their generated predicates, by entity.
+// Conditions on the result, system metric and system config of a benchmark are matched against the respective edge.
+var fieldPredicates = map[where.Entity]map[string]fieldPredicate{
+	where.EntityBenchmark: {
+		"id": typed(benchmark.And, toID,
+			benchmark.IDEQ, benchmark.IDNEQ,
+			benchmark.IDLT, benchmark.IDLTE,
+			benchmark.IDGT, benchmark.IDGTE,
+		),
+		"group_id": typed(benchmark.And, toID,
+			benchmark.GroupIDEQ, benchmark.GroupIDNEQ,
+			benchmark.GroupIDLT, benchmark.GroupIDLTE,
+			benchmark.GroupIDGT, benchmark.GroupIDGTE,
+		),
+		"comment": typed(benchmark.And, as[string],
+			benchmark.CommentEQ, benchmark.CommentNEQ,
+			benchmark.CommentLT, benchmark.CommentLTE,
+			benchmark.CommentGT, benchmark.CommentGTE,
+		),
+		"version": typed(benchmark.And, as[string],
+			benchmark.VersionEQ, benchmark.VersionNEQ,
+			benchmark.VersionLT, benchmark.VersionLTE,
+			benchmark.VersionGT, benchmark.VersionGTE,
+		),
+		"transaction_type": typed(benchmark.And, as[string],
+			benchmark.TransactionTypeEQ, benchmark.TransactionTypeNEQ,
+			benchmark.TransactionTypeLT, benchmark.TransactionTypeLTE,
+			benchmark.TransactionTypeGT, benchmark.TransactionTypeGTE,
+		),
+		"scaling_factor": typed(benchmark.And, as[float64],
+			benchmark.ScalingFactorEQ, benchmark.ScalingFactorNEQ,
+			benchmark.ScalingFactorLT, benchmark.ScalingFactorLTE,
+			benchmark.ScalingFactorGT, benchmark.ScalingFactorGTE,
+		),
+		"query_mode": typed(benchmark.And, as[string],
+			benchmark.QueryModeEQ, benchmark.QueryModeNEQ,
+			benchmark.QueryModeLT, benchmark.QueryModeLTE,
+			benchmark.QueryModeGT, benchmark.QueryModeGTE,
+		),
+		"clients": typed(benchmark.And, toInteger[int],
+			benchmark.ClientsEQ, benchmark.ClientsNEQ,
+			benchmark.ClientsLT, benchmark.ClientsLTE,
+			benchmark.ClientsGT, benchmark.ClientsGTE,
+		),
+		"threads": typed(benchmark.And, toInteger[int],
+			benchmark.ThreadsEQ, benchmark.ThreadsNEQ,
+			benchmark.ThreadsLT, benchmark.ThreadsLTE,
+			benchmark.ThreadsGT, benchmark.ThreadsGTE,
+		),
+		"recorded_at": typed(benchmark.And, as[time.Time],
+			benchmark.RecordedAtEQ, benchmark.RecordedAtNEQ,
+			benchmark.RecordedAtLT, benchmark.RecordedAtLTE,
+			benchmark.RecordedAtGT, benchmark.RecordedAtGTE,
+		),
+	},
+	where.EntityBenchmarkResult: {
+		"transactions": typed(benchmark.HasResultWith, toInteger[int],
+			benchmarkresult.TransactionsEQ, benchmarkresult.TransactionsNEQ,
+			benchmarkresult.TransactionsLT, benchmarkresult.TransactionsLTE,
+			benchmarkresult.TransactionsGT, benchmarkresult.TransactionsGTE,
+		),
+		"failed_transactions": typed(benchmark.HasResultWith, toInteger[int],
+			benchmarkresult.FailedTransactionsEQ, benchmarkresult.FailedTransactionsNEQ,
+			benchmarkresult.FailedTransactionsLT, benchmarkresult.FailedTransactionsLTE,
+			benchmarkresult.FailedTransactionsGT, benchmarkresult.FailedTransactionsGTE,
+		),
+		"transactions_per_second": typed(benchmark.HasResultWith, as[float64],
+			benchmarkresult.TransactionsPerSecondEQ, benchmarkresult.TransactionsPerSecondNEQ,
+			benchmarkresult.TransactionsPerSecondLT, benchmarkresult.TransactionsPerSecondLTE,
+			benchmarkresult.TransactionsPerSecondGT, benchmarkresult.TransactionsPerSecondGTE,
+		),
+		"average_latency": typed(benchmark.HasResultWith, toDuration,
+			benchmarkresult.AverageLatencyEQ, benchmarkresult.AverageLatencyNEQ,
+			benchmarkresult.AverageLatencyLT, benchmarkresult.AverageLatencyLTE,
+			benchmarkresult.AverageLatencyGT, benchmarkresult.AverageLatencyGTE,
+		),
+		"connection_time": typed(benchmark.HasResultWith, toDuration,
+			benchmarkresult.ConnectionTimeEQ, benchmarkresult.ConnectionTimeNEQ,
+			benchmarkresult.ConnectionTimeLT, benchmarkresult.ConnectionTimeLTE,
+			benchmarkresult.ConnectionTimeGT, benchmarkresult.ConnectionTimeGTE,
+		),
+		"total_runtime": typed(benchmark.HasResultWith, toDuration,
+			benchmarkresult.TotalRuntimeEQ, benchmarkresult.TotalRuntimeNEQ,
+			benchmarkresult.TotalRuntimeLT, benchmarkresult.TotalRuntimeLTE,
+			benchmarkresult.TotalRuntimeGT, benchmarkresult.TotalRuntimeGTE,
+		),
+	},
+	where.EntitySystemMetric: {
+		"cpu_min_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPUMinLoadEQ, systemmetric.CPUMinLoadNEQ,
+			systemmetric.CPUMinLoadLT, systemmetric.CPUMinLoadLTE,
+			systemmetric.CPUMinLoadGT, systemmetric.CPUMinLoadGTE,
+		),
+		"cpu_max_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPUMaxLoadEQ, systemmetric.CPUMaxLoadNEQ,
+			systemmetric.CPUMaxLoadLT, systemmetric.CPUMaxLoadLTE,
+			systemmetric.CPUMaxLoadGT, systemmetric.CPUMaxLoadGTE,
+		),
+		"cpu_average_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPUAverageLoadEQ, systemmetric.CPUAverageLoadNEQ,
+			systemmetric.CPUAverageLoadLT, systemmetric.CPUAverageLoadLTE,
+			systemmetric.CPUAverageLoadGT, systemmetric.CPUAverageLoadGTE,
+		),
+		"cpu_50th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPU50thLoadEQ, systemmetric.CPU50thLoadNEQ,
+			systemmetric.CPU50thLoadLT, systemmetric.CPU50thLoadLTE,
+			systemmetric.CPU50thLoadGT, systemmetric.CPU50thLoadGTE,
+		),
+		"cpu_75th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPU75thLoadEQ, systemmetric.CPU75thLoadNEQ,
+			systemmetric.CPU75thLoadLT, systemmetric.CPU75thLoadLTE,
+			systemmetric.CPU75thLoadGT, systemmetric.CPU75thLoadGTE,
+		),
+		"cpu_90th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPU90thLoadEQ, systemmetric.CPU90thLoadNEQ,
+			systemmetric.CPU90thLoadLT, systemmetric.CPU90thLoadLTE,
+			systemmetric.CPU90thLoadGT, systemmetric.CPU90thLoadGTE,
+		),
+		"cpu_95th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPU95thLoadEQ, systemmetric.CPU95thLoadNEQ,
+			systemmetric.CPU95thLoadLT, systemmetric.CPU95thLoadLTE,
+			systemmetric.CPU95thLoadGT, systemmetric.CPU95thLoadGTE,
+		),
+		"cpu_99th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.CPU99thLoadEQ, systemmetric.CPU99thLoadNEQ,
+			systemmetric.CPU99thLoadLT, systemmetric.CPU99thLoadLTE,
+			systemmetric.CPU99thLoadGT, systemmetric.CPU99thLoadGTE,
+		),
+		"memory_min_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.MemoryMinLoadEQ, systemmetric.MemoryMinLoadNEQ,
+			systemmetric.MemoryMinLoadLT, systemmetric.MemoryMinLoadLTE,
+			systemmetric.MemoryMinLoadGT, systemmetric.MemoryMinLoadGTE,
+		),
+		"memory_max_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.MemoryMaxLoadEQ, systemmetric.MemoryMaxLoadNEQ,
+			systemmetric.MemoryMaxLoadLT, systemmetric.MemoryMaxLoadLTE,
+			systemmetric.MemoryMaxLoadGT, systemmetric.MemoryMaxLoadGTE,
+		),
+		"memory_average_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.MemoryAverageLoadEQ, systemmetric.MemoryAverageLoadNEQ,
+			systemmetric.MemoryAverageLoadLT, systemmetric.MemoryAverageLoadLTE,
+			systemmetric.MemoryAverageLoadGT, systemmetric.MemoryAverageLoadGTE,
+		),
+		"memory_50th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Memory50thLoadEQ, systemmetric.Memory50thLoadNEQ,
+			systemmetric.Memory50thLoadLT, systemmetric.Memory50thLoadLTE,
+			systemmetric.Memory50thLoadGT, systemmetric.Memory50thLoadGTE,
+		),
+		"memory_75th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Memory75thLoadEQ, systemmetric.Memory75thLoadNEQ,
+			systemmetric.Memory75thLoadLT, systemmetric.Memory75thLoadLTE,
+			systemmetric.Memory75thLoadGT, systemmetric.Memory75thLoadGTE,
+		),
+		"memory_90th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Memory90thLoadEQ, systemmetric.Memory90thLoadNEQ,
+			systemmetric.Memory90thLoadLT, systemmetric.Memory90thLoadLTE,
+			systemmetric.Memory90thLoadGT, systemmetric.Memory90thLoadGTE,
+		),
+		"memory_95th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Memory95thLoadEQ, systemmetric.Memory95thLoadNEQ,
+			systemmetric.Memory95thLoadLT, systemmetric.Memory95thLoadLTE,
+			systemmetric.Memory95thLoadGT, systemmetric.Memory95thLoadGTE,
+		),
+		"memory_99th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Memory99thLoadEQ, systemmetric.Memory99thLoadNEQ,
+			systemmetric.Memory99thLoadLT, systemmetric.Memory99thLoadLTE,
+			systemmetric.Memory99thLoadGT, systemmetric.Memory99thLoadGTE,
+		),
+		"iowait_min_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.IowaitMinLoadEQ, systemmetric.IowaitMinLoadNEQ,
+			systemmetric.IowaitMinLoadLT, systemmetric.IowaitMinLoadLTE,
+			systemmetric.IowaitMinLoadGT, systemmetric.IowaitMinLoadGTE,
+		),
+		"iowait_max_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.IowaitMaxLoadEQ, systemmetric.IowaitMaxLoadNEQ,
+			systemmetric.IowaitMaxLoadLT, systemmetric.IowaitMaxLoadLTE,
+			systemmetric.IowaitMaxLoadGT, systemmetric.IowaitMaxLoadGTE,
+		),
+		"iowait_average_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.IowaitAverageLoadEQ, systemmetric.IowaitAverageLoadNEQ,
+			systemmetric.IowaitAverageLoadLT, systemmetric.IowaitAverageLoadLTE,
+			systemmetric.IowaitAverageLoadGT, systemmetric.IowaitAverageLoadGTE,
+		),
+		"iowait_50th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Iowait50thLoadEQ, systemmetric.Iowait50thLoadNEQ,
+			systemmetric.Iowait50thLoadLT, systemmetric.Iowait50thLoadLTE,
+			systemmetric.Iowait50thLoadGT, systemmetric.Iowait50thLoadGTE,
+		),
+		"iowait_75th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Iowait75thLoadEQ, systemmetric.Iowait75thLoadNEQ,
+			systemmetric.Iowait75thLoadLT, systemmetric.Iowait75thLoadLTE,
+			systemmetric.Iowait75thLoadGT, systemmetric.Iowait75thLoadGTE,
+		),
+		"iowait_90th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Iowait90thLoadEQ, systemmetric.Iowait90thLoadNEQ,
+			systemmetric.Iowait90thLoadLT, systemmetric.Iowait90thLoadLTE,
+			systemmetric.Iowait90thLoadGT, systemmetric.Iowait90thLoadGTE,
+		),
+		"iowait_95th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Iowait95thLoadEQ, systemmetric.Iowait95thLoadNEQ,
+			systemmetric.Iowait95thLoadLT, systemmetric.Iowait95thLoadLTE,
+			systemmetric.Iowait95thLoadGT, systemmetric.Iowait95thLoadGTE,
+		),
+		"iowait_99th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Iowait99thLoadEQ, systemmetric.Iowait99thLoadNEQ,
+			systemmetric.Iowait99thLoadLT, systemmetric.Iowait99thLoadLTE,
+			systemmetric.Iowait99thLoadGT, systemmetric.Iowait99thLoadGTE,
+		),
+		"swap_min_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.SwapMinLoadEQ, systemmetric.SwapMinLoadNEQ,
+			systemmetric.SwapMinLoadLT, systemmetric.SwapMinLoadLTE,
+			systemmetric.SwapMinLoadGT, systemmetric.SwapMinLoadGTE,
+		),
+		"swap_max_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.SwapMaxLoadEQ, systemmetric.SwapMaxLoadNEQ,
+			systemmetric.SwapMaxLoadLT, systemmetric.SwapMaxLoadLTE,
+			systemmetric.SwapMaxLoadGT, systemmetric.SwapMaxLoadGTE,
+		),
+		"swap_average_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.SwapAverageLoadEQ, systemmetric.SwapAverageLoadNEQ,
+			systemmetric.SwapAverageLoadLT, systemmetric.SwapAverageLoadLTE,
+			systemmetric.SwapAverageLoadGT, systemmetric.SwapAverageLoadGTE,
+		),
+		"swap_50th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Swap50thLoadEQ, systemmetric.Swap50thLoadNEQ,
+			systemmetric.Swap50thLoadLT, systemmetric.Swap50thLoadLTE,
+			systemmetric.Swap50thLoadGT, systemmetric.Swap50thLoadGTE,
+		),
+		"swap_75th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Swap75thLoadEQ, systemmetric.Swap75thLoadNEQ,
+			systemmetric.Swap75thLoadLT, systemmetric.Swap75thLoadLTE,
+			systemmetric.Swap75thLoadGT, systemmetric.Swap75thLoadGTE,
+		),
+		"swap_90th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Swap90thLoadEQ, systemmetric.Swap90thLoadNEQ,
+			systemmetric.Swap90thLoadLT, systemmetric.Swap90thLoadLTE,
+			systemmetric.Swap90thLoadGT, systemmetric.Swap90thLoadGTE,
+		),
+		"swap_95th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Swap95thLoadEQ, systemmetric.Swap95thLoadNEQ,
+			systemmetric.Swap95thLoadLT, systemmetric.Swap95thLoadLTE,
+			systemmetric.Swap95thLoadGT, systemmetric.Swap95thLoadGTE,
+		),
+		"swap_99th_load": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.Swap99thLoadEQ, systemmetric.Swap99thLoadNEQ,
+			systemmetric.Swap99thLoadLT, systemmetric.Swap99thLoadLTE,
+			systemmetric.Swap99thLoadGT, systemmetric.Swap99thLoadGTE,
+		),
+		"load_average_min": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverageMinEQ, systemmetric.LoadAverageMinNEQ,
+			systemmetric.LoadAverageMinLT, systemmetric.LoadAverageMinLTE,
+			systemmetric.LoadAverageMinGT, systemmetric.LoadAverageMinGTE,
+		),
+		"load_average_max": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverageMaxEQ, systemmetric.LoadAverageMaxNEQ,
+			systemmetric.LoadAverageMaxLT, systemmetric.LoadAverageMaxLTE,
+			systemmetric.LoadAverageMaxGT, systemmetric.LoadAverageMaxGTE,
+		),
+		"load_average_average": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverageAverageEQ, systemmetric.LoadAverageAverageNEQ,
+			systemmetric.LoadAverageAverageLT, systemmetric.LoadAverageAverageLTE,
+			systemmetric.LoadAverageAverageGT, systemmetric.LoadAverageAverageGTE,
+		),
+		"load_average_50th": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverage50thEQ, systemmetric.LoadAverage50thNEQ,
+			systemmetric.LoadAverage50thLT, systemmetric.LoadAverage50thLTE,
+			systemmetric.LoadAverage50thGT, systemmetric.LoadAverage50thGTE,
+		),
+		"load_average_75th": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverage75thEQ, systemmetric.LoadAverage75thNEQ,
+			systemmetric.LoadAverage75thLT, systemmetric.LoadAverage75thLTE,
+			systemmetric.LoadAverage75thGT, systemmetric.LoadAverage75thGTE,
+		),
+		"load_average_90th": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverage90thEQ, systemmetric.LoadAverage90thNEQ,
+			systemmetric.LoadAverage90thLT, systemmetric.LoadAverage90thLTE,
+			systemmetric.LoadAverage90thGT, systemmetric.LoadAverage90thGTE,
+		),
+		"load_average_95th": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverage95thEQ, systemmetric.LoadAverage95thNEQ,
+			systemmetric.LoadAverage95thLT, systemmetric.LoadAverage95thLTE,
+			systemmetric.LoadAverage95thGT, systemmetric.LoadAverage95thGTE,
+		),
+		"load_average_99th": typed(benchmark.HasSystemMetricWith, as[float64],
+			systemmetric.LoadAverage99thEQ, systemmetric.LoadAverage99thNEQ,
+			systemmetric.LoadAverage99thLT, systemmetric.LoadAverage99thLTE,
+			systemmetric.LoadAverage99thGT, systemmetric.LoadAverage99thGTE,
+		),
+	},
+	where.EntitySystemConfig: {
+		"machine_id": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.MachineIDEQ, systemconfig.MachineIDNEQ,
+			systemconfig.MachineIDLT, systemconfig.MachineIDLTE,
+			systemconfig.MachineIDGT, systemconfig.MachineIDGTE,
+		),
+		"os_name": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.OsNameEQ, systemconfig.OsNameNEQ,
+			systemconfig.OsNameLT, systemconfig.OsNameLTE,
+			systemconfig.OsNameGT, systemconfig.OsNameGTE,
+		),
+		"os_arch": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.OsArchEQ, systemconfig.OsArchNEQ,
+			systemconfig.OsArchLT, systemconfig.OsArchLTE,
+			systemconfig.OsArchGT, systemconfig.OsArchGTE,
+		),
+		"cpu_vendor": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.CPUVendorEQ, systemconfig.CPUVendorNEQ,
+			systemconfig.CPUVendorLT, systemconfig.CPUVendorLTE,
+			systemconfig.CPUVendorGT, systemconfig.CPUVendorGTE,
+		),
+		"cpu_model": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.CPUModelEQ, systemconfig.CPUModelNEQ,
+			systemconfig.CPUModelLT, systemconfig.CPUModelLTE,
+			systemconfig.CPUModelGT, systemconfig.CPUModelGTE,
+		),
+		"cpu_count": typed(benchmark.HasSystemWith, toInteger[uint32],
+			systemconfig.CPUCountEQ, systemconfig.CPUCountNEQ,
+			systemconfig.CPUCountLT, systemconfig.CPUCountLTE,
+			systemconfig.CPUCountGT, systemconfig.CPUCountGTE,
+		),
+		"cpu_cores": typed(benchmark.HasSystemWith, toInteger[uint32],
+			systemconfig.CPUCoresEQ, systemconfig.CPUCoresNEQ,
+			systemconfig.CPUCoresLT, systemconfig.CPUCoresLTE,
+			systemconfig.CPUCoresGT, systemconfig.CPUCoresGTE,
+		),
+		"cpu_threads": typed(benchmark.HasSystemWith, toInteger[uint32],
+			systemconfig.CPUThreadsEQ, systemconfig.CPUThreadsNEQ,
+			systemconfig.CPUThreadsLT, systemconfig.CPUThreadsLTE,
+			systemconfig.CPUThreadsGT, systemconfig.CPUThreadsGTE,
+		),
+		"ram_physical": typed(benchmark.HasSystemWith, toInteger[uint64],
+			systemconfig.RAMPhysicalEQ, systemconfig.RAMPhysicalNEQ,
+			systemconfig.RAMPhysicalLT, systemconfig.RAMPhysicalLTE,
+			systemconfig.RAMPhysicalGT, systemconfig.RAMPhysicalGTE,
+		),
+		"ram_usable": typed(benchmark.HasSystemWith, toInteger[uint64],
+			systemconfig.RAMUsableEQ, systemconfig.RAMUsableNEQ,
+			systemconfig.RAMUsableLT, systemconfig.RAMUsableLTE,
+			systemconfig.RAMUsableGT, systemconfig.RAMUsableGTE,
+		),
+		"disk_count": typed(benchmark.HasSystemWith, toInteger[uint32],
+			systemconfig.DiskCountEQ, systemconfig.DiskCountNEQ,
+			systemconfig.DiskCountLT, systemconfig.DiskCountLTE,
+			systemconfig.DiskCountGT, systemconfig.DiskCountGTE,
+		),
+		"disk_space_total": typed(benchmark.HasSystemWith, toInteger[uint64],
+			systemconfig.DiskSpaceTotalEQ, systemconfig.DiskSpaceTotalNEQ,
+			systemconfig.DiskSpaceTotalLT, systemconfig.DiskSpaceTotalLTE,
+			systemconfig.DiskSpaceTotalGT, systemconfig.DiskSpaceTotalGTE,
+		),
+		"disk_type": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.DiskTypeEQ, systemconfig.DiskTypeNEQ,
+			systemconfig.DiskTypeLT, systemconfig.DiskTypeLTE,
+			systemconfig.DiskTypeGT, systemconfig.DiskTypeGTE,
+		),
+		"kernel_version": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.KernelVersionEQ, systemconfig.KernelVersionNEQ,
+			systemconfig.KernelVersionLT, systemconfig.KernelVersionLTE,
+			systemconfig.KernelVersionGT, systemconfig.KernelVersionGTE,
+		),
+		"cpu_governor": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.CPUGovernorEQ, systemconfig.CPUGovernorNEQ,
+			systemconfig.CPUGovernorLT, systemconfig.CPUGovernorLTE,
+			systemconfig.CPUGovernorGT, systemconfig.CPUGovernorGTE,
+		),
+		"numa_nodes": typed(benchmark.HasSystemWith, toInteger[uint32],
+			systemconfig.NumaNodesEQ, systemconfig.NumaNodesNEQ,
+			systemconfig.NumaNodesLT, systemconfig.NumaNodesLTE,
+			systemconfig.NumaNodesGT, systemconfig.NumaNodesGTE,
+		),
+		"transparent_huge_pages": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.TransparentHugePagesEQ, systemconfig.TransparentHugePagesNEQ,
+			systemconfig.TransparentHugePagesLT, systemconfig.TransparentHugePagesLTE,
+			systemconfig.TransparentHugePagesGT, systemconfig.TransparentHugePagesGTE,
+		),
+		"data_dir_filesystem": typed(benchmark.HasSystemWith, as[string],
+			systemconfig.DataDirFilesystemEQ, systemconfig.DataDirFilesystemNEQ,
+			systemconfig.DataDirFilesystemLT, systemconfig.DataDirFilesystemLTE,
+			systemconfig.DataDirFilesystemGT, systemconfig.DataDirFilesystemGTE,
+		),
+		"cgroup_version": typed(benchmark.HasSystemWith, toInteger[uint8],
+			systemconfig.CgroupVersionEQ, systemconfig.CgroupVersionNEQ,
+			systemconfig.CgroupVersionLT, systemconfig.CgroupVersionLTE,
+			systemconfig.CgroupVersionGT, systemconfig.CgroupVersionGTE,
+		),
+		"cgroup_cpu_quota": typed(benchmark.HasSystemWith, as[float64],
+			systemconfig.CgroupCPUQuotaEQ, systemconfig.CgroupCPUQuotaNEQ,
+			systemconfig.CgroupCPUQuotaLT, systemconfig.CgroupCPUQuotaLTE,
+			systemconfig.CgroupCPUQuotaGT, systemconfig.CgroupCPUQuotaGTE,
+		),
+		"cgroup_memory_limit": typed(benchmark.HasSystemWith, toInteger[uint64],
+			systemconfig.CgroupMemoryLimitEQ, systemconfig.CgroupMemoryLimitNEQ,
+			systemconfig.CgroupMemoryLimitLT, systemconfig.CgroupMemoryLimitLTE,
+			systemconfig.CgroupMemoryLimitGT, systemconfig.CgroupMemoryLimitGTE,
+		),
+	},
+}
+
+// typed returns the predicate of a field from its generated predicates. The value is converted to the type of the field
+// first, the predicates of edges are matched with has.
+func typed[T, P any](has func(...P) predicate.Benchmark, convert func(any) T, eq, neq, lt, lte, gt, gte func(T) P) fieldPredicate {
+	byOp := map[where.Op]func(T) P{
+		where.OpEQ:  eq,
+		where.OpNEQ: neq,
+		where.OpLT:  lt,
+		where.OpLTE: lte,
+		where.OpGT:  gt,
+		where.OpGTE: gte,
+	}
+
+	return func(op where.Op, value any) predicate.Benchmark {
+		return has(byOp[op](convert(value)))
+	}
+}
+
+// as returns the value as it is. The where package already parsed it into the type of the field.
+func as[T any](value any) T {
+	return value.(T)
+}
+
+func toID(value any) pulid.ID {
+	return pulid.ID(value.(string))
+}
+
+// toDuration converts a duration to the type that durations are stored in.
+func toDuration(value any) duration.Duration {
+	return duration.Duration(value.(time.Duration))
+}
+
+// toInteger converts an integer value to the integer type of a field. Values beyond the range of the type are clamped
+// to it, e.g. a negative value to zero for unsigned types.
+func toInteger[T int | uint8 | uint32 | uint64](value any) T {
+	v := value.(int64)
+
+	converted := T(v)
+	if int64(converted) == v && (v < 0) == (converted < 0) {
+		return converted
+	}
+
+	if v < 0 {
+		return 0
+	}
+
+	return ^T(0)
+}
+
+// WithWhere is a function that returns a QueryOption that only matches benchmarks that satisfy the given expression.
+func WithWhere(node where.Node) QueryOption {
+	return WithFilter(func(query *ent.BenchmarkQuery) *ent.BenchmarkQuery {
+		return query.Where(wherePredicate(node))
+	})
+}
+
+func wherePredicate(node where.Node) predicate.Benchmark {
+	switch n := node.(type) {
+	case *where.And:
+		return benchmark.And(wherePredicates(n.Nodes)...)
+	case *where.Or:
+		return benchmark.Or(wherePredicates(n.Nodes)...)
+	default:
+		return conditionPredicate(node.(*where.Condition))
+	}
+}
+
+func wherePredicates(nodes []where.Node) []predicate.Benchmark {
+	preds := make([]predicate.Benchmark, 0, len(nodes))
+	for _, node := range nodes {
+		preds = append(preds, wherePredicate(node))
+	}
+
+	return preds
+}
+
+func conditionPredicate(cond *where.Condition) predicate.Benchmark {
+	return fieldPredicates[cond.Field.Entity][cond.Field.Column](cond.Op, cond.Value)
+}
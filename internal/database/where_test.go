@@ -0,0 +1,30 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/internal/where"
+)
+
+func TestFieldPredicatesCoverAllFields(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range where.FieldNames() {
+		field, _ := where.LookupField(name)
+		assert.Contains(t, fieldPredicates[field.Entity], field.Column, name)
+	}
+}
+
+func TestToInteger(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 42, toInteger[int](int64(42)))
+	assert.Equal(t, -1, toInteger[int](int64(-1)))
+	assert.Equal(t, uint8(2), toInteger[uint8](int64(2)))
+	assert.Equal(t, uint8(255), toInteger[uint8](int64(300)))
+	assert.Equal(t, uint32(0), toInteger[uint32](int64(-5)))
+	assert.Equal(t, uint64(0), toInteger[uint64](int64(-5)))
+	assert.Equal(t, uint64(1<<40), toInteger[uint64](int64(1<<40)))
+}
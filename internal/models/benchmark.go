@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/nikoksr/dbench/ent"
 )
 
@@ -12,30 +14,106 @@ const (
 	ModeThorough BenchmarkMode = "thorough" // ModeThorough is a thorough benchmark mode
 )
 
+const (
+	ProcessRoleServer = "server" // ProcessRoleServer is the role of the PostgreSQL server process tree
+	ProcessRoleClient = "client" // ProcessRoleClient is the role of the pgbench process tree
+)
+
 type (
 	// Benchmark represents a benchmark.
 	Benchmark = ent.Benchmark
 
+	// BenchmarkGroup represents a group of benchmarks that were run together.
+	BenchmarkGroup = ent.BenchmarkGroup
+
 	// BenchmarkResult represents the result of a benchmark run.
 	BenchmarkResult = ent.BenchmarkResult
 
+	// BenchmarkOutput represents the raw, compressed output of a benchmark run.
+	BenchmarkOutput = ent.BenchmarkOutput
+
 	// SystemConfig represents a system config.
 	SystemConfig = ent.SystemConfig
 
 	// SystemMetric represents a system metric.
 	SystemMetric = ent.SystemMetric
 
+	// SystemMetricSample represents a single, persisted sample of the system metrics.
+	SystemMetricSample = ent.SystemMetricSample
+
+	// WaitEvent represents the number of times the benchmark's backends were observed in a wait event.
+	WaitEvent = ent.WaitEvent
+
+	// ProcessMetric represents the resource metrics of a process tree.
+	ProcessMetric = ent.ProcessMetric
+
+	// DiskMetric represents the IO metrics of a block device.
+	DiskMetric = ent.DiskMetric
+
+	// NetworkMetric represents the IO metrics of a network interface.
+	NetworkMetric = ent.NetworkMetric
+
+	// Tag represents a key-value label of a benchmark.
+	Tag = ent.Tag
+
 	// SystemSample represents a system sample.
 	SystemSample struct {
-		CPULoad    float64
-		MemoryLoad float64
+		SampledAt   time.Time
+		CPULoad     float64 // Relative to the CPU quota of the cgroup, if any
+		MemoryLoad  float64 // Relative to the memory limit of the cgroup, if any
+		LoadAverage float64 // 1-minute load average
+		IOWait      float64 // Share of CPU time spent waiting for IO in percent
+		SwapLoad    float64
+		Disks       []DiskSample
+		Networks    []NetworkSample
+	}
+
+	// DiskSample represents the IO rates of a block device since the previous sample.
+	DiskSample struct {
+		Device              string
+		ReadBytesPerSecond  float64
+		WriteBytesPerSecond float64
+		ReadIOPS            float64
+		WriteIOPS           float64
+		Utilization         float64 // Share of time the device was busy in percent
+	}
+
+	// NetworkSample represents the IO rates of a network interface since the previous sample.
+	NetworkSample struct {
+		Interface                string
+		BytesReceivedPerSecond   float64
+		BytesSentPerSecond       float64
+		PacketsReceivedPerSecond float64
+		PacketsSentPerSecond     float64
+	}
+
+	// ProcessSample represents a sample of a process tree. Counters hold the difference to the previous sample.
+	ProcessSample struct {
+		Role                   string
+		ProcessCount           int
+		CPULoad                float64 // In percent of a single core
+		RSS                    uint64
+		IOAvailable            bool
+		ReadBytes              uint64
+		WriteBytes             uint64
+		VoluntaryCtxSwitches   uint64
+		InvoluntaryCtxSwitches uint64
+	}
+
+	// WaitEventSample represents the number of backends in a wait event at the time of sampling.
+	WaitEventSample struct {
+		Type  string
+		Event string
+		Count int
 	}
 
 	// BenchmarkCSV is the CSV-exportable type for models.Benchmark.
 	BenchmarkCSV struct {
 		ID                    string `csv:"ID"`
 		GroupID               string `csv:"GroupID"`
+		GroupName             string `csv:"GroupName"`
 		Comment               string `csv:"Comment"`
+		Tags                  string `csv:"Tags"`
 		Version               string `csv:"Version"`
 		Command               string `csv:"Command"`
 		TransactionType       string `csv:"TransactionType"`
@@ -55,6 +133,17 @@ type (
 		RAMUsable             string `csv:"RAMUsable"`
 		DiskCount             string `csv:"DiskCount"`
 		DiskSpaceTotal        string `csv:"DiskSpaceTotal"`
+		KernelVersion         string `csv:"KernelVersion"`
+		CPUGovernor           string `csv:"CPUGovernor"`
+		NumaNodes             string `csv:"NumaNodes"`
+		TransparentHugePages  string `csv:"TransparentHugePages"`
+		DataDirFilesystem     string `csv:"DataDirFilesystem"`
+		DataDirMountOptions   string `csv:"DataDirMountOptions"`
+		DiskType              string `csv:"DiskType"`
+		CgroupVersion         string `csv:"CgroupVersion"`
+		CgroupCPUQuota        string `csv:"CgroupCPUQuota"`
+		CgroupMemoryLimit     string `csv:"CgroupMemoryLimit"`
+		CgroupIOLimits        string `csv:"CgroupIOLimits"`
 		Transactions          string `csv:"Transactions"`
 		TransactionsPerSecond string `csv:"TransactionsPerSecond"`
 		FailedTransactions    string `csv:"FailedTransactions"`
@@ -77,6 +166,140 @@ type (
 		Memory90thLoad        string `csv:"Memory90thLoad"`
 		Memory95thLoad        string `csv:"Memory95thLoad"`
 		Memory99thLoad        string `csv:"Memory99thLoad"`
+		LoadAverage           string `csv:"LoadAverage"`
+		LoadAverage95th       string `csv:"LoadAverage95th"`
+		IOWaitAverageLoad     string `csv:"IOWaitAverageLoad"`
+		IOWait95thLoad        string `csv:"IOWait95thLoad"`
+		SwapAverageLoad       string `csv:"SwapAverageLoad"`
+		DiskReadThroughput    string `csv:"DiskReadThroughput"`
+		DiskWriteThroughput   string `csv:"DiskWriteThroughput"`
+		DiskReadIOPS          string `csv:"DiskReadIOPS"`
+		DiskWriteIOPS         string `csv:"DiskWriteIOPS"`
+		DiskMaxUtilization    string `csv:"DiskMaxUtilization"`
+		NetworkReceived       string `csv:"NetworkReceived"`
+		NetworkSent           string `csv:"NetworkSent"`
+		ServerCPUAverageLoad  string `csv:"ServerCPUAverageLoad"`
+		ServerCPUMaxLoad      string `csv:"ServerCPUMaxLoad"`
+		ServerMemoryRSSMax    string `csv:"ServerMemoryRSSMax"`
+		ClientCPUAverageLoad  string `csv:"ClientCPUAverageLoad"`
+		ClientCPUMaxLoad      string `csv:"ClientCPUMaxLoad"`
+		ClientMemoryRSSMax    string `csv:"ClientMemoryRSSMax"`
+		WaitEventCPU          string `csv:"WaitEventCPU"`
+		WaitEventLock         string `csv:"WaitEventLock"`
+		WaitEventLWLock       string `csv:"WaitEventLWLock"`
+		WaitEventIO           string `csv:"WaitEventIO"`
+		WaitEventIPC          string `csv:"WaitEventIPC"`
+		WaitEventClient       string `csv:"WaitEventClient"`
+		WaitEventOther        string `csv:"WaitEventOther"`
 		RecordedAt            string `csv:"RecordedAt"`
 	}
+
+	// SystemMetricSampleCSV is the CSV-exportable type for models.SystemMetricSample. Elapsed is given in seconds and
+	// AverageLatency in milliseconds, so that they can be plotted directly.
+	SystemMetricSampleCSV struct {
+		BenchmarkID           string `csv:"BenchmarkID"`
+		Clients               string `csv:"Clients"`
+		SampledAt             string `csv:"SampledAt"`
+		Elapsed               string `csv:"Elapsed"`
+		CPULoad               string `csv:"CPULoad"`
+		MemoryLoad            string `csv:"MemoryLoad"`
+		IOWaitLoad            string `csv:"IOWaitLoad"`
+		SwapLoad              string `csv:"SwapLoad"`
+		LoadAverage           string `csv:"LoadAverage"`
+		DiskReadThroughput    string `csv:"DiskReadThroughput"`
+		DiskWriteThroughput   string `csv:"DiskWriteThroughput"`
+		NetworkReceived       string `csv:"NetworkReceived"`
+		NetworkSent           string `csv:"NetworkSent"`
+		TransactionsPerSecond string `csv:"TransactionsPerSecond"`
+		AverageLatency        string `csv:"AverageLatency"`
+	}
+
+	// BenchmarkParquet is the Parquet-exportable type for models.Benchmark. It holds the same columns as BenchmarkCSV,
+	// but typed, so that analytics tools don't have to parse them. Missing values are null and durations are given in
+	// milliseconds.
+	BenchmarkParquet struct {
+		ID                    string    `parquet:"id"`
+		GroupID               string    `parquet:"group_id"`
+		GroupName             *string   `parquet:"group_name"`
+		Comment               *string   `parquet:"comment"`
+		Tags                  *string   `parquet:"tags"`
+		Version               string    `parquet:"version"`
+		Command               string    `parquet:"command"`
+		TransactionType       string    `parquet:"transaction_type"`
+		QueryMode             string    `parquet:"query_mode"`
+		ScalingFactor         float64   `parquet:"scaling_factor"`
+		Clients               int64     `parquet:"clients"`
+		Threads               int64     `parquet:"threads"`
+		MachineID             *string   `parquet:"machine_id"`
+		OsName                *string   `parquet:"os_name"`
+		OsArch                *string   `parquet:"os_arch"`
+		CPUVendor             *string   `parquet:"cpu_vendor"`
+		CPUModel              *string   `parquet:"cpu_model"`
+		CPUCount              *uint32   `parquet:"cpu_count"`
+		CPUCores              *uint32   `parquet:"cpu_cores"`
+		CPUThreads            *uint32   `parquet:"cpu_threads"`
+		RAMPhysical           *uint64   `parquet:"ram_physical"`
+		RAMUsable             *uint64   `parquet:"ram_usable"`
+		DiskCount             *uint32   `parquet:"disk_count"`
+		DiskSpaceTotal        *uint64   `parquet:"disk_space_total"`
+		KernelVersion         *string   `parquet:"kernel_version"`
+		CPUGovernor           *string   `parquet:"cpu_governor"`
+		NumaNodes             *uint32   `parquet:"numa_nodes"`
+		TransparentHugePages  *string   `parquet:"transparent_huge_pages"`
+		DataDirFilesystem     *string   `parquet:"data_dir_filesystem"`
+		DataDirMountOptions   *string   `parquet:"data_dir_mount_options"`
+		DiskType              *string   `parquet:"disk_type"`
+		CgroupVersion         *uint8    `parquet:"cgroup_version"`
+		CgroupCPUQuota        *float64  `parquet:"cgroup_cpu_quota"`
+		CgroupMemoryLimit     *uint64   `parquet:"cgroup_memory_limit"`
+		CgroupIOLimits        *string   `parquet:"cgroup_io_limits"`
+		Transactions          *int64    `parquet:"transactions"`
+		TransactionsPerSecond *float64  `parquet:"transactions_per_second"`
+		FailedTransactions    *int64    `parquet:"failed_transactions"`
+		AverageLatency        *float64  `parquet:"average_latency_ms"`
+		ConnectionTime        *float64  `parquet:"connection_time_ms"`
+		TotalRuntime          *float64  `parquet:"total_runtime_ms"`
+		CPUMinLoad            *float64  `parquet:"cpu_min_load"`
+		CPUMaxLoad            *float64  `parquet:"cpu_max_load"`
+		CPUAverageLoad        *float64  `parquet:"cpu_average_load"`
+		CPU50thLoad           *float64  `parquet:"cpu_50th_load"`
+		CPU75thLoad           *float64  `parquet:"cpu_75th_load"`
+		CPU90thLoad           *float64  `parquet:"cpu_90th_load"`
+		CPU95thLoad           *float64  `parquet:"cpu_95th_load"`
+		CPU99thLoad           *float64  `parquet:"cpu_99th_load"`
+		MemoryMinLoad         *float64  `parquet:"memory_min_load"`
+		MemoryMaxLoad         *float64  `parquet:"memory_max_load"`
+		MemoryAverageLoad     *float64  `parquet:"memory_average_load"`
+		Memory50thLoad        *float64  `parquet:"memory_50th_load"`
+		Memory75thLoad        *float64  `parquet:"memory_75th_load"`
+		Memory90thLoad        *float64  `parquet:"memory_90th_load"`
+		Memory95thLoad        *float64  `parquet:"memory_95th_load"`
+		Memory99thLoad        *float64  `parquet:"memory_99th_load"`
+		LoadAverage           *float64  `parquet:"load_average"`
+		LoadAverage95th       *float64  `parquet:"load_average_95th"`
+		IOWaitAverageLoad     *float64  `parquet:"iowait_average_load"`
+		IOWait95thLoad        *float64  `parquet:"iowait_95th_load"`
+		SwapAverageLoad       *float64  `parquet:"swap_average_load"`
+		DiskReadThroughput    *float64  `parquet:"disk_read_throughput"`
+		DiskWriteThroughput   *float64  `parquet:"disk_write_throughput"`
+		DiskReadIOPS          *float64  `parquet:"disk_read_iops"`
+		DiskWriteIOPS         *float64  `parquet:"disk_write_iops"`
+		DiskMaxUtilization    *float64  `parquet:"disk_max_utilization"`
+		NetworkReceived       *float64  `parquet:"network_received"`
+		NetworkSent           *float64  `parquet:"network_sent"`
+		ServerCPUAverageLoad  *float64  `parquet:"server_cpu_average_load"`
+		ServerCPUMaxLoad      *float64  `parquet:"server_cpu_max_load"`
+		ServerMemoryRSSMax    *uint64   `parquet:"server_memory_rss_max"`
+		ClientCPUAverageLoad  *float64  `parquet:"client_cpu_average_load"`
+		ClientCPUMaxLoad      *float64  `parquet:"client_cpu_max_load"`
+		ClientMemoryRSSMax    *uint64   `parquet:"client_memory_rss_max"`
+		WaitEventCPU          *float64  `parquet:"wait_event_cpu"`
+		WaitEventLock         *float64  `parquet:"wait_event_lock"`
+		WaitEventLWLock       *float64  `parquet:"wait_event_lwlock"`
+		WaitEventIO           *float64  `parquet:"wait_event_io"`
+		WaitEventIPC          *float64  `parquet:"wait_event_ipc"`
+		WaitEventClient       *float64  `parquet:"wait_event_client"`
+		WaitEventOther        *float64  `parquet:"wait_event_other"`
+		RecordedAt            time.Time `parquet:"recorded_at"`
+	}
 )
@@ -1,6 +1,13 @@
 package models
 
-import "runtime"
+import (
+	"runtime"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/ent/schema/snapshot"
+)
 
 // BenchmarkConfig holds the configuration for benchmarking
 type BenchmarkConfig struct {
@@ -20,6 +27,13 @@ type BenchmarkConfig struct {
 	NumThreads int           // NumThreads is the number of threads to use
 	NumClients int           // NumClients is the number of clients to use
 	Comment    string        // Comment is a comment to add to the benchmark
+	Tags       []*Tag        // Tags are the key-value tags to label the benchmark with
+
+	// Sampling options
+	Collectors        []string      // Collectors holds the names of the metric collectors to enable
+	WaitEventInterval time.Duration // WaitEventInterval is the polling interval for wait events; zero uses the sampling rate of the mode
+	PGDataDir         string        // PGDataDir is the data directory of the PostgreSQL server, used to find its PID
+	PGProcessName     string        // PGProcessName is the process name of the PostgreSQL server
 }
 
 func (c *BenchmarkConfig) Sanitize() {
@@ -68,4 +82,46 @@ func (c *BenchmarkConfig) Sanitize() {
 	if c.NumClients < 1 {
 		c.NumClients = 1
 	}
+
+	// Sampling options
+	if c.WaitEventInterval < 0 {
+		c.WaitEventInterval = 0
+	}
+	if c.WaitEventInterval > 0 && !slices.Contains(c.Collectors, "postgres") {
+		// Wait events are sampled by the postgres collector; asking for an interval implies enabling it.
+		c.Collectors = append(c.Collectors, "postgres")
+	}
+
+	// Remove duplicates, so that no collector runs twice
+	collectors := make([]string, 0, len(c.Collectors))
+	for _, name := range c.Collectors {
+		if !slices.Contains(collectors, name) {
+			collectors = append(collectors, name)
+		}
+	}
+	c.Collectors = collectors
+
+	if c.PGProcessName == "" {
+		c.PGProcessName = "postgres"
+	}
+}
+
+// Snapshot returns a snapshot of the config for a run with the given client counts. The password is left out.
+func (c *BenchmarkConfig) Snapshot(clients []int, collectSystemConfig bool) *snapshot.RunConfig {
+	config := &snapshot.RunConfig{
+		Host:                c.Host,
+		Port:                c.Port,
+		DBName:              c.DBName,
+		Username:            c.Username,
+		Mode:                c.Mode,
+		Clients:             slices.Clone(clients),
+		Threads:             c.NumThreads,
+		Collectors:          slices.Clone(c.Collectors),
+		CollectSystemConfig: collectSystemConfig,
+	}
+	if c.WaitEventInterval > 0 {
+		config.WaitEventInterval = c.WaitEventInterval.String()
+	}
+
+	return config
 }
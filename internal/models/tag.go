@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// ParseTag parses a tag of the form "key=value". Surrounding whitespace of the key and value is ignored.
+func ParseTag(s string) (*Tag, error) {
+	key, value, found := strings.Cut(s, "=")
+	key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+	if !found || key == "" || value == "" {
+		return nil, fmt.Errorf("invalid tag %q, expected the form key=value", s)
+	}
+
+	return &Tag{Key: key, Value: value}, nil
+}
+
+// ParseTags parses the given tags of the form "key=value". A benchmark holds at most one value per key, so a key must
+// not be given twice.
+func ParseTags(tags []string) ([]*Tag, error) {
+	parsed := make([]*Tag, 0, len(tags))
+	for _, s := range tags {
+		tag, err := ParseTag(s)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.ContainsFunc(parsed, func(t *Tag) bool { return t.Key == tag.Key }) {
+			return nil, fmt.Errorf("tag key %q is given more than once", tag.Key)
+		}
+
+		parsed = append(parsed, tag)
+	}
+
+	return parsed, nil
+}
+
+// ParseTagFilters parses the given tag filters. A filter of the form "key=value" matches a tag exactly, a filter that
+// only consists of a key matches any value of that key.
+func ParseTagFilters(filters []string) ([]*Tag, error) {
+	parsed := make([]*Tag, 0, len(filters))
+	for _, s := range filters {
+		if !strings.Contains(s, "=") {
+			key := strings.TrimSpace(s)
+			if key == "" {
+				return nil, fmt.Errorf("invalid tag filter %q, expected the form key or key=value", s)
+			}
+
+			parsed = append(parsed, &Tag{Key: key})
+			continue
+		}
+
+		tag, err := ParseTag(s)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, tag)
+	}
+
+	return parsed, nil
+}
+
+// FormatTags returns the given tags as a comma separated list of key=value pairs, sorted by key.
+func FormatTags(tags []*Tag) string {
+	pairs := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		pairs = append(pairs, tag.Key+"="+tag.Value)
+	}
+
+	slices.Sort(pairs)
+
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,85 @@
+// Package pgstat samples PostgreSQL's statistics views while a benchmark is running.
+package pgstat
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	_ "github.com/lib/pq" // PostgreSQL driver
+
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// waitEventsQuery selects the wait events of all pgbench backends that are connected to the current database. pgbench
+// identifies itself with the application name 'pgbench'. Idle backends are skipped, they're waiting for pgbench to send
+// the next transaction. Active backends that are not waiting have no wait event; we record them as 'CPU'.
+const waitEventsQuery = `
+SELECT coalesce(wait_event_type, 'CPU'), coalesce(wait_event, 'CPU'), count(*)
+FROM pg_stat_activity
+WHERE application_name = 'pgbench'
+  AND datname = current_database()
+  AND pid <> pg_backend_pid()
+  AND state <> 'idle'
+  AND (state = 'active' OR wait_event IS NOT NULL)
+GROUP BY 1, 2`
+
+// buildDSN builds a connection string for the given benchmark config.
+func buildDSN(config *models.BenchmarkConfig) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(config.Username, config.Password),
+		Host:   net.JoinHostPort(config.Host, config.Port),
+		Path:   config.DBName,
+	}
+
+	// lib/pq defaults to sslmode=require, while pgbench (libpq) defaults to prefer. To not fail against servers that
+	// pgbench can connect to, we disable SSL unless the user explicitly configured it.
+	if os.Getenv("PGSSLMODE") == "" {
+		dsn.RawQuery = "sslmode=disable"
+	}
+
+	return dsn.String()
+}
+
+// Open opens a connection to the database that is being benchmarked.
+func Open(ctx context.Context, config *models.BenchmarkConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", buildDSN(config))
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+
+	// A single connection is all we need for sampling
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+// WaitEvents returns the wait events of the pgbench backends at this moment.
+func WaitEvents(ctx context.Context, db *sql.DB) ([]models.WaitEventSample, error) {
+	rows, err := db.QueryContext(ctx, waitEventsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []models.WaitEventSample
+	for rows.Next() {
+		var sample models.WaitEventSample
+		if err := rows.Scan(&sample.Type, &sample.Event, &sample.Count); err != nil {
+			return nil, err
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return samples, rows.Err()
+}
@@ -8,6 +8,17 @@ var ScriptTemplates = map[string]string{
 	"memory_load_distribution":                    memoryLoadDistribution,
 	"performance_efficiency":                      performanceEfficiency,
 	"transactions_latency_conn_time_over_clients": transactionsLatencyConnTimeOverClients,
+	"wait_event_mix_over_clients":                 waitEventMixOverClients,
+	"server_and_client_cpu_load_over_clients":     serverAndClientCPULoadOverClients,
+	"disk_io_over_clients":                        diskIOOverClients,
+	"network_io_over_clients":                     networkIOOverClients,
+	"load_iowait_swap_over_clients":               loadIOWaitSwapOverClients,
+}
+
+// TimeSeriesScriptTemplates are plotted per benchmark against its system metric samples.
+var TimeSeriesScriptTemplates = map[string]string{
+	"throughput_and_cpu_load_over_time": throughputAndCPULoadOverTime,
+	"system_load_over_time":             systemLoadOverTime,
 }
 
 const (
@@ -138,5 +149,139 @@ set grid
 plot '{{ .DataPath }}' using "Clients":"TransactionsPerSecond" with linespoints title "TPS", \
 	 '{{ .DataPath }}' using "Clients":"ConnectionTime" with linespoints title "Connection Time" axes x1y2, \
 	 '{{ .DataPath }}' using "Clients":"AverageLatency" with linespoints title "Latency" axes x1y2
+`
+
+	waitEventMixOverClients = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "Wait Event Mix over Clients"
+set key outside right top
+set tmargin 5
+set xlabel "Number of Clients"
+set ylabel "Share of Sampled Backends (%)"
+set yrange [0:100]
+set style data histograms
+set style histogram rowstacked
+set style fill solid 0.8 border -1
+set boxwidth 0.75
+set grid ytics
+
+plot '{{ .DataPath }}' using "WaitEventCPU":xtic(stringcolumn("Clients")) title "CPU", \
+	 '{{ .DataPath }}' using "WaitEventLock" title "Lock", \
+	 '{{ .DataPath }}' using "WaitEventLWLock" title "LWLock", \
+	 '{{ .DataPath }}' using "WaitEventIO" title "IO", \
+	 '{{ .DataPath }}' using "WaitEventIPC" title "IPC", \
+	 '{{ .DataPath }}' using "WaitEventClient" title "Client", \
+	 '{{ .DataPath }}' using "WaitEventOther" title "Other"
+`
+
+	serverAndClientCPULoadOverClients = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "PostgreSQL and pgbench CPU Load over Clients"
+set key bottom right
+set tmargin 5
+set xlabel "Number of Clients"
+set ylabel "CPU Load (% of one core)"
+set grid
+plot '{{ .DataPath }}' using "Clients":"ServerCPUAverageLoad" title "PostgreSQL Average Load" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":"ServerCPUMaxLoad" title "PostgreSQL Max Load" with lines dashtype 2, \
+	 '{{ .DataPath }}' using "Clients":"ClientCPUAverageLoad" title "pgbench Average Load" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":"ClientCPUMaxLoad" title "pgbench Max Load" with lines dashtype 2
+`
+
+	diskIOOverClients = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "Disk IO over Clients"
+set key bottom right
+set tmargin 5
+set xlabel "Number of Clients"
+set ylabel "Throughput (MiB/s)"
+set y2label "IOPS"
+set ytics nomirror
+set y2tics
+set grid
+plot '{{ .DataPath }}' using "Clients":(column("DiskReadThroughput")/1048576) title "Read Throughput" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":(column("DiskWriteThroughput")/1048576) title "Write Throughput" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":"DiskReadIOPS" title "Read IOPS" axes x1y2 with lines dashtype 2, \
+	 '{{ .DataPath }}' using "Clients":"DiskWriteIOPS" title "Write IOPS" axes x1y2 with lines dashtype 2
+`
+
+	networkIOOverClients = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "Network IO over Clients"
+set key bottom right
+set tmargin 5
+set xlabel "Number of Clients"
+set ylabel "Throughput (MiB/s)"
+set grid
+plot '{{ .DataPath }}' using "Clients":(column("NetworkReceived")/1048576) title "Received" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":(column("NetworkSent")/1048576) title "Sent" with linespoints
+`
+
+	loadIOWaitSwapOverClients = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "Load Average, IO Wait and Swap over Clients"
+set key bottom right
+set tmargin 5
+set xlabel "Number of Clients"
+set ylabel "Load Average (1 min)"
+set y2label "IO Wait, Swap and Disk Utilization (%)"
+set ytics nomirror
+set y2tics
+set grid
+plot '{{ .DataPath }}' using "Clients":"LoadAverage" title "Load Average" with linespoints, \
+	 '{{ .DataPath }}' using "Clients":"IOWaitAverageLoad" title "IO Wait" axes x1y2 with lines, \
+	 '{{ .DataPath }}' using "Clients":"SwapAverageLoad" title "Swap" axes x1y2 with lines, \
+	 '{{ .DataPath }}' using "Clients":"DiskMaxUtilization" title "Disk Utilization (busiest device)" axes x1y2 with lines
+`
+
+	throughputAndCPULoadOverTime = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,600 enhanced font 'Verdana,10'
+set title "Throughput and CPU Load over Time\n{{ .Title }}"
+set key bottom right
+set tmargin 5
+set xlabel "Elapsed Time (s)"
+set ylabel "Transactions Per Second"
+set y2label "CPU and IO Wait Load (%)"
+set ytics nomirror
+set y2tics
+set y2range [0:100]
+set grid
+plot '{{ .DataPath }}' using "Elapsed":"TransactionsPerSecond" title "Transactions Per Second" with linespoints, \
+	 '{{ .DataPath }}' using "Elapsed":"CPULoad" title "CPU Load" axes x1y2 with lines, \
+	 '{{ .DataPath }}' using "Elapsed":"IOWaitLoad" title "IO Wait" axes x1y2 with lines
+`
+
+	systemLoadOverTime = `set datafile separator ","
+set output '{{ .OutputPath }}'
+set terminal pngcairo size 800,800 enhanced font 'Verdana,10'
+set multiplot layout 3, 1 title "System Load over Time\n{{ .Title }}"
+
+set xlabel "Elapsed Time (s)"
+set grid
+
+set ylabel "Load (%)"
+set key bottom right
+plot '{{ .DataPath }}' using "Elapsed":"CPULoad" title "CPU" with lines, \
+	 '{{ .DataPath }}' using "Elapsed":"MemoryLoad" title "Memory" with lines, \
+	 '{{ .DataPath }}' using "Elapsed":"IOWaitLoad" title "IO Wait" with lines, \
+	 '{{ .DataPath }}' using "Elapsed":"SwapLoad" title "Swap" with lines
+
+set ylabel "Disk IO (MiB/s)"
+set key bottom right
+plot '{{ .DataPath }}' using "Elapsed":(column("DiskReadThroughput")/1048576) title "Read" with lines, \
+	 '{{ .DataPath }}' using "Elapsed":(column("DiskWriteThroughput")/1048576) title "Write" with lines
+
+set ylabel "Network IO (MiB/s)"
+set key bottom right
+plot '{{ .DataPath }}' using "Elapsed":(column("NetworkReceived")/1048576) title "Received" with lines, \
+	 '{{ .DataPath }}' using "Elapsed":(column("NetworkSent")/1048576) title "Sent" with lines
+
+unset multiplot
 `
 )
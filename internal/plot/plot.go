@@ -13,6 +13,7 @@ import (
 type plotData struct {
 	DataPath   string
 	OutputPath string
+	Title      string
 }
 
 func executeScriptTemplate(name, text string, data plotData) (string, error) {
@@ -30,14 +31,21 @@ func executeScriptTemplate(name, text string, data plotData) (string, error) {
 	return scriptBuilder.String(), nil
 }
 
-func Plot(ctx context.Context, dataFile, outputDir string) error {
-	for name, template := range gnuplot2.ScriptTemplates {
-		// outputPath is the template output dir + the template name + .png
-		outputPath := filepath.Join(outputDir, name+".png")
+// plotTemplates executes the given script templates against the data file. The plots are named after their template,
+// followed by the optional suffix.
+func plotTemplates(ctx context.Context, templates map[string]string, dataFile, outputDir, suffix, title string) error {
+	for name, template := range templates {
+		// outputPath is the template output dir + the template name + optional suffix + .png
+		fileName := name
+		if suffix != "" {
+			fileName += "_" + suffix
+		}
+		outputPath := filepath.Join(outputDir, fileName+".png")
 
 		data := plotData{
 			DataPath:   dataFile,
 			OutputPath: outputPath,
+			Title:      title,
 		}
 
 		// Execute the template and get the script
@@ -53,3 +61,15 @@ func Plot(ctx context.Context, dataFile, outputDir string) error {
 
 	return nil
 }
+
+// Plot generates the plots of a benchmark-group. The data file is expected to hold one row per benchmark.
+func Plot(ctx context.Context, dataFile, outputDir string) error {
+	return plotTemplates(ctx, gnuplot2.ScriptTemplates, dataFile, outputDir, "", "")
+}
+
+// PlotTimeSeries generates the time series plots of a single benchmark. The data file is expected to hold one row per
+// system metric sample. The file names are suffixed with the given suffix, so that the plots of multiple benchmarks
+// don't overwrite each other.
+func PlotTimeSeries(ctx context.Context, dataFile, outputDir, suffix, title string) error {
+	return plotTemplates(ctx, gnuplot2.TimeSeriesScriptTemplates, dataFile, outputDir, suffix, title)
+}
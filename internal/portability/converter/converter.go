@@ -2,12 +2,52 @@ package converter
 
 import (
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/nikoksr/dbench/internal/models"
 	"github.com/nikoksr/dbench/internal/ui/text"
 )
 
-// BenchmarkToCSV converts a benchmark to a CSV-exportable type.
+// waitEventShares calculates the share in percent of each wait event type in the given wait events. Wait event types
+// that are not explicitly listed are summed up under "Other".
+func waitEventShares(waitEvents []*models.WaitEvent) map[string]float64 {
+	shares := make(map[string]float64)
+
+	total := 0
+	for _, waitEvent := range waitEvents {
+		total += waitEvent.Count
+	}
+	if total == 0 {
+		return shares
+	}
+
+	for _, waitEvent := range waitEvents {
+		typ := waitEvent.WaitEventType
+		switch typ {
+		case "CPU", "Lock", "LWLock", "IO", "IPC", "Client":
+		default:
+			typ = "Other"
+		}
+
+		shares[typ] += float64(waitEvent.Count) / float64(total) * 100
+	}
+
+	return shares
+}
+
+// optionalStringToCSV formats an optional text value. Values that would be taken for the placeholder of missing values,
+// or for an escaped value, are escaped, so that the import can tell them apart.
+func optionalStringToCSV(s *string) string {
+	if s != nil && (*s == naValue || strings.HasPrefix(*s, escapeChar)) {
+		return escapeChar + *s
+	}
+
+	return text.ValueOrNA(s)
+}
+
+// BenchmarkToCSV converts a benchmark to a CSV-exportable type. Its config and result are exported without loss, so
+// that the benchmark is recognized again when the CSV is imported.
 func BenchmarkToCSV(b *models.Benchmark) *models.BenchmarkCSV {
 	// Avoid nil pointer dereference. Also makes the code below more readable.
 	system := new(models.SystemConfig)
@@ -25,13 +65,50 @@ func BenchmarkToCSV(b *models.Benchmark) *models.BenchmarkCSV {
 		result = b.Edges.Result
 	}
 
+	// Process metrics are optional and keyed by their role
+	serverMetric := new(models.ProcessMetric)
+	clientMetric := new(models.ProcessMetric)
+	for _, processMetric := range b.Edges.ProcessMetrics {
+		switch string(processMetric.Role) {
+		case models.ProcessRoleServer:
+			serverMetric = processMetric
+		case models.ProcessRoleClient:
+			clientMetric = processMetric
+		}
+	}
+
+	// Disk and network rates are summed up over all devices and interfaces. For utilization, the busiest device counts.
+	var diskRead, diskWrite, diskReadIOPS, diskWriteIOPS, diskUtilization float64
+	for _, disk := range b.Edges.DiskMetrics {
+		diskRead += disk.ReadBytesPerSecondAverage
+		diskWrite += disk.WriteBytesPerSecondAverage
+		diskReadIOPS += disk.ReadIopsAverage
+		diskWriteIOPS += disk.WriteIopsAverage
+		diskUtilization = max(diskUtilization, disk.UtilizationAverage)
+	}
+
+	var networkReceived, networkSent float64
+	for _, network := range b.Edges.NetworkMetrics {
+		networkReceived += network.BytesReceivedPerSecondAverage
+		networkSent += network.BytesSentPerSecondAverage
+	}
+
+	waitEvents := waitEventShares(b.Edges.WaitEvents)
+
+	var groupName *string
+	if b.Edges.Group != nil {
+		groupName = b.Edges.Group.Name
+	}
+
 	// Convert to CSV-exportable type
 	return &models.BenchmarkCSV{
 		// Config
 
 		ID:              b.ID.String(),
 		GroupID:         b.GroupID.String(),
-		Comment:         text.ValueOrNA(b.Comment),
+		GroupName:       optionalStringToCSV(groupName),
+		Comment:         optionalStringToCSV(b.Comment),
+		Tags:            text.StringOrNA(models.FormatTags(b.Edges.Tags)),
 		Version:         b.Version,
 		Command:         b.Command,
 		TransactionType: b.TransactionType,
@@ -42,11 +119,11 @@ func BenchmarkToCSV(b *models.Benchmark) *models.BenchmarkCSV {
 
 		// System config
 
-		MachineID:      text.ValueOrNA(system.MachineID),
-		OsName:         text.ValueOrNA(system.OsName),
-		OsArch:         text.ValueOrNA(system.OsArch),
-		CPUVendor:      text.ValueOrNA(system.CPUVendor),
-		CPUModel:       text.ValueOrNA(system.CPUModel),
+		MachineID:      optionalStringToCSV(system.MachineID),
+		OsName:         optionalStringToCSV(system.OsName),
+		OsArch:         optionalStringToCSV(system.OsArch),
+		CPUVendor:      optionalStringToCSV(system.CPUVendor),
+		CPUModel:       optionalStringToCSV(system.CPUModel),
 		CPUCount:       text.ValueOrNA(system.CPUCount),
 		CPUCores:       text.ValueOrNA(system.CPUCores),
 		CPUThreads:     text.ValueOrNA(system.CPUThreads),
@@ -55,10 +132,23 @@ func BenchmarkToCSV(b *models.Benchmark) *models.BenchmarkCSV {
 		DiskCount:      text.ValueOrNA(system.DiskCount),
 		DiskSpaceTotal: text.ValueOrNA(system.DiskSpaceTotal),
 
+		KernelVersion:        optionalStringToCSV(system.KernelVersion),
+		CPUGovernor:          optionalStringToCSV(system.CPUGovernor),
+		NumaNodes:            text.ValueOrNA(system.NumaNodes),
+		TransparentHugePages: optionalStringToCSV(system.TransparentHugePages),
+		DataDirFilesystem:    optionalStringToCSV(system.DataDirFilesystem),
+		DataDirMountOptions:  optionalStringToCSV(system.DataDirMountOptions),
+		DiskType:             optionalStringToCSV(system.DiskType),
+
+		CgroupVersion:     text.ValueOrNA(system.CgroupVersion),
+		CgroupCPUQuota:    text.ValueOrNA(system.CgroupCPUQuota),
+		CgroupMemoryLimit: text.ValueOrNA(system.CgroupMemoryLimit),
+		CgroupIOLimits:    optionalStringToCSV(system.CgroupIoLimits),
+
 		// Benchmark result
 
 		Transactions:          strconv.Itoa(result.Transactions),
-		TransactionsPerSecond: strconv.FormatFloat(result.TransactionsPerSecond, 'f', 2, 64),
+		TransactionsPerSecond: strconv.FormatFloat(result.TransactionsPerSecond, 'f', -1, 64),
 		FailedTransactions:    strconv.Itoa(result.FailedTransactions),
 		AverageLatency:        result.AverageLatency.String(),
 		ConnectionTime:        result.ConnectionTime.String(),
@@ -86,9 +176,46 @@ func BenchmarkToCSV(b *models.Benchmark) *models.BenchmarkCSV {
 		Memory95thLoad:    strconv.FormatFloat(systemMetric.Memory95thLoad, 'f', 2, 64),
 		Memory99thLoad:    strconv.FormatFloat(systemMetric.Memory99thLoad, 'f', 2, 64),
 
+		// Load, IO wait and swap
+
+		LoadAverage:       strconv.FormatFloat(systemMetric.LoadAverageAverage, 'f', 2, 64),
+		LoadAverage95th:   strconv.FormatFloat(systemMetric.LoadAverage95th, 'f', 2, 64),
+		IOWaitAverageLoad: strconv.FormatFloat(systemMetric.IowaitAverageLoad, 'f', 2, 64),
+		IOWait95thLoad:    strconv.FormatFloat(systemMetric.Iowait95thLoad, 'f', 2, 64),
+		SwapAverageLoad:   strconv.FormatFloat(systemMetric.SwapAverageLoad, 'f', 2, 64),
+
+		// Disk and network
+
+		DiskReadThroughput:  strconv.FormatFloat(diskRead, 'f', 2, 64),
+		DiskWriteThroughput: strconv.FormatFloat(diskWrite, 'f', 2, 64),
+		DiskReadIOPS:        strconv.FormatFloat(diskReadIOPS, 'f', 2, 64),
+		DiskWriteIOPS:       strconv.FormatFloat(diskWriteIOPS, 'f', 2, 64),
+		DiskMaxUtilization:  strconv.FormatFloat(diskUtilization, 'f', 2, 64),
+		NetworkReceived:     strconv.FormatFloat(networkReceived, 'f', 2, 64),
+		NetworkSent:         strconv.FormatFloat(networkSent, 'f', 2, 64),
+
+		// Processes
+
+		ServerCPUAverageLoad: strconv.FormatFloat(serverMetric.CPUAverageLoad, 'f', 2, 64),
+		ServerCPUMaxLoad:     strconv.FormatFloat(serverMetric.CPUMaxLoad, 'f', 2, 64),
+		ServerMemoryRSSMax:   strconv.FormatUint(serverMetric.MemoryRssMax, 10),
+		ClientCPUAverageLoad: strconv.FormatFloat(clientMetric.CPUAverageLoad, 'f', 2, 64),
+		ClientCPUMaxLoad:     strconv.FormatFloat(clientMetric.CPUMaxLoad, 'f', 2, 64),
+		ClientMemoryRSSMax:   strconv.FormatUint(clientMetric.MemoryRssMax, 10),
+
+		// Wait events
+
+		WaitEventCPU:    strconv.FormatFloat(waitEvents["CPU"], 'f', 2, 64),
+		WaitEventLock:   strconv.FormatFloat(waitEvents["Lock"], 'f', 2, 64),
+		WaitEventLWLock: strconv.FormatFloat(waitEvents["LWLock"], 'f', 2, 64),
+		WaitEventIO:     strconv.FormatFloat(waitEvents["IO"], 'f', 2, 64),
+		WaitEventIPC:    strconv.FormatFloat(waitEvents["IPC"], 'f', 2, 64),
+		WaitEventClient: strconv.FormatFloat(waitEvents["Client"], 'f', 2, 64),
+		WaitEventOther:  strconv.FormatFloat(waitEvents["Other"], 'f', 2, 64),
+
 		// Misc
 
-		RecordedAt: text.PrettyTime(b.RecordedAt),
+		RecordedAt: b.RecordedAt.UTC().Format(time.RFC3339Nano),
 	}
 }
 
@@ -100,3 +227,48 @@ func BenchmarksToCSV(benchmarks []*models.Benchmark) []*models.BenchmarkCSV {
 	}
 	return csvBenchmarks
 }
+
+// formatOptionalFloat formats the given float with two decimals. Missing values are formatted as an empty string, which
+// gnuplot treats as a gap in the series.
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 2, 64)
+}
+
+// SystemMetricSamplesToCSV converts the system metric samples of the given benchmarks to a CSV-exportable type. The
+// samples are flattened into a single series, each row referencing the benchmark it belongs to.
+func SystemMetricSamplesToCSV(benchmarks []*models.Benchmark) []*models.SystemMetricSampleCSV {
+	var csvSamples []*models.SystemMetricSampleCSV
+
+	for _, b := range benchmarks {
+		for _, s := range b.Edges.SystemMetricSamples {
+			var latency *float64
+			if s.AverageLatency != nil {
+				ms := float64(*s.AverageLatency) / float64(time.Millisecond)
+				latency = &ms
+			}
+
+			csvSamples = append(csvSamples, &models.SystemMetricSampleCSV{
+				BenchmarkID:           b.ID.String(),
+				Clients:               strconv.Itoa(b.Clients),
+				SampledAt:             text.PrettyTime(s.SampledAt),
+				Elapsed:               strconv.FormatFloat(time.Duration(s.Elapsed).Seconds(), 'f', 2, 64),
+				CPULoad:               strconv.FormatFloat(s.CPULoad, 'f', 2, 64),
+				MemoryLoad:            strconv.FormatFloat(s.MemoryLoad, 'f', 2, 64),
+				IOWaitLoad:            strconv.FormatFloat(s.IowaitLoad, 'f', 2, 64),
+				SwapLoad:              strconv.FormatFloat(s.SwapLoad, 'f', 2, 64),
+				LoadAverage:           strconv.FormatFloat(s.LoadAverage, 'f', 2, 64),
+				DiskReadThroughput:    strconv.FormatFloat(s.DiskReadBytesPerSecond, 'f', 2, 64),
+				DiskWriteThroughput:   strconv.FormatFloat(s.DiskWriteBytesPerSecond, 'f', 2, 64),
+				NetworkReceived:       strconv.FormatFloat(s.NetworkReceivedBytesPerSecond, 'f', 2, 64),
+				NetworkSent:           strconv.FormatFloat(s.NetworkSentBytesPerSecond, 'f', 2, 64),
+				TransactionsPerSecond: formatOptionalFloat(s.TransactionsPerSecond),
+				AverageLatency:        formatOptionalFloat(latency),
+			})
+		}
+	}
+
+	return csvSamples
+}
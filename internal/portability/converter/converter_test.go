@@ -31,6 +31,10 @@ func TestBenchmarkToCSV(t *testing.T) {
 		Clients:         1,
 		Threads:         1,
 		Edges: ent.BenchmarkEdges{
+			Tags: []*models.Tag{
+				{Key: "pg", Value: "16.1"},
+				{Key: "env", Value: "staging"},
+			},
 			System: &models.SystemConfig{
 				MachineID:      pointer.To("Test MachineID"),
 				OsName:         pointer.To("Test OsName"),
@@ -44,6 +48,8 @@ func TestBenchmarkToCSV(t *testing.T) {
 				RAMUsable:      pointer.To(uint64(1)),
 				DiskCount:      pointer.To(uint32(1)),
 				DiskSpaceTotal: pointer.To(uint64(1)),
+				KernelVersion:  pointer.To("6.1.0"),
+				DiskType:       pointer.To("nvme"),
 			},
 			SystemMetric: &models.SystemMetric{
 				CPUMinLoad:        1.0,
@@ -71,6 +77,22 @@ func TestBenchmarkToCSV(t *testing.T) {
 				ConnectionTime:        duration.Duration(1),
 				TotalRuntime:          duration.Duration(1),
 			},
+			DiskMetrics: []*models.DiskMetric{
+				{Device: "sda", ReadBytesPerSecondAverage: 100, WriteBytesPerSecondAverage: 200, ReadIopsAverage: 1, WriteIopsAverage: 2, UtilizationAverage: 10},
+				{Device: "sdb", ReadBytesPerSecondAverage: 100, WriteBytesPerSecondAverage: 200, ReadIopsAverage: 1, WriteIopsAverage: 2, UtilizationAverage: 20},
+			},
+			NetworkMetrics: []*models.NetworkMetric{
+				{InterfaceName: "lo", BytesReceivedPerSecondAverage: 1000, BytesSentPerSecondAverage: 1000},
+			},
+			ProcessMetrics: []*models.ProcessMetric{
+				{Role: models.ProcessRoleServer, CPUAverageLoad: 150.0, CPUMaxLoad: 200.0, MemoryRssMax: 1024},
+				{Role: models.ProcessRoleClient, CPUAverageLoad: 50.0, CPUMaxLoad: 75.0, MemoryRssMax: 512},
+			},
+			WaitEvents: []*models.WaitEvent{
+				{WaitEventType: "CPU", WaitEvent: "CPU", Count: 2},
+				{WaitEventType: "Lock", WaitEvent: "transactionid", Count: 1},
+				{WaitEventType: "Timeout", WaitEvent: "VacuumDelay", Count: 1},
+			},
 		},
 		RecordedAt: staticTime,
 	}
@@ -80,6 +102,7 @@ func TestBenchmarkToCSV(t *testing.T) {
 	assert.Equal(t, "1", csv.ID)
 	assert.Equal(t, "1", csv.GroupID)
 	assert.Equal(t, "Test Comment", csv.Comment)
+	assert.Equal(t, "env=staging,pg=16.1", csv.Tags)
 	assert.Equal(t, "1.0", csv.Version)
 	assert.Equal(t, "Test Command", csv.Command)
 	assert.Equal(t, "Test TransactionType", csv.TransactionType)
@@ -99,8 +122,11 @@ func TestBenchmarkToCSV(t *testing.T) {
 	assert.Equal(t, "1", csv.RAMUsable)
 	assert.Equal(t, "1", csv.DiskCount)
 	assert.Equal(t, "1", csv.DiskSpaceTotal)
+	assert.Equal(t, "6.1.0", csv.KernelVersion)
+	assert.Equal(t, "nvme", csv.DiskType)
+	assert.Equal(t, "-", csv.CPUGovernor)
 	assert.Equal(t, "1", csv.Transactions)
-	assert.Equal(t, "1.00", csv.TransactionsPerSecond)
+	assert.Equal(t, "1", csv.TransactionsPerSecond)
 	assert.Equal(t, "1", csv.FailedTransactions)
 	assert.Equal(t, "1ns", csv.AverageLatency)
 	assert.Equal(t, "1ns", csv.ConnectionTime)
@@ -121,7 +147,27 @@ func TestBenchmarkToCSV(t *testing.T) {
 	assert.Equal(t, "1.00", csv.Memory90thLoad)
 	assert.Equal(t, "1.00", csv.Memory95thLoad)
 	assert.Equal(t, "1.00", csv.Memory99thLoad)
-	assert.Equal(t, text.PrettyTime(staticTime), csv.RecordedAt)
+	assert.Equal(t, "200.00", csv.DiskReadThroughput)
+	assert.Equal(t, "400.00", csv.DiskWriteThroughput)
+	assert.Equal(t, "2.00", csv.DiskReadIOPS)
+	assert.Equal(t, "4.00", csv.DiskWriteIOPS)
+	assert.Equal(t, "20.00", csv.DiskMaxUtilization)
+	assert.Equal(t, "1000.00", csv.NetworkReceived)
+	assert.Equal(t, "1000.00", csv.NetworkSent)
+	assert.Equal(t, "150.00", csv.ServerCPUAverageLoad)
+	assert.Equal(t, "200.00", csv.ServerCPUMaxLoad)
+	assert.Equal(t, "1024", csv.ServerMemoryRSSMax)
+	assert.Equal(t, "50.00", csv.ClientCPUAverageLoad)
+	assert.Equal(t, "75.00", csv.ClientCPUMaxLoad)
+	assert.Equal(t, "512", csv.ClientMemoryRSSMax)
+	assert.Equal(t, "50.00", csv.WaitEventCPU)
+	assert.Equal(t, "25.00", csv.WaitEventLock)
+	assert.Equal(t, "0.00", csv.WaitEventLWLock)
+	assert.Equal(t, "0.00", csv.WaitEventIO)
+	assert.Equal(t, "0.00", csv.WaitEventIPC)
+	assert.Equal(t, "0.00", csv.WaitEventClient)
+	assert.Equal(t, "25.00", csv.WaitEventOther)
+	assert.Equal(t, "2021-01-01T00:00:00Z", csv.RecordedAt)
 }
 
 func TestBenchmarksToCSV(t *testing.T) {
@@ -168,11 +214,61 @@ func TestBenchmarksToCSV(t *testing.T) {
 	assert.Equal(t, "Test MachineID", csvBenchmarks[0].MachineID)
 	assert.Equal(t, "1.00", csvBenchmarks[0].CPUMinLoad)
 	assert.Equal(t, "1", csvBenchmarks[0].Transactions)
-	assert.Equal(t, text.PrettyTime(staticTime), csvBenchmarks[0].RecordedAt)
+	assert.Equal(t, "2021-01-01T00:00:00Z", csvBenchmarks[0].RecordedAt)
 
 	assert.Equal(t, "2", csvBenchmarks[1].ID)
 	assert.Equal(t, "Test MachineID 2", csvBenchmarks[1].MachineID)
 	assert.Equal(t, "2.00", csvBenchmarks[1].CPUMinLoad)
 	assert.Equal(t, "2", csvBenchmarks[1].Transactions)
-	assert.Equal(t, text.PrettyTime(staticTime), csvBenchmarks[1].RecordedAt)
+	assert.Equal(t, "2021-01-01T00:00:00Z", csvBenchmarks[1].RecordedAt)
+}
+
+func TestSystemMetricSamplesToCSV(t *testing.T) {
+	t.Parallel()
+
+	staticTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	benchmarks := []*models.Benchmark{
+		{
+			ID:      pulid.ID("1"),
+			Clients: 10,
+			Edges: ent.BenchmarkEdges{
+				SystemMetricSamples: []*models.SystemMetricSample{
+					{
+						SampledAt:             staticTime,
+						Elapsed:               duration.Duration(1500 * time.Millisecond),
+						CPULoad:               50.0,
+						TransactionsPerSecond: pointer.To(1234.5),
+						AverageLatency:        pointer.To(duration.Duration(2500 * time.Microsecond)),
+					},
+					{
+						SampledAt: staticTime.Add(time.Second),
+						Elapsed:   duration.Duration(2500 * time.Millisecond),
+						CPULoad:   75.0,
+					},
+				},
+			},
+		},
+		{
+			ID: pulid.ID("2"),
+		},
+	}
+
+	csvSamples := SystemMetricSamplesToCSV(benchmarks)
+
+	assert.Len(t, csvSamples, 2)
+
+	assert.Equal(t, "1", csvSamples[0].BenchmarkID)
+	assert.Equal(t, "10", csvSamples[0].Clients)
+	assert.Equal(t, text.PrettyTime(staticTime), csvSamples[0].SampledAt)
+	assert.Equal(t, "1.50", csvSamples[0].Elapsed)
+	assert.Equal(t, "50.00", csvSamples[0].CPULoad)
+	assert.Equal(t, "1234.50", csvSamples[0].TransactionsPerSecond)
+	assert.Equal(t, "2.50", csvSamples[0].AverageLatency)
+
+	// Missing progress is exported as a gap
+	assert.Equal(t, "2.50", csvSamples[1].Elapsed)
+	assert.Equal(t, "75.00", csvSamples[1].CPULoad)
+	assert.Empty(t, csvSamples[1].TransactionsPerSecond)
+	assert.Empty(t, csvSamples[1].AverageLatency)
 }
@@ -0,0 +1,243 @@
+package converter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/ent/benchmarkgroup"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// naValue is the placeholder that the CSV export writes for missing values.
+const naValue = "-"
+
+// escapeChar precedes text values that are written like the placeholder of missing values.
+const escapeChar = `\`
+
+// csvParser parses the columns of a CSV record. It remembers the first error, so that a record can be parsed without
+// checking each column individually.
+type csvParser struct {
+	err error
+}
+
+func (p *csvParser) fail(column, value string, err error) {
+	if p.err == nil {
+		p.err = fmt.Errorf("parse column %s value %q: %w", column, value, err)
+	}
+}
+
+func (p *csvParser) float(column, value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.fail(column, value, err)
+	}
+	return f
+}
+
+func (p *csvParser) int(column, value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		p.fail(column, value, err)
+	}
+	return i
+}
+
+func (p *csvParser) duration(column, value string) duration.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		p.fail(column, value, err)
+	}
+	return duration.Duration(d)
+}
+
+func (p *csvParser) time(column, value string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err == nil {
+		return t.UTC()
+	}
+
+	// Older versions of dbench wrote times in local time, without a zone and only to the second
+	t, legacyErr := time.ParseInLocation("2006-01-02 15:04:05", value, time.Local)
+	if legacyErr != nil {
+		p.fail(column, value, err)
+	}
+	return t.UTC()
+}
+
+func optionalString(value string) *string {
+	if value == naValue {
+		return nil
+	}
+
+	value = strings.TrimPrefix(value, escapeChar)
+	return &value
+}
+
+func optionalUint[T uint8 | uint32 | uint64](p *csvParser, column, value string, bits int) *T {
+	if value == naValue {
+		return nil
+	}
+
+	u, err := strconv.ParseUint(value, 10, bits)
+	if err != nil {
+		p.fail(column, value, err)
+		return nil
+	}
+
+	v := T(u)
+	return &v
+}
+
+func optionalFloat(p *csvParser, column, value string) *float64 {
+	if value == naValue {
+		return nil
+	}
+
+	f := p.float(column, value)
+	return &f
+}
+
+// BenchmarkFromCSV converts a CSV record back into a benchmark. It reverses BenchmarkToCSV as far as possible: the
+// config, tags, group name, system config, result and system metric are restored. The CSV only holds aggregates of the
+// disk, network and process metrics and the shares of the wait events, so these edges can't be restored.
+func BenchmarkFromCSV(c *models.BenchmarkCSV) (*models.Benchmark, error) {
+	p := &csvParser{}
+
+	b := &models.Benchmark{
+		ID:              pulid.ID(c.ID),
+		GroupID:         pulid.ID(c.GroupID),
+		Comment:         optionalString(c.Comment),
+		Version:         c.Version,
+		Command:         c.Command,
+		TransactionType: c.TransactionType,
+		QueryMode:       c.QueryMode,
+		ScalingFactor:   p.float("ScalingFactor", c.ScalingFactor),
+		Clients:         p.int("Clients", c.Clients),
+		Threads:         p.int("Threads", c.Threads),
+		RecordedAt:      p.time("RecordedAt", c.RecordedAt),
+	}
+
+	// Tags
+	if c.Tags != naValue {
+		tags, err := models.ParseTags(strings.Split(c.Tags, ","))
+		if err != nil {
+			return nil, fmt.Errorf("parse column Tags: %w", err)
+		}
+		b.Edges.Tags = tags
+	}
+
+	// Group; without a name, the group is derived from the benchmark when it's saved
+	if c.GroupName != naValue {
+		b.Edges.Group = &models.BenchmarkGroup{
+			ID:        b.GroupID,
+			Name:      optionalString(c.GroupName),
+			Status:    benchmarkgroup.StatusCompleted,
+			StartedAt: b.RecordedAt,
+		}
+	}
+
+	// System config; benchmarks without one are exported with all of its columns missing
+	systemColumns := []string{
+		c.MachineID, c.OsName, c.OsArch, c.CPUVendor, c.CPUModel, c.CPUCount, c.CPUCores, c.CPUThreads, c.RAMPhysical,
+		c.RAMUsable, c.DiskCount, c.DiskSpaceTotal, c.KernelVersion, c.CPUGovernor, c.NumaNodes, c.TransparentHugePages,
+		c.DataDirFilesystem, c.DataDirMountOptions, c.DiskType, c.CgroupVersion, c.CgroupCPUQuota, c.CgroupMemoryLimit,
+		c.CgroupIOLimits,
+	}
+	if slices.ContainsFunc(systemColumns, func(v string) bool { return v != naValue }) {
+		b.Edges.System = systemConfigFromCSV(p, c)
+	}
+
+	// Benchmark result
+	b.Edges.Result = &models.BenchmarkResult{
+		Transactions:          p.int("Transactions", c.Transactions),
+		TransactionsPerSecond: p.float("TransactionsPerSecond", c.TransactionsPerSecond),
+		FailedTransactions:    p.int("FailedTransactions", c.FailedTransactions),
+		AverageLatency:        p.duration("AverageLatency", c.AverageLatency),
+		ConnectionTime:        p.duration("ConnectionTime", c.ConnectionTime),
+		TotalRuntime:          p.duration("TotalRuntime", c.TotalRuntime),
+	}
+
+	// System metric; only the aggregates that are part of the CSV are restored
+	metric := &models.SystemMetric{
+		CPUMinLoad:         p.float("CPUMinLoad", c.CPUMinLoad),
+		CPUMaxLoad:         p.float("CPUMaxLoad", c.CPUMaxLoad),
+		CPUAverageLoad:     p.float("CPUAverageLoad", c.CPUAverageLoad),
+		CPU50thLoad:        p.float("CPU50thLoad", c.CPU50thLoad),
+		CPU75thLoad:        p.float("CPU75thLoad", c.CPU75thLoad),
+		CPU90thLoad:        p.float("CPU90thLoad", c.CPU90thLoad),
+		CPU95thLoad:        p.float("CPU95thLoad", c.CPU95thLoad),
+		CPU99thLoad:        p.float("CPU99thLoad", c.CPU99thLoad),
+		MemoryMinLoad:      p.float("MemoryMinLoad", c.MemoryMinLoad),
+		MemoryMaxLoad:      p.float("MemoryMaxLoad", c.MemoryMaxLoad),
+		MemoryAverageLoad:  p.float("MemoryAverageLoad", c.MemoryAverageLoad),
+		Memory50thLoad:     p.float("Memory50thLoad", c.Memory50thLoad),
+		Memory75thLoad:     p.float("Memory75thLoad", c.Memory75thLoad),
+		Memory90thLoad:     p.float("Memory90thLoad", c.Memory90thLoad),
+		Memory95thLoad:     p.float("Memory95thLoad", c.Memory95thLoad),
+		Memory99thLoad:     p.float("Memory99thLoad", c.Memory99thLoad),
+		LoadAverageAverage: p.float("LoadAverage", c.LoadAverage),
+		LoadAverage95th:    p.float("LoadAverage95th", c.LoadAverage95th),
+		IowaitAverageLoad:  p.float("IOWaitAverageLoad", c.IOWaitAverageLoad),
+		Iowait95thLoad:     p.float("IOWait95thLoad", c.IOWait95thLoad),
+		SwapAverageLoad:    p.float("SwapAverageLoad", c.SwapAverageLoad),
+	}
+
+	// Benchmarks without a system metric are exported with all metrics being zero
+	if metric.CPUMaxLoad > 0 || metric.MemoryMaxLoad > 0 || metric.LoadAverageAverage > 0 ||
+		metric.IowaitAverageLoad > 0 || metric.SwapAverageLoad > 0 {
+		b.Edges.SystemMetric = metric
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return b, nil
+}
+
+// BenchmarksFromCSV converts a slice of CSV records back into benchmarks.
+func BenchmarksFromCSV(records []*models.BenchmarkCSV) ([]*models.Benchmark, error) {
+	benchmarks := make([]*models.Benchmark, 0, len(records))
+	for idx, record := range records {
+		b, err := BenchmarkFromCSV(record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", idx+1, err)
+		}
+		benchmarks = append(benchmarks, b)
+	}
+	return benchmarks, nil
+}
+
+func systemConfigFromCSV(p *csvParser, c *models.BenchmarkCSV) *models.SystemConfig {
+	return &models.SystemConfig{
+		MachineID:            optionalString(c.MachineID),
+		OsName:               optionalString(c.OsName),
+		OsArch:               optionalString(c.OsArch),
+		CPUVendor:            optionalString(c.CPUVendor),
+		CPUModel:             optionalString(c.CPUModel),
+		CPUCount:             optionalUint[uint32](p, "CPUCount", c.CPUCount, 32),
+		CPUCores:             optionalUint[uint32](p, "CPUCores", c.CPUCores, 32),
+		CPUThreads:           optionalUint[uint32](p, "CPUThreads", c.CPUThreads, 32),
+		RAMPhysical:          optionalUint[uint64](p, "RAMPhysical", c.RAMPhysical, 64),
+		RAMUsable:            optionalUint[uint64](p, "RAMUsable", c.RAMUsable, 64),
+		DiskCount:            optionalUint[uint32](p, "DiskCount", c.DiskCount, 32),
+		DiskSpaceTotal:       optionalUint[uint64](p, "DiskSpaceTotal", c.DiskSpaceTotal, 64),
+		KernelVersion:        optionalString(c.KernelVersion),
+		CPUGovernor:          optionalString(c.CPUGovernor),
+		NumaNodes:            optionalUint[uint32](p, "NumaNodes", c.NumaNodes, 32),
+		TransparentHugePages: optionalString(c.TransparentHugePages),
+		DataDirFilesystem:    optionalString(c.DataDirFilesystem),
+		DataDirMountOptions:  optionalString(c.DataDirMountOptions),
+		DiskType:             optionalString(c.DiskType),
+		CgroupVersion:        optionalUint[uint8](p, "CgroupVersion", c.CgroupVersion, 8),
+		CgroupCPUQuota:       optionalFloat(p, "CgroupCPUQuota", c.CgroupCPUQuota),
+		CgroupMemoryLimit:    optionalUint[uint64](p, "CgroupMemoryLimit", c.CgroupMemoryLimit, 64),
+		CgroupIoLimits:       optionalString(c.CgroupIOLimits),
+	}
+}
@@ -0,0 +1,133 @@
+package converter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/pointer"
+)
+
+func TestBenchmarkFromCSV(t *testing.T) {
+	t.Parallel()
+
+	staticTime := time.Date(2021, 1, 1, 12, 30, 0, 0, time.UTC)
+
+	b := &models.Benchmark{
+		ID:              pulid.ID("bmk_1"),
+		GroupID:         pulid.ID("bmkgrp_1"),
+		Comment:         pointer.To("Test Comment"),
+		Version:         "1.0",
+		Command:         "Test Command",
+		TransactionType: "Test TransactionType",
+		QueryMode:       "simple",
+		ScalingFactor:   1.5,
+		Clients:         8,
+		Threads:         2,
+		Edges: ent.BenchmarkEdges{
+			Group: &models.BenchmarkGroup{Name: pointer.To("nightly")},
+			Tags: []*models.Tag{
+				{Key: "env", Value: "staging"},
+				{Key: "pg", Value: "16.1"},
+			},
+			System: &models.SystemConfig{
+				MachineID:     pointer.To("machine"),
+				CPUCount:      pointer.To(uint32(2)),
+				RAMPhysical:   pointer.To(uint64(1024)),
+				CgroupVersion: pointer.To(uint8(2)),
+			},
+			SystemMetric: &models.SystemMetric{
+				CPUMaxLoad:     50.0,
+				CPUAverageLoad: 25.0,
+			},
+			Result: &models.BenchmarkResult{
+				Transactions:          100,
+				TransactionsPerSecond: 10.5,
+				AverageLatency:        duration.Duration(1500 * time.Microsecond),
+				ConnectionTime:        duration.Duration(time.Millisecond),
+				TotalRuntime:          duration.Duration(10 * time.Second),
+			},
+		},
+		RecordedAt: staticTime,
+	}
+
+	got, err := BenchmarkFromCSV(BenchmarkToCSV(b))
+	assert.NoError(t, err)
+	if got == nil {
+		return
+	}
+
+	assert.Equal(t, b.ID, got.ID)
+	assert.Equal(t, b.GroupID, got.GroupID)
+	assert.Equal(t, b.Comment, got.Comment)
+	assert.Equal(t, b.Command, got.Command)
+	assert.Equal(t, b.ScalingFactor, got.ScalingFactor)
+	assert.Equal(t, b.Clients, got.Clients)
+	assert.Equal(t, b.Threads, got.Threads)
+	assert.True(t, staticTime.Equal(got.RecordedAt))
+	assert.Equal(t, "nightly", *got.Edges.Group.Name)
+	assert.Equal(t, "env=staging,pg=16.1", models.FormatTags(got.Edges.Tags))
+	assert.Equal(t, b.Edges.System.MachineID, got.Edges.System.MachineID)
+	assert.Equal(t, b.Edges.System.CPUCount, got.Edges.System.CPUCount)
+	assert.Equal(t, b.Edges.System.RAMPhysical, got.Edges.System.RAMPhysical)
+	assert.Equal(t, b.Edges.System.CgroupVersion, got.Edges.System.CgroupVersion)
+	assert.Nil(t, got.Edges.System.OsName)
+	assert.Equal(t, b.Edges.Result.Transactions, got.Edges.Result.Transactions)
+	assert.Equal(t, b.Edges.Result.TransactionsPerSecond, got.Edges.Result.TransactionsPerSecond)
+	assert.Equal(t, b.Edges.Result.AverageLatency, got.Edges.Result.AverageLatency)
+	assert.Equal(t, b.Edges.Result.TotalRuntime, got.Edges.Result.TotalRuntime)
+	assert.Equal(t, b.Edges.SystemMetric.CPUMaxLoad, got.Edges.SystemMetric.CPUMaxLoad)
+}
+
+func TestBenchmarkFromCSVInvalid(t *testing.T) {
+	t.Parallel()
+
+	c := BenchmarkToCSV(&models.Benchmark{RecordedAt: time.Now()})
+	c.Clients = "many"
+
+	_, err := BenchmarkFromCSV(c)
+	assert.ErrorContains(t, err, "Clients")
+}
+
+func TestBenchmarkFromCSVIsLossless(t *testing.T) {
+	t.Parallel()
+
+	recordedAt := time.Date(2024, 1, 31, 15, 4, 5, 123456789, time.UTC)
+
+	for _, comment := range []*string{nil, pointer.To("-"), pointer.To(`\-`), pointer.To(`\n`), pointer.To("")} {
+		b := &models.Benchmark{
+			Comment:    comment,
+			RecordedAt: recordedAt,
+			Edges: ent.BenchmarkEdges{
+				Result: &models.BenchmarkResult{TransactionsPerSecond: 1234.56789},
+			},
+		}
+
+		got, err := BenchmarkFromCSV(BenchmarkToCSV(b))
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		assert.Equal(t, comment, got.Comment)
+		assert.Equal(t, recordedAt, got.RecordedAt)
+		assert.Equal(t, 1234.56789, got.Edges.Result.TransactionsPerSecond)
+	}
+}
+
+func TestBenchmarkFromCSVLegacyTime(t *testing.T) {
+	t.Parallel()
+
+	// Older versions of dbench wrote the local time without a zone
+	c := BenchmarkToCSV(&models.Benchmark{})
+	c.RecordedAt = "2024-01-31 15:04:05"
+
+	got, err := BenchmarkFromCSV(c)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2024, 1, 31, 15, 4, 5, 0, time.Local).UTC(), got.RecordedAt)
+	}
+}
@@ -0,0 +1,166 @@
+package converter
+
+import (
+	"time"
+
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/pointer"
+)
+
+func milliseconds(d duration.Duration) *float64 {
+	return pointer.To(float64(d) / float64(time.Millisecond))
+}
+
+// BenchmarkToParquet converts a benchmark to a Parquet-exportable type. The columns match the ones of BenchmarkToCSV,
+// but the values of missing edges are null instead of zero.
+func BenchmarkToParquet(b *models.Benchmark) *models.BenchmarkParquet {
+	p := &models.BenchmarkParquet{
+		ID:              b.ID.String(),
+		GroupID:         b.GroupID.String(),
+		Comment:         b.Comment,
+		Version:         b.Version,
+		Command:         b.Command,
+		TransactionType: b.TransactionType,
+		QueryMode:       b.QueryMode,
+		ScalingFactor:   b.ScalingFactor,
+		Clients:         int64(b.Clients),
+		Threads:         int64(b.Threads),
+		RecordedAt:      b.RecordedAt,
+	}
+
+	if b.Edges.Group != nil {
+		p.GroupName = b.Edges.Group.Name
+	}
+	if len(b.Edges.Tags) > 0 {
+		p.Tags = pointer.To(models.FormatTags(b.Edges.Tags))
+	}
+
+	// System config
+	if system := b.Edges.System; system != nil {
+		p.MachineID = system.MachineID
+		p.OsName = system.OsName
+		p.OsArch = system.OsArch
+		p.CPUVendor = system.CPUVendor
+		p.CPUModel = system.CPUModel
+		p.CPUCount = system.CPUCount
+		p.CPUCores = system.CPUCores
+		p.CPUThreads = system.CPUThreads
+		p.RAMPhysical = system.RAMPhysical
+		p.RAMUsable = system.RAMUsable
+		p.DiskCount = system.DiskCount
+		p.DiskSpaceTotal = system.DiskSpaceTotal
+		p.KernelVersion = system.KernelVersion
+		p.CPUGovernor = system.CPUGovernor
+		p.NumaNodes = system.NumaNodes
+		p.TransparentHugePages = system.TransparentHugePages
+		p.DataDirFilesystem = system.DataDirFilesystem
+		p.DataDirMountOptions = system.DataDirMountOptions
+		p.DiskType = system.DiskType
+		p.CgroupVersion = system.CgroupVersion
+		p.CgroupCPUQuota = system.CgroupCPUQuota
+		p.CgroupMemoryLimit = system.CgroupMemoryLimit
+		p.CgroupIOLimits = system.CgroupIoLimits
+	}
+
+	// Benchmark result
+	if result := b.Edges.Result; result != nil {
+		p.Transactions = pointer.To(int64(result.Transactions))
+		p.TransactionsPerSecond = pointer.To(result.TransactionsPerSecond)
+		p.FailedTransactions = pointer.To(int64(result.FailedTransactions))
+		p.AverageLatency = milliseconds(result.AverageLatency)
+		p.ConnectionTime = milliseconds(result.ConnectionTime)
+		p.TotalRuntime = milliseconds(result.TotalRuntime)
+	}
+
+	// System metric
+	if metric := b.Edges.SystemMetric; metric != nil {
+		p.CPUMinLoad = pointer.To(metric.CPUMinLoad)
+		p.CPUMaxLoad = pointer.To(metric.CPUMaxLoad)
+		p.CPUAverageLoad = pointer.To(metric.CPUAverageLoad)
+		p.CPU50thLoad = pointer.To(metric.CPU50thLoad)
+		p.CPU75thLoad = pointer.To(metric.CPU75thLoad)
+		p.CPU90thLoad = pointer.To(metric.CPU90thLoad)
+		p.CPU95thLoad = pointer.To(metric.CPU95thLoad)
+		p.CPU99thLoad = pointer.To(metric.CPU99thLoad)
+		p.MemoryMinLoad = pointer.To(metric.MemoryMinLoad)
+		p.MemoryMaxLoad = pointer.To(metric.MemoryMaxLoad)
+		p.MemoryAverageLoad = pointer.To(metric.MemoryAverageLoad)
+		p.Memory50thLoad = pointer.To(metric.Memory50thLoad)
+		p.Memory75thLoad = pointer.To(metric.Memory75thLoad)
+		p.Memory90thLoad = pointer.To(metric.Memory90thLoad)
+		p.Memory95thLoad = pointer.To(metric.Memory95thLoad)
+		p.Memory99thLoad = pointer.To(metric.Memory99thLoad)
+		p.LoadAverage = pointer.To(metric.LoadAverageAverage)
+		p.LoadAverage95th = pointer.To(metric.LoadAverage95th)
+		p.IOWaitAverageLoad = pointer.To(metric.IowaitAverageLoad)
+		p.IOWait95thLoad = pointer.To(metric.Iowait95thLoad)
+		p.SwapAverageLoad = pointer.To(metric.SwapAverageLoad)
+	}
+
+	// Disk and network rates are summed up over all devices and interfaces. For utilization, the busiest device counts.
+	if len(b.Edges.DiskMetrics) > 0 {
+		var read, write, readIOPS, writeIOPS, utilization float64
+		for _, disk := range b.Edges.DiskMetrics {
+			read += disk.ReadBytesPerSecondAverage
+			write += disk.WriteBytesPerSecondAverage
+			readIOPS += disk.ReadIopsAverage
+			writeIOPS += disk.WriteIopsAverage
+			utilization = max(utilization, disk.UtilizationAverage)
+		}
+
+		p.DiskReadThroughput = pointer.To(read)
+		p.DiskWriteThroughput = pointer.To(write)
+		p.DiskReadIOPS = pointer.To(readIOPS)
+		p.DiskWriteIOPS = pointer.To(writeIOPS)
+		p.DiskMaxUtilization = pointer.To(utilization)
+	}
+
+	if len(b.Edges.NetworkMetrics) > 0 {
+		var received, sent float64
+		for _, network := range b.Edges.NetworkMetrics {
+			received += network.BytesReceivedPerSecondAverage
+			sent += network.BytesSentPerSecondAverage
+		}
+
+		p.NetworkReceived = pointer.To(received)
+		p.NetworkSent = pointer.To(sent)
+	}
+
+	// Process metrics are keyed by their role
+	for _, processMetric := range b.Edges.ProcessMetrics {
+		switch string(processMetric.Role) {
+		case models.ProcessRoleServer:
+			p.ServerCPUAverageLoad = pointer.To(processMetric.CPUAverageLoad)
+			p.ServerCPUMaxLoad = pointer.To(processMetric.CPUMaxLoad)
+			p.ServerMemoryRSSMax = pointer.To(processMetric.MemoryRssMax)
+		case models.ProcessRoleClient:
+			p.ClientCPUAverageLoad = pointer.To(processMetric.CPUAverageLoad)
+			p.ClientCPUMaxLoad = pointer.To(processMetric.CPUMaxLoad)
+			p.ClientMemoryRSSMax = pointer.To(processMetric.MemoryRssMax)
+		}
+	}
+
+	// Wait events
+	if len(b.Edges.WaitEvents) > 0 {
+		shares := waitEventShares(b.Edges.WaitEvents)
+		p.WaitEventCPU = pointer.To(shares["CPU"])
+		p.WaitEventLock = pointer.To(shares["Lock"])
+		p.WaitEventLWLock = pointer.To(shares["LWLock"])
+		p.WaitEventIO = pointer.To(shares["IO"])
+		p.WaitEventIPC = pointer.To(shares["IPC"])
+		p.WaitEventClient = pointer.To(shares["Client"])
+		p.WaitEventOther = pointer.To(shares["Other"])
+	}
+
+	return p
+}
+
+// BenchmarksToParquet converts a slice of benchmarks to a Parquet-exportable type.
+func BenchmarksToParquet(benchmarks []*models.Benchmark) []*models.BenchmarkParquet {
+	parquetBenchmarks := make([]*models.BenchmarkParquet, 0, len(benchmarks))
+	for _, b := range benchmarks {
+		parquetBenchmarks = append(parquetBenchmarks, BenchmarkToParquet(b))
+	}
+	return parquetBenchmarks
+}
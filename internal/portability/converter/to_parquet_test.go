@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/ent"
+	"github.com/nikoksr/dbench/ent/schema/duration"
+	"github.com/nikoksr/dbench/ent/schema/pulid"
+	"github.com/nikoksr/dbench/internal/models"
+	"github.com/nikoksr/dbench/internal/pointer"
+	"github.com/nikoksr/dbench/internal/portability/parquet"
+)
+
+func TestBenchmarkToParquet(t *testing.T) {
+	t.Parallel()
+
+	staticTime := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b := &models.Benchmark{
+		ID:              pulid.ID("1"),
+		GroupID:         pulid.ID("2"),
+		Comment:         pointer.To("Test Comment"),
+		Version:         "1.0",
+		Command:         "Test Command",
+		TransactionType: "Test TransactionType",
+		QueryMode:       "Test QueryMode",
+		ScalingFactor:   1.5,
+		Clients:         4,
+		Threads:         2,
+		Edges: ent.BenchmarkEdges{
+			Group: &models.BenchmarkGroup{Name: pointer.To("nightly")},
+			Tags: []*models.Tag{
+				{Key: "pg", Value: "16.1"},
+				{Key: "env", Value: "staging"},
+			},
+			System: &models.SystemConfig{
+				MachineID:     pointer.To("Test MachineID"),
+				CPUCount:      pointer.To(uint32(8)),
+				RAMPhysical:   pointer.To(uint64(1024)),
+				CgroupVersion: pointer.To(uint8(2)),
+			},
+			SystemMetric: &models.SystemMetric{
+				CPUMaxLoad:         90.5,
+				LoadAverageAverage: 1.5,
+			},
+			Result: &models.BenchmarkResult{
+				Transactions:          1000,
+				TransactionsPerSecond: 123.456,
+				FailedTransactions:    3,
+				AverageLatency:        duration.Duration(1500 * time.Microsecond),
+				ConnectionTime:        duration.Duration(2 * time.Millisecond),
+				TotalRuntime:          duration.Duration(10 * time.Second),
+			},
+			DiskMetrics: []*models.DiskMetric{
+				{Device: "sda", ReadBytesPerSecondAverage: 100, WriteBytesPerSecondAverage: 200, ReadIopsAverage: 1, WriteIopsAverage: 2, UtilizationAverage: 10},
+				{Device: "sdb", ReadBytesPerSecondAverage: 100, WriteBytesPerSecondAverage: 200, ReadIopsAverage: 1, WriteIopsAverage: 2, UtilizationAverage: 20},
+			},
+			NetworkMetrics: []*models.NetworkMetric{
+				{InterfaceName: "lo", BytesReceivedPerSecondAverage: 1000, BytesSentPerSecondAverage: 500},
+			},
+			ProcessMetrics: []*models.ProcessMetric{
+				{Role: models.ProcessRoleServer, CPUAverageLoad: 150.0, CPUMaxLoad: 200.0, MemoryRssMax: 1024},
+				{Role: models.ProcessRoleClient, CPUAverageLoad: 50.0, CPUMaxLoad: 75.0, MemoryRssMax: 512},
+			},
+			WaitEvents: []*models.WaitEvent{
+				{WaitEventType: "CPU", WaitEvent: "CPU", Count: 2},
+				{WaitEventType: "Lock", WaitEvent: "transactionid", Count: 1},
+				{WaitEventType: "Timeout", WaitEvent: "VacuumDelay", Count: 1},
+			},
+		},
+		RecordedAt: staticTime,
+	}
+
+	p := BenchmarkToParquet(b)
+
+	assert.Equal(t, "1", p.ID)
+	assert.Equal(t, "2", p.GroupID)
+	assert.Equal(t, pointer.To("nightly"), p.GroupName)
+	assert.Equal(t, pointer.To("Test Comment"), p.Comment)
+	assert.Equal(t, pointer.To("env=staging,pg=16.1"), p.Tags)
+	assert.Equal(t, "1.0", p.Version)
+	assert.Equal(t, "Test Command", p.Command)
+	assert.Equal(t, 1.5, p.ScalingFactor)
+	assert.Equal(t, int64(4), p.Clients)
+	assert.Equal(t, int64(2), p.Threads)
+	assert.Equal(t, staticTime, p.RecordedAt)
+
+	// System config
+	assert.Equal(t, pointer.To("Test MachineID"), p.MachineID)
+	assert.Equal(t, pointer.To(uint32(8)), p.CPUCount)
+	assert.Equal(t, pointer.To(uint64(1024)), p.RAMPhysical)
+	assert.Equal(t, pointer.To(uint8(2)), p.CgroupVersion)
+	assert.Nil(t, p.OsName)
+	assert.Nil(t, p.CPUCores)
+
+	// Benchmark result; durations in milliseconds
+	assert.Equal(t, pointer.To(int64(1000)), p.Transactions)
+	assert.Equal(t, pointer.To(123.456), p.TransactionsPerSecond)
+	assert.Equal(t, pointer.To(int64(3)), p.FailedTransactions)
+	assert.Equal(t, pointer.To(1.5), p.AverageLatency)
+	assert.Equal(t, pointer.To(2.0), p.ConnectionTime)
+	assert.Equal(t, pointer.To(10_000.0), p.TotalRuntime)
+
+	// System metric
+	assert.Equal(t, pointer.To(90.5), p.CPUMaxLoad)
+	assert.Equal(t, pointer.To(0.0), p.CPUMinLoad)
+	assert.Equal(t, pointer.To(1.5), p.LoadAverage)
+
+	// Disk, network and process metrics
+	assert.Equal(t, pointer.To(200.0), p.DiskReadThroughput)
+	assert.Equal(t, pointer.To(400.0), p.DiskWriteThroughput)
+	assert.Equal(t, pointer.To(2.0), p.DiskReadIOPS)
+	assert.Equal(t, pointer.To(4.0), p.DiskWriteIOPS)
+	assert.Equal(t, pointer.To(20.0), p.DiskMaxUtilization)
+	assert.Equal(t, pointer.To(1000.0), p.NetworkReceived)
+	assert.Equal(t, pointer.To(500.0), p.NetworkSent)
+	assert.Equal(t, pointer.To(150.0), p.ServerCPUAverageLoad)
+	assert.Equal(t, pointer.To(200.0), p.ServerCPUMaxLoad)
+	assert.Equal(t, pointer.To(uint64(1024)), p.ServerMemoryRSSMax)
+	assert.Equal(t, pointer.To(50.0), p.ClientCPUAverageLoad)
+	assert.Equal(t, pointer.To(75.0), p.ClientCPUMaxLoad)
+	assert.Equal(t, pointer.To(uint64(512)), p.ClientMemoryRSSMax)
+
+	// Wait events
+	assert.Equal(t, pointer.To(50.0), p.WaitEventCPU)
+	assert.Equal(t, pointer.To(25.0), p.WaitEventLock)
+	assert.Equal(t, pointer.To(0.0), p.WaitEventIO)
+	assert.Equal(t, pointer.To(25.0), p.WaitEventOther)
+}
+
+func TestBenchmarkToParquetMissingEdges(t *testing.T) {
+	t.Parallel()
+
+	b := &models.Benchmark{
+		ID:         pulid.ID("1"),
+		GroupID:    pulid.ID("2"),
+		Command:    "Test Command",
+		RecordedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	p := BenchmarkToParquet(b)
+
+	// Missing edges are null instead of zero
+	assert.Equal(t, "Test Command", p.Command)
+	assert.Nil(t, p.GroupName)
+	assert.Nil(t, p.Comment)
+	assert.Nil(t, p.Tags)
+	assert.Nil(t, p.MachineID)
+	assert.Nil(t, p.CPUCount)
+	assert.Nil(t, p.Transactions)
+	assert.Nil(t, p.TotalRuntime)
+	assert.Nil(t, p.CPUMaxLoad)
+	assert.Nil(t, p.DiskReadThroughput)
+	assert.Nil(t, p.NetworkReceived)
+	assert.Nil(t, p.ServerCPUAverageLoad)
+	assert.Nil(t, p.ClientMemoryRSSMax)
+	assert.Nil(t, p.WaitEventCPU)
+}
+
+func TestBenchmarksToParquet(t *testing.T) {
+	t.Parallel()
+
+	benchmarks := []*models.Benchmark{
+		{ID: pulid.ID("1")},
+		{ID: pulid.ID("2"), Edges: ent.BenchmarkEdges{Result: &models.BenchmarkResult{Transactions: 1}}},
+	}
+
+	rows := BenchmarksToParquet(benchmarks)
+	if !assert.Len(t, rows, 2) {
+		return
+	}
+
+	assert.Equal(t, "1", rows[0].ID)
+	assert.Nil(t, rows[0].Transactions)
+	assert.Equal(t, "2", rows[1].ID)
+	assert.Equal(t, pointer.To(int64(1)), rows[1].Transactions)
+
+	// All columns have to be supported by the Parquet writer
+	var buf bytes.Buffer
+	pw, err := parquet.NewWriter(&buf, &models.BenchmarkParquet{}, "dbench")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, row := range rows {
+		assert.NoError(t, pw.Write(row))
+	}
+	assert.NoError(t, pw.Close())
+}
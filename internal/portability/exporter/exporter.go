@@ -20,3 +20,14 @@ func ToCSV(w io.Writer, data any) error {
 func ToJSON(w io.Writer, data any) error {
 	return json.NewEncoder(w).Encode(data)
 }
+
+// ToParquet exports a slice of structs to a Parquet file. The columns are derived from the fields of the struct, see
+// parquet.NewWriter.
+func ToParquet(w io.Writer, data any) error {
+	stream := NewParquetStream(w)
+	if err := stream.Write(data); err != nil {
+		return err
+	}
+
+	return stream.Close()
+}
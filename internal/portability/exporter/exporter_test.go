@@ -93,3 +93,19 @@ func TestToJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestToParquet(t *testing.T) {
+	t.Parallel()
+
+	buf := bytes.Buffer{}
+	err := exporter.ToParquet(&buf, []dummy{{ID: "1", Name: pointer.To("Test"), Age: 1, Married: true}, {ID: "2"}})
+	assert.NoError(t, err)
+
+	data := buf.Bytes()
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+
+	// Unsupported data
+	err = exporter.ToParquet(&bytes.Buffer{}, "not a slice")
+	assert.Error(t, err)
+}
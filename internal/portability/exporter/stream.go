@@ -0,0 +1,158 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/gocarina/gocsv"
+
+	"github.com/nikoksr/dbench/internal/build"
+	"github.com/nikoksr/dbench/internal/portability/parquet"
+)
+
+// StreamExporter writes records page by page, so that an export doesn't have to hold all of its records in memory.
+// Close has to be called after the last page to complete the output.
+type StreamExporter interface {
+	// Write writes a page of records. The page has to be a slice.
+	Write(page any) error
+	// Close completes the output. It doesn't close the underlying writer.
+	Close() error
+}
+
+// sliceElements calls fn for each element of the given slice.
+func sliceElements(page any, fn func(elem any) error) error {
+	v := reflect.ValueOf(page)
+	if v.Kind() != reflect.Slice {
+		return fmt.Errorf("page must be a slice, got %T", page)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if err := fn(v.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type jsonStream struct {
+	w       io.Writer
+	encoder *json.Encoder
+	started bool
+}
+
+// NewJSONStream returns a StreamExporter that writes the records as a single JSON array, like ToJSON.
+func NewJSONStream(w io.Writer) StreamExporter {
+	return &jsonStream{w: w, encoder: json.NewEncoder(w)}
+}
+
+func (s *jsonStream) Write(page any) error {
+	return sliceElements(page, func(elem any) error {
+		delim := ","
+		if !s.started {
+			delim = "["
+			s.started = true
+		}
+
+		if _, err := io.WriteString(s.w, delim); err != nil {
+			return err
+		}
+
+		return s.encoder.Encode(elem)
+	})
+}
+
+func (s *jsonStream) Close() error {
+	// An export without any records is still a valid, empty array
+	closing := "]\n"
+	if !s.started {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(s.w, closing)
+	return err
+}
+
+type ndjsonStream struct {
+	encoder *json.Encoder
+}
+
+// NewNDJSONStream returns a StreamExporter that writes newline delimited JSON, one record per line.
+func NewNDJSONStream(w io.Writer) StreamExporter {
+	return &ndjsonStream{encoder: json.NewEncoder(w)}
+}
+
+func (s *ndjsonStream) Write(page any) error {
+	return sliceElements(page, s.encoder.Encode)
+}
+
+func (s *ndjsonStream) Close() error {
+	return nil
+}
+
+type csvStream struct {
+	writer        *gocsv.SafeCSVWriter
+	headerWritten bool
+}
+
+// NewCSVStream returns a StreamExporter that writes the records as CSV, like ToCSV. The header is written along with
+// the first page.
+func NewCSVStream(w io.Writer) StreamExporter {
+	return &csvStream{writer: gocsv.DefaultCSVWriter(w)}
+}
+
+func (s *csvStream) Write(page any) error {
+	if reflect.ValueOf(page).Len() == 0 {
+		return nil
+	}
+
+	if s.headerWritten {
+		return gocsv.MarshalCSVWithoutHeaders(page, s.writer)
+	}
+
+	s.headerWritten = true
+	return gocsv.MarshalCSV(page, s.writer)
+}
+
+func (s *csvStream) Close() error {
+	s.writer.Flush()
+	return s.writer.Error()
+}
+
+type parquetStream struct {
+	w      io.Writer
+	writer *parquet.Writer
+}
+
+// NewParquetStream returns a StreamExporter that writes the records to a Parquet file, like ToParquet. The schema is
+// derived from the type of the first page, which may be empty.
+func NewParquetStream(w io.Writer) StreamExporter {
+	return &parquetStream{w: w}
+}
+
+func (s *parquetStream) Write(page any) error {
+	if s.writer == nil {
+		pageType := reflect.TypeOf(page)
+		if pageType == nil || pageType.Kind() != reflect.Slice {
+			return fmt.Errorf("page must be a slice, got %T", page)
+		}
+
+		writer, err := parquet.NewWriter(s.w, reflect.Zero(pageType.Elem()).Interface(), build.AppName+" version "+build.Version)
+		if err != nil {
+			return err
+		}
+		s.writer = writer
+	}
+
+	return sliceElements(page, s.writer.Write)
+}
+
+func (s *parquetStream) Close() error {
+	if s.writer == nil {
+		return fmt.Errorf("no page written, the schema of the parquet file is unknown")
+	}
+
+	return s.writer.Close()
+}
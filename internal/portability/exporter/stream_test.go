@@ -0,0 +1,78 @@
+package exporter_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/internal/pointer"
+	"github.com/nikoksr/dbench/internal/portability/exporter"
+)
+
+func TestStreamExporters(t *testing.T) {
+	t.Parallel()
+
+	pages := [][]dummy{
+		{{ID: "1", Name: pointer.To("Test"), Age: 1, Married: true}},
+		{},
+		{{ID: "2", Name: pointer.To("Other"), Age: 2}, {ID: "3", Age: 3}},
+	}
+
+	testCases := []struct {
+		name      string
+		newStream func(w io.Writer) exporter.StreamExporter
+		pages     [][]dummy
+		expected  string
+		jsonEq    bool // The JSON array is compared semantically, as the encoder separates its records by newlines
+	}{
+		{
+			name:      "JSON",
+			newStream: exporter.NewJSONStream,
+			pages:     pages,
+			jsonEq:    true,
+			expected:  `[{"id":"1","name":"Test","age":1,"married":true},{"id":"2","name":"Other","age":2,"married":false},{"id":"3","name":null,"age":3,"married":false}]`,
+		},
+		{
+			name:      "Empty JSON",
+			newStream: exporter.NewJSONStream,
+			expected:  "[]",
+			jsonEq:    true,
+		},
+		{
+			name:      "NDJSON",
+			newStream: exporter.NewNDJSONStream,
+			pages:     pages,
+			expected: "{\"id\":\"1\",\"name\":\"Test\",\"age\":1,\"married\":true}\n" +
+				"{\"id\":\"2\",\"name\":\"Other\",\"age\":2,\"married\":false}\n" +
+				"{\"id\":\"3\",\"name\":null,\"age\":3,\"married\":false}\n",
+		},
+		{
+			name:      "CSV",
+			newStream: exporter.NewCSVStream,
+			pages:     pages,
+			expected:  "id,name,age,married\n1,Test,1,true\n2,Other,2,false\n3,,3,false\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			buf := bytes.Buffer{}
+			stream := tc.newStream(&buf)
+			for _, page := range tc.pages {
+				assert.NoError(t, stream.Write(page))
+			}
+			assert.NoError(t, stream.Close())
+
+			if tc.jsonEq {
+				assert.JSONEq(t, tc.expected, buf.String())
+				return
+			}
+			assert.Equal(t, tc.expected, buf.String())
+		})
+	}
+}
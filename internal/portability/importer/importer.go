@@ -1,8 +1,14 @@
 package importer
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"unicode"
+
+	"github.com/gocarina/gocsv"
 )
 
 // FromJSON reads JSON data from a reader and returns a slice of the given type.
@@ -15,3 +21,90 @@ func FromJSON[T any](r io.Reader) (T, error) {
 
 	return data, nil
 }
+
+// FromCSV reads CSV data from a reader and returns a slice of the given type. The columns are matched by the csv tags
+// of the type.
+func FromCSV[T any](r io.Reader) (T, error) {
+	var data T
+
+	if err := gocsv.Unmarshal(r, &data); err != nil {
+		return data, err
+	}
+
+	return data, nil
+}
+
+// StreamCSV decodes the records of CSV data one by one and calls fn for each of them, so that only a single record is
+// held in memory at a time. The columns are matched by the csv tags of the type.
+func StreamCSV[T any](r io.Reader, fn func(record T) error) error {
+	return gocsv.UnmarshalToCallbackWithError(r, fn)
+}
+
+// StreamJSON decodes the records of a JSON array or of newline delimited JSON one by one and calls fn for each of
+// them, so that only a single record is held in memory at a time. The raw record is passed to fn, which is responsible
+// for decoding it.
+func StreamJSON(r io.Reader, fn func(record json.RawMessage) error) error {
+	br := bufio.NewReader(r)
+
+	first, err := firstByte(br)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(br)
+
+	// Anything but an array is treated as newline delimited JSON
+	if first != '[' {
+		return decodeRecords(decoder, fn)
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		var record json.RawMessage
+		if err := decoder.Decode(&record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	// Consume the closing bracket, so that truncated files are reported
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("read end of array: %w", err)
+	}
+
+	return nil
+}
+
+// firstByte returns the first byte of the reader that isn't whitespace, without consuming it.
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b)) {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+func decodeRecords(decoder *json.Decoder, fn func(record json.RawMessage) error) error {
+	for {
+		var record json.RawMessage
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
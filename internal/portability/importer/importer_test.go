@@ -84,3 +84,96 @@ func jsonEqual(a, b interface{}) bool {
 	}
 	return bytes.Equal(aJSON, bJSON)
 }
+
+type dummyCSV struct {
+	ID   string `csv:"ID"`
+	Name string `csv:"Name"`
+}
+
+func TestFromCSV(t *testing.T) {
+	reader := bytes.NewBufferString("ID,Name\n1,Test\n2,Other\n")
+
+	got, err := importer.FromCSV[[]*dummyCSV](reader)
+	if err != nil {
+		t.Fatalf("FromCSV() error = %v", err)
+	}
+
+	want := []*dummyCSV{{ID: "1", Name: "Test"}, {ID: "2", Name: "Other"}}
+	if !jsonEqual(got, want) {
+		t.Errorf("FromCSV() got = %v, want %v", got, want)
+	}
+}
+
+func TestStreamJSON(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    []dummy
+		wantErr bool
+	}{
+		{
+			name:  "Array",
+			input: `[{"id":"1","name":"Test"},{"id":"2","name":"Other"}]`,
+			want:  []dummy{{ID: "1", Name: "Test"}, {ID: "2", Name: "Other"}},
+		},
+		{
+			name:  "Empty Array",
+			input: " []\n",
+		},
+		{
+			name:  "NDJSON",
+			input: "{\"id\":\"1\",\"name\":\"Test\"}\n{\"id\":\"2\",\"name\":\"Other\"}\n",
+			want:  []dummy{{ID: "1", Name: "Test"}, {ID: "2", Name: "Other"}},
+		},
+		{
+			name:    "Truncated Array",
+			input:   `[{"id":"1","name":"Test"},`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty Input",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []dummy
+			err := importer.StreamJSON(bytes.NewBufferString(tc.input), func(record json.RawMessage) error {
+				var d dummy
+				if err := json.Unmarshal(record, &d); err != nil {
+					return err
+				}
+				got = append(got, d)
+				return nil
+			})
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("StreamJSON() error = %v, wantErr %v", err, tc.wantErr)
+			}
+
+			if !tc.wantErr && !jsonEqual(got, tc.want) {
+				t.Errorf("StreamJSON() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	reader := bytes.NewBufferString("ID,Name\n1,Test\n2,Other\n")
+
+	var got []*dummyCSV
+	err := importer.StreamCSV(reader, func(record *dummyCSV) error {
+		got = append(got, record)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamCSV() error = %v", err)
+	}
+
+	want := []*dummyCSV{{ID: "1", Name: "Test"}, {ID: "2", Name: "Other"}}
+	if !jsonEqual(got, want) {
+		t.Errorf("StreamCSV() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,195 @@
+// Package manifest describes the files of a dbench export, so that imports can verify them and know which version of
+// the record layout they hold.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileName is the name of the manifest in an export directory.
+const FileName = "manifest.json"
+
+// SchemaVersion is the version of the record layout that is exported. Bump it whenever the layout changes in a way
+// that older exports have to be transformed on import, and add the transformation to upgrades.
+const SchemaVersion = 2
+
+// LegacySchemaVersion is the version of exports that were written before the manifest was introduced.
+const LegacySchemaVersion = 1
+
+// upgrades transform a JSON record of the version they're keyed by to the next version.
+var upgrades = map[int]func(record map[string]any) error{
+	// Version 2 introduced the manifest, the records themselves didn't change.
+	1: func(map[string]any) error { return nil },
+}
+
+// Manifest describes an export.
+type Manifest struct {
+	AppVersion    string    `json:"app_version"`
+	SchemaVersion int       `json:"schema_version"`
+	Format        string    `json:"format"`
+	CreatedAt     time.Time `json:"created_at"`
+	Files         []File    `json:"files"`
+}
+
+// File describes a single file of an export.
+type File struct {
+	Name    string `json:"name"`
+	Records int    `json:"records"`
+	SHA256  string `json:"sha256"`
+}
+
+// New returns a manifest for an export of the given format by the given version of dbench.
+func New(appVersion, format string) *Manifest {
+	return &Manifest{
+		AppVersion:    appVersion,
+		SchemaVersion: SchemaVersion,
+		Format:        format,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+// Lookup returns the file with the given name.
+func (m *Manifest) Lookup(name string) (File, bool) {
+	for _, file := range m.Files {
+		if file.Name == name {
+			return file, true
+		}
+	}
+
+	return File{}, false
+}
+
+// Write writes the manifest to the given export directory.
+func (m *Manifest) Write(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(dir, FileName), data, 0o644)
+}
+
+// Read reads the manifest of the given export directory. It returns nil and no error if the directory has no manifest,
+// which is the case for exports of older versions of dbench.
+func Read(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+
+	m := new(Manifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+
+	if m.SchemaVersion > SchemaVersion {
+		return nil, fmt.Errorf("export has schema version %d, which is newer than the supported version %d; it was created by dbench %s", m.SchemaVersion, SchemaVersion, m.AppVersion)
+	}
+
+	return m, nil
+}
+
+// Verify checks that all files of the manifest exist in the given export directory and match their checksums.
+func (m *Manifest) Verify(dir string) error {
+	var errs []error
+	for _, file := range m.Files {
+		sum, err := Checksum(filepath.Join(dir, file.Name))
+		if errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("%s is missing", file.Name))
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", file.Name, err))
+			continue
+		}
+
+		if sum != file.SHA256 {
+			errs = append(errs, fmt.Errorf("%s is corrupted; checksum mismatch", file.Name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Checksum returns the hex encoded SHA-256 checksum of the given file.
+func Checksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashingWriter is a writer that computes the SHA-256 checksum of everything that is written to it.
+type HashingWriter struct {
+	w    io.Writer
+	hash hash.Hash
+}
+
+// NewHashingWriter returns a writer that writes to w and computes the checksum of the written data.
+func NewHashingWriter(w io.Writer) *HashingWriter {
+	return &HashingWriter{w: w, hash: sha256.New()}
+}
+
+// Write writes p to the underlying writer and adds it to the checksum.
+func (hw *HashingWriter) Write(p []byte) (int, error) {
+	n, err := hw.w.Write(p)
+	hw.hash.Write(p[:n])
+	return n, err
+}
+
+// Sum returns the hex encoded SHA-256 checksum of the data written so far.
+func (hw *HashingWriter) Sum() string {
+	return hex.EncodeToString(hw.hash.Sum(nil))
+}
+
+// UpgradeRecord transforms a JSON record of the given schema version to the current one.
+func UpgradeRecord(data json.RawMessage, version int) (json.RawMessage, error) {
+	if version == SchemaVersion {
+		return data, nil
+	}
+	if version < LegacySchemaVersion || version > SchemaVersion {
+		return nil, fmt.Errorf("unsupported schema version %d", version)
+	}
+
+	// Numbers are kept as they are, durations in nanoseconds would lose precision as floats
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var record map[string]any
+	if err := decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+
+	for v := version; v < SchemaVersion; v++ {
+		upgrade, ok := upgrades[v]
+		if !ok {
+			return nil, fmt.Errorf("no upgrade from schema version %d", v)
+		}
+		if err := upgrade(record); err != nil {
+			return nil, fmt.Errorf("upgrade from schema version %d: %w", v, err)
+		}
+	}
+
+	return json.Marshal(record)
+}
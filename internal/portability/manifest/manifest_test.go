@@ -0,0 +1,76 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifestVerify(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	f, err := os.Create(filepath.Join(dir, "export_1.json"))
+	assert.NoError(t, err)
+
+	w := NewHashingWriter(f)
+	_, err = w.Write([]byte(`[{"id":"bmk_1"}]`))
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	m := New("v1.0.0", "json")
+	m.Files = append(m.Files, File{Name: "export_1.json", Records: 1, SHA256: w.Sum()})
+	assert.NoError(t, m.Write(dir))
+
+	read, err := Read(dir)
+	assert.NoError(t, err)
+	if assert.NotNil(t, read) {
+		assert.Equal(t, SchemaVersion, read.SchemaVersion)
+		assert.NoError(t, read.Verify(dir))
+	}
+
+	// Corrupt the file
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "export_1.json"), []byte(`[{"id":"bmk_2"}]`), 0o644))
+	assert.ErrorContains(t, m.Verify(dir), "export_1.json is corrupted")
+
+	// Remove the file
+	assert.NoError(t, os.Remove(filepath.Join(dir, "export_1.json")))
+	assert.ErrorContains(t, m.Verify(dir), "export_1.json is missing")
+}
+
+func TestReadWithoutManifest(t *testing.T) {
+	t.Parallel()
+
+	m, err := Read(t.TempDir())
+	assert.NoError(t, err)
+	assert.Nil(t, m)
+}
+
+func TestReadNewerSchemaVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	m := New("v99.0.0", "json")
+	m.SchemaVersion = SchemaVersion + 1
+	assert.NoError(t, m.Write(dir))
+
+	_, err := Read(dir)
+	assert.ErrorContains(t, err, "newer than the supported version")
+}
+
+func TestUpgradeRecord(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"id":"bmk_1","edges":{"result":{"total_runtime":9007199254740993}}}`)
+
+	upgraded, err := UpgradeRecord(data, LegacySchemaVersion)
+	assert.NoError(t, err)
+	assert.JSONEq(t, string(data), string(upgraded))
+
+	_, err = UpgradeRecord(data, SchemaVersion+1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Physical types of Parquet.
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeFloat     = 4
+	typeDouble    = 5
+	typeByteArray = 6
+)
+
+// Converted types of Parquet, which tell readers how to interpret the physical types.
+const (
+	convertedNone            = -1
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+	convertedUint8           = 11
+	convertedUint32          = 13
+	convertedUint64          = 14
+)
+
+// Repetition types of Parquet.
+const (
+	repetitionRequired = 0
+	repetitionOptional = 1
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// column holds the buffered values of a column of the current row group.
+type column struct {
+	name      string
+	field     int
+	physical  int32
+	converted int32
+	optional  bool
+	encode    func(buf *bytes.Buffer, v reflect.Value)
+
+	values    bytes.Buffer
+	bools     []bool // Boolean values are bit-packed, so they're collected first
+	defLevels []bool // Whether each row has a value, only for optional columns
+	numValues int
+}
+
+// columnsOf derives the columns from the exported fields of the given struct type. A column is named by the parquet
+// tag of its field, or the name of the field if there's none. Fields tagged with "-" are skipped and pointer fields
+// are optional columns.
+func columnsOf(rowType reflect.Type) ([]*column, error) {
+	if rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("rows must be structs, got %s", rowType)
+	}
+
+	var columns []*column
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("parquet")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		col := &column{name: name, field: i}
+
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			col.optional = true
+			typ = typ.Elem()
+		}
+
+		if err := col.setType(typ); err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		columns = append(columns, col)
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%s has no exported fields", rowType)
+	}
+
+	return columns, nil
+}
+
+func (c *column) setType(typ reflect.Type) error {
+	c.converted = convertedNone
+
+	if typ == timeType {
+		c.physical, c.converted = typeInt64, convertedTimestampMicros
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) {
+			putUint64(buf, uint64(v.Interface().(time.Time).UnixMicro()))
+		}
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		c.physical = typeBoolean
+	case reflect.String:
+		c.physical, c.converted = typeByteArray, convertedUTF8
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) {
+			putUint32(buf, uint32(v.Len()))
+			buf.WriteString(v.String())
+		}
+	case reflect.Int32:
+		c.physical = typeInt32
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint32(buf, uint32(v.Int())) }
+	case reflect.Int, reflect.Int64:
+		c.physical = typeInt64
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint64(buf, uint64(v.Int())) }
+	case reflect.Uint8:
+		c.physical, c.converted = typeInt32, convertedUint8
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint32(buf, uint32(v.Uint())) }
+	case reflect.Uint32:
+		c.physical, c.converted = typeInt32, convertedUint32
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint32(buf, uint32(v.Uint())) }
+	case reflect.Uint, reflect.Uint64:
+		c.physical, c.converted = typeInt64, convertedUint64
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint64(buf, v.Uint()) }
+	case reflect.Float32:
+		c.physical = typeFloat
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint32(buf, math.Float32bits(float32(v.Float()))) }
+	case reflect.Float64:
+		c.physical = typeDouble
+		c.encode = func(buf *bytes.Buffer, v reflect.Value) { putUint64(buf, math.Float64bits(v.Float())) }
+	default:
+		return fmt.Errorf("unsupported type %s", typ)
+	}
+
+	return nil
+}
+
+// add adds the value of the column of the given row.
+func (c *column) add(row reflect.Value) {
+	c.numValues++
+
+	v := row.Field(c.field)
+	if c.optional {
+		c.defLevels = append(c.defLevels, !v.IsNil())
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if c.physical == typeBoolean {
+		c.bools = append(c.bools, v.Bool())
+		return
+	}
+
+	c.encode(&c.values, v)
+}
+
+// page returns the data of a data page holding the buffered values. The definition levels of optional columns precede
+// the values.
+func (c *column) page() []byte {
+	var page bytes.Buffer
+
+	if c.optional {
+		levels := encodeLevels(c.defLevels)
+		putUint32(&page, uint32(len(levels)))
+		page.Write(levels)
+	}
+
+	if c.physical == typeBoolean {
+		page.Write(packBools(c.bools))
+	} else {
+		page.Write(c.values.Bytes())
+	}
+
+	return page.Bytes()
+}
+
+func (c *column) reset() {
+	c.values.Reset()
+	c.bools = c.bools[:0]
+	c.defLevels = c.defLevels[:0]
+	c.numValues = 0
+}
+
+// encodeLevels encodes definition levels of a bit width of 1 with the RLE part of the RLE/bit-packing hybrid encoding.
+func encodeLevels(levels []bool) []byte {
+	var out []byte
+	for start := 0; start < len(levels); {
+		end := start + 1
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+
+		out = binary.AppendUvarint(out, uint64(end-start)<<1)
+		if levels[start] {
+			out = append(out, 1)
+		} else {
+			out = append(out, 0)
+		}
+
+		start = end
+	}
+
+	return out
+}
+
+// packBools packs booleans into bits, least significant bit first, as the PLAIN encoding of booleans requires.
+func packBools(bools []bool) []byte {
+	out := make([]byte, (len(bools)+7)/8)
+	for i, b := range bools {
+		if b {
+			out[i/8] |= 1 << (i % 8)
+		}
+	}
+
+	return out
+}
+
+func putUint32(buf *bytes.Buffer, v uint32) {
+	buf.Write(binary.LittleEndian.AppendUint32(nil, v))
+}
+
+func putUint64(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.LittleEndian.AppendUint64(nil, v))
+}
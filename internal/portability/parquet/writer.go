@@ -0,0 +1,255 @@
+// Package parquet writes Apache Parquet files. It supports flat schemas of required and optional primitive columns,
+// which are written uncompressed and PLAIN encoded. That's all the exports of dbench need and keeps the writer free of
+// any dependencies.
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+const magic = "PAR1"
+
+// DefaultRowGroupSize is the number of rows that are buffered before they're written as a row group.
+const DefaultRowGroupSize = 10_000
+
+// Encodings of Parquet.
+const (
+	encodingPlain = 0
+	encodingRLE   = 3
+)
+
+type columnChunk struct {
+	offset    int64
+	size      int64
+	numValues int
+}
+
+type rowGroup struct {
+	chunks  []columnChunk
+	size    int64
+	numRows int
+}
+
+// Writer writes rows of a struct type to a Parquet file. The columns are derived from the fields of the struct, see
+// columnsOf. Rows are buffered and written as row groups, so that only a single row group is held in memory at a time.
+type Writer struct {
+	w            io.Writer
+	offset       int64
+	rowType      reflect.Type
+	columns      []*column
+	rowGroups    []rowGroup
+	buffered     int
+	numRows      int64
+	rowGroupSize int
+	createdBy    string
+}
+
+// NewWriter returns a writer for rows of the type of the given value, which has to be a struct or a pointer to one.
+// The file is attributed to the given application.
+func NewWriter(w io.Writer, row any, createdBy string) (*Writer, error) {
+	rowType := reflect.TypeOf(row)
+	columns, err := columnsOf(rowType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Writer{
+		w:            w,
+		rowType:      rowType,
+		columns:      columns,
+		rowGroupSize: DefaultRowGroupSize,
+		createdBy:    createdBy,
+	}, nil
+}
+
+func (pw *Writer) write(p []byte) error {
+	n, err := pw.w.Write(p)
+	pw.offset += int64(n)
+	return err
+}
+
+// Write buffers the given row, which has to be of the type the writer was created for. A row group is written once
+// enough rows are buffered.
+func (pw *Writer) Write(row any) error {
+	v := reflect.ValueOf(row)
+	if v.Type() != pw.rowType {
+		return fmt.Errorf("row must be of type %s, got %s", pw.rowType, v.Type())
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("row is nil")
+		}
+		v = v.Elem()
+	}
+
+	for _, col := range pw.columns {
+		col.add(v)
+	}
+
+	pw.buffered++
+	if pw.buffered >= pw.rowGroupSize {
+		return pw.Flush()
+	}
+
+	return nil
+}
+
+// Flush writes the buffered rows as a row group.
+func (pw *Writer) Flush() error {
+	if pw.buffered == 0 {
+		return nil
+	}
+
+	if pw.offset == 0 {
+		if err := pw.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+
+	group := rowGroup{numRows: pw.buffered}
+	for _, col := range pw.columns {
+		chunk, err := pw.writeColumnChunk(col)
+		if err != nil {
+			return fmt.Errorf("write column %s: %w", col.name, err)
+		}
+
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.size
+		col.reset()
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.numRows += int64(pw.buffered)
+	pw.buffered = 0
+
+	return nil
+}
+
+// writeColumnChunk writes the buffered values of the given column as a single data page.
+func (pw *Writer) writeColumnChunk(col *column) (columnChunk, error) {
+	page := col.page()
+
+	t := &thriftWriter{}
+	t.beginStruct()
+	t.i32(1, 0) // Data page
+	t.i32(2, int32(len(page)))
+	t.i32(3, int32(len(page)))
+	t.structField(5)
+	t.i32(1, int32(col.numValues))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE) // Definition levels
+	t.i32(4, encodingRLE) // Repetition levels
+	t.endStruct()
+	t.endStruct()
+
+	chunk := columnChunk{
+		offset:    pw.offset,
+		size:      int64(t.buf.Len() + len(page)),
+		numValues: col.numValues,
+	}
+
+	if err := pw.write(t.buf.Bytes()); err != nil {
+		return chunk, err
+	}
+
+	return chunk, pw.write(page)
+}
+
+// Close writes the remaining rows and the footer of the file. It doesn't close the underlying writer.
+func (pw *Writer) Close() error {
+	if err := pw.Flush(); err != nil {
+		return err
+	}
+
+	// A file without any rows still needs its leading magic
+	if pw.offset == 0 {
+		if err := pw.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+
+	metadata := pw.fileMetadata()
+	if err := pw.write(metadata); err != nil {
+		return err
+	}
+
+	footer := binary.LittleEndian.AppendUint32(nil, uint32(len(metadata)))
+	footer = append(footer, magic...)
+
+	return pw.write(footer)
+}
+
+// fileMetadata encodes the FileMetaData structure of the Parquet format.
+func (pw *Writer) fileMetadata() []byte {
+	t := &thriftWriter{}
+	t.beginStruct()
+
+	t.i32(1, 1) // Version
+
+	// Schema; a root element followed by the columns
+	t.list(2, thriftStruct, len(pw.columns)+1)
+	t.beginStruct()
+	t.string(4, "schema")
+	t.i32(5, int32(len(pw.columns)))
+	t.endStruct()
+
+	for _, col := range pw.columns {
+		repetition := int32(repetitionRequired)
+		if col.optional {
+			repetition = repetitionOptional
+		}
+
+		t.beginStruct()
+		t.i32(1, col.physical)
+		t.i32(3, repetition)
+		t.string(4, col.name)
+		if col.converted != convertedNone {
+			t.i32(6, col.converted)
+		}
+		t.endStruct()
+	}
+
+	t.i64(3, pw.numRows)
+
+	// Row groups
+	t.list(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		t.beginStruct()
+
+		t.list(1, thriftStruct, len(group.chunks))
+		for idx, chunk := range group.chunks {
+			col := pw.columns[idx]
+
+			t.beginStruct()
+			t.i64(2, chunk.offset)
+
+			t.structField(3)
+			t.i32(1, col.physical)
+			t.list(2, thriftI32, 2)
+			t.i32Elem(encodingPlain)
+			t.i32Elem(encodingRLE)
+			t.list(3, thriftBinary, 1)
+			t.stringElem(col.name)
+			t.i32(4, 0) // Uncompressed
+			t.i64(5, int64(chunk.numValues))
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.endStruct()
+
+			t.endStruct()
+		}
+
+		t.i64(2, group.size)
+		t.i64(3, int64(group.numRows))
+		t.endStruct()
+	}
+
+	t.string(6, pw.createdBy)
+	t.endStruct()
+
+	return t.buf.Bytes()
+}
@@ -0,0 +1,289 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nikoksr/dbench/internal/pointer"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata")
+
+const goldenFile = "testdata/rows.parquet"
+
+// thriftReader decodes the Thrift compact protocol into generic values, so that the written metadata can be checked.
+// Structs are decoded into maps by field ID, lists into slices and integers into int64.
+type thriftReader struct {
+	r *bytes.Reader
+}
+
+func (t *thriftReader) zigzag() int64 {
+	u, _ := binary.ReadUvarint(t.r)
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (t *thriftReader) value(typ byte) any {
+	switch typ {
+	case thriftI32, thriftI64:
+		return t.zigzag()
+	case thriftBinary:
+		n, _ := binary.ReadUvarint(t.r)
+		b := make([]byte, n)
+		_, _ = t.r.Read(b)
+		return string(b)
+	case thriftList:
+		header, _ := t.r.ReadByte()
+		size := int(header >> 4)
+		if size == 15 {
+			n, _ := binary.ReadUvarint(t.r)
+			size = int(n)
+		}
+		list := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			list = append(list, t.value(header&0x0f))
+		}
+		return list
+	case thriftStruct:
+		fields := make(map[int64]any)
+		var id int64
+		for {
+			header, _ := t.r.ReadByte()
+			if header == 0 {
+				return fields
+			}
+			if delta := int64(header >> 4); delta != 0 {
+				id += delta
+			} else {
+				id = t.zigzag()
+			}
+			fields[id] = t.value(header & 0x0f)
+		}
+	default:
+		return nil
+	}
+}
+
+type testRow struct {
+	Name       string    `parquet:"name"`
+	Comment    *string   `parquet:"comment"`
+	Clients    int       `parquet:"clients"`
+	CPUCount   *uint32   `parquet:"cpu_count"`
+	TPS        float64   `parquet:"tps"`
+	Failed     bool      `parquet:"failed"`
+	RecordedAt time.Time `parquet:"recorded_at"`
+}
+
+var testRecordedAt = time.Date(2024, 1, 31, 15, 4, 5, 0, time.UTC)
+
+// writeTestRows writes three rows, which cover missing values of optional columns, in two row groups.
+func writeTestRows() ([]byte, error) {
+	rows := []*testRow{
+		{
+			Name: "a", Comment: pointer.To("warm cache"), Clients: 1, CPUCount: pointer.To(uint32(8)), TPS: 1234.5,
+			Failed: true, RecordedAt: testRecordedAt,
+		},
+		{Name: "bb", Clients: 2, TPS: 99, RecordedAt: testRecordedAt},
+		{Name: "ccc", Clients: 3, TPS: 0.5, Failed: true, RecordedAt: testRecordedAt},
+	}
+
+	var buf bytes.Buffer
+	pw, err := NewWriter(&buf, &testRow{}, "dbench")
+	if err != nil {
+		return nil, err
+	}
+	pw.rowGroupSize = 2
+
+	for _, row := range rows {
+		if err := pw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := pw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	data, err := writeTestRows()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, magic, string(data[:4]))
+	assert.Equal(t, magic, string(data[len(data)-4:]))
+
+	metadataLen := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	metadataStart := len(data) - 8 - metadataLen
+	metadata := (&thriftReader{r: bytes.NewReader(data[metadataStart : len(data)-8])}).value(thriftStruct).(map[int64]any)
+
+	// Schema
+	schema := metadata[2].([]any)
+	assert.Len(t, schema, 8)
+	assert.Equal(t, int64(7), schema[0].(map[int64]any)[5])
+
+	names := make([]string, 0, 7)
+	for _, elem := range schema[1:] {
+		names = append(names, elem.(map[int64]any)[4].(string))
+	}
+	assert.Equal(t, []string{"name", "comment", "clients", "cpu_count", "tps", "failed", "recorded_at"}, names)
+	assert.Equal(t, int64(repetitionOptional), schema[2].(map[int64]any)[3])
+	assert.Equal(t, int64(convertedUint32), schema[4].(map[int64]any)[6])
+	assert.Equal(t, int64(convertedTimestampMicros), schema[7].(map[int64]any)[6])
+
+	// Row groups
+	assert.Equal(t, int64(3), metadata[3])
+	rowGroups := metadata[4].([]any)
+	assert.Len(t, rowGroups, 2)
+	assert.Equal(t, int64(2), rowGroups[0].(map[int64]any)[3])
+	assert.Equal(t, int64(1), rowGroups[1].(map[int64]any)[3])
+	assert.Equal(t, "dbench", metadata[6])
+
+	// Pages of the first row group
+	chunks := rowGroups[0].(map[int64]any)[1].([]any)
+	readPage := func(column int) []byte {
+		meta := chunks[column].(map[int64]any)[3].(map[int64]any)
+		offset := meta[9].(int64)
+		r := bytes.NewReader(data[offset:])
+		header := (&thriftReader{r: r}).value(thriftStruct).(map[int64]any)
+		page := make([]byte, header[3].(int64))
+		_, _ = r.Read(page)
+		return page
+	}
+
+	// Required strings are length-prefixed
+	assert.Equal(t, []byte{1, 0, 0, 0, 'a', 2, 0, 0, 0, 'b', 'b'}, readPage(0))
+
+	// Optional strings are preceded by their definition levels; a run of one present and one missing value
+	assert.Equal(t, []byte{4, 0, 0, 0, 2, 1, 2, 0, 10, 0, 0, 0, 'w', 'a', 'r', 'm', ' ', 'c', 'a', 'c', 'h', 'e'}, readPage(1))
+
+	tps := readPage(4)
+	assert.Equal(t, 1234.5, math.Float64frombits(binary.LittleEndian.Uint64(tps[:8])))
+	assert.Equal(t, []byte{0b01}, readPage(5))
+	assert.Equal(t, testRecordedAt.UnixMicro(), int64(binary.LittleEndian.Uint64(readPage(6)[:8])))
+}
+
+// TestWriterGolden guards against unintended changes of the file format. The golden file is checked against a real
+// Parquet reader by TestWriterPyArrow; after an intended change, regenerate it with the -update flag.
+func TestWriterGolden(t *testing.T) {
+	t.Parallel()
+
+	data, err := writeTestRows()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if *update {
+		assert.NoError(t, os.WriteFile(goldenFile, data, 0o644))
+	}
+
+	golden, err := os.ReadFile(goldenFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, golden, data)
+}
+
+// readWithPyArrow prints the schema and the columns of a Parquet file as JSON. Timestamps are printed as microseconds
+// since the Unix epoch.
+const readWithPyArrow = `
+import json, sys
+import pyarrow as pa, pyarrow.parquet as pq
+
+table = pq.read_table(sys.argv[1])
+print(json.dumps({
+    "types": {field.name: str(field.type) for field in table.schema},
+    "columns": {
+        name: (column.cast(pa.int64()) if pa.types.is_timestamp(column.type) else column).to_pylist()
+        for name, column in zip(table.column_names, table.columns)
+    },
+}))
+`
+
+func TestWriterPyArrow(t *testing.T) {
+	t.Parallel()
+
+	python, err := exec.LookPath("python3")
+	if err != nil {
+		t.Skip("python3 is not installed")
+	}
+	if err := exec.Command(python, "-c", "import pyarrow").Run(); err != nil {
+		t.Skip("pyarrow is not installed")
+	}
+
+	path, err := filepath.Abs(goldenFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	out, err := exec.Command(python, "-c", readWithPyArrow, path).Output()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var table struct {
+		Types   map[string]string `json:"types"`
+		Columns map[string][]any  `json:"columns"`
+	}
+	if !assert.NoError(t, json.Unmarshal(out, &table)) {
+		return
+	}
+
+	assert.Equal(t, map[string]string{
+		"name":        "string",
+		"comment":     "string",
+		"clients":     "int64",
+		"cpu_count":   "uint32",
+		"tps":         "double",
+		"failed":      "bool",
+		"recorded_at": "timestamp[us, tz=UTC]",
+	}, table.Types)
+
+	micros := float64(testRecordedAt.UnixMicro())
+	assert.Equal(t, map[string][]any{
+		"name":        {"a", "bb", "ccc"},
+		"comment":     {"warm cache", nil, nil},
+		"clients":     {1.0, 2.0, 3.0},
+		"cpu_count":   {8.0, nil, nil},
+		"tps":         {1234.5, 99.0, 0.5},
+		"failed":      {true, false, true},
+		"recorded_at": {micros, micros, micros},
+	}, table.Columns)
+}
+
+func TestWriterEmpty(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	pw, err := NewWriter(&buf, testRow{}, "dbench")
+	assert.NoError(t, err)
+	assert.NoError(t, pw.Close())
+
+	data := buf.Bytes()
+	assert.Equal(t, magic, string(data[:4]))
+	assert.Equal(t, magic, string(data[len(data)-4:]))
+}
+
+func TestWriterUnsupportedType(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewWriter(&bytes.Buffer{}, struct{ Tags []string }{}, "dbench")
+	assert.Error(t, err)
+
+	_, err = NewWriter(&bytes.Buffer{}, "not a struct", "dbench")
+	assert.Error(t, err)
+}
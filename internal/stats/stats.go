@@ -0,0 +1,119 @@
+// Package stats provides the descriptive statistics that dbench uses to aggregate samples and results.
+package stats
+
+import (
+	"math"
+
+	"golang.org/x/exp/slices"
+)
+
+// Round rounds the given value half away from zero to the given number of decimals.
+func Round(value float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(value*pow) / pow
+}
+
+// Sorted returns a sorted copy of the given values.
+func Sorted(values []float64) []float64 {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	return sorted
+}
+
+// Quantile returns the q-quantile of the given sorted values, with q in [0, 1]. Values between two samples are linearly
+// interpolated, which matches the default method of R, NumPy and PostgreSQL's percentile_cont. An empty series yields
+// zero.
+func Quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	q = math.Max(0, math.Min(1, q))
+
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+// Summary is a streaming summary of a series of values. It keeps the count, min, max and mean without storing the
+// values. The zero value is an empty summary that is ready to use.
+type Summary struct {
+	count    int
+	min, max float64
+	mean     float64
+}
+
+// Add adds a value to the summary.
+func (s *Summary) Add(value float64) {
+	s.count++
+
+	if s.count == 1 {
+		s.min, s.max = value, value
+	} else {
+		s.min = math.Min(s.min, value)
+		s.max = math.Max(s.max, value)
+	}
+
+	s.mean += (value - s.mean) / float64(s.count)
+}
+
+// AddAll adds all given values to the summary.
+func (s *Summary) AddAll(values ...float64) {
+	for _, v := range values {
+		s.Add(v)
+	}
+}
+
+// Count returns the number of values in the summary.
+func (s *Summary) Count() int { return s.count }
+
+// Min returns the smallest value, or zero if the summary is empty.
+func (s *Summary) Min() float64 { return s.min }
+
+// Max returns the largest value, or zero if the summary is empty.
+func (s *Summary) Max() float64 { return s.max }
+
+// Mean returns the arithmetic mean, or zero if the summary is empty.
+func (s *Summary) Mean() float64 { return s.mean }
+
+// Distribution describes a series of values by its summary and percentiles.
+type Distribution struct {
+	Min, Max, Mean          float64
+	P50, P75, P90, P95, P99 float64
+}
+
+// NewDistribution calculates the distribution of the given values. An empty series yields a zero distribution.
+func NewDistribution(values []float64) Distribution {
+	var s Summary
+	s.AddAll(values...)
+
+	sorted := Sorted(values)
+
+	return Distribution{
+		Min:  s.Min(),
+		Max:  s.Max(),
+		Mean: s.Mean(),
+		P50:  Quantile(sorted, 0.50),
+		P75:  Quantile(sorted, 0.75),
+		P90:  Quantile(sorted, 0.90),
+		P95:  Quantile(sorted, 0.95),
+		P99:  Quantile(sorted, 0.99),
+	}
+}
+
+// Round returns a copy of the distribution with all values rounded to the given number of decimals.
+func (d Distribution) Round(decimals int) Distribution {
+	return Distribution{
+		Min:  Round(d.Min, decimals),
+		Max:  Round(d.Max, decimals),
+		Mean: Round(d.Mean, decimals),
+		P50:  Round(d.P50, decimals),
+		P75:  Round(d.P75, decimals),
+		P90:  Round(d.P90, decimals),
+		P95:  Round(d.P95, decimals),
+		P99:  Round(d.P99, decimals),
+	}
+}
@@ -0,0 +1,76 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRound(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1.24, Round(1.235, 2))
+	assert.Equal(t, 1.23, Round(1.2349, 2))
+	assert.Equal(t, -1.24, Round(-1.235, 2))
+	assert.Equal(t, 99.99, Round(99.99, 2))
+	assert.Equal(t, 3.0, Round(2.5, 0))
+}
+
+func TestQuantile(t *testing.T) {
+	t.Parallel()
+
+	sorted := []float64{10, 20, 30, 40, 50}
+
+	cases := []struct {
+		q    float64
+		want float64
+	}{
+		{0, 10},
+		{0.5, 30},
+		{0.75, 40},
+		{0.9, 46},
+		{0.95, 48},
+		{0.99, 49.6},
+		{1, 50},
+		{-1, 10}, // Clamped
+		{2, 50},  // Clamped
+	}
+
+	for _, c := range cases {
+		assert.InDelta(t, c.want, Quantile(sorted, c.q), 1e-9, "q=%v", c.q)
+	}
+
+	assert.Zero(t, Quantile(nil, 0.5))
+	assert.Equal(t, 7.0, Quantile([]float64{7}, 0.99))
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	var s Summary
+	assert.Zero(t, s.Mean())
+
+	s.AddAll(3, 1, 4, 1, 5, 9, 2, 6)
+
+	assert.Equal(t, 8, s.Count())
+	assert.Equal(t, 1.0, s.Min())
+	assert.Equal(t, 9.0, s.Max())
+	assert.InDelta(t, 3.875, s.Mean(), 1e-9)
+}
+
+func TestNewDistribution(t *testing.T) {
+	t.Parallel()
+
+	// With five samples, the percentiles must not collapse to the maximum
+	d := NewDistribution([]float64{50, 10, 40, 20, 30})
+
+	assert.Equal(t, 10.0, d.Min)
+	assert.Equal(t, 50.0, d.Max)
+	assert.Equal(t, 30.0, d.Mean)
+	assert.Equal(t, 30.0, d.P50)
+	assert.Equal(t, 40.0, d.P75)
+	assert.InDelta(t, 46.0, d.P90, 1e-9)
+	assert.Less(t, d.P99, d.Max)
+
+	assert.Equal(t, Distribution{}, NewDistribution(nil))
+}
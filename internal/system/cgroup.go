@@ -0,0 +1,288 @@
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// unlimitedMemoryV1 is the threshold above which a cgroup v1 memory limit is considered unset. Without a limit, the
+// kernel reports the largest page aligned int64 instead of a keyword.
+const unlimitedMemoryV1 = 1 << 62
+
+// Cgroup describes the resource limits of the cgroup that dbench runs in. Inside a container, these limits are what the
+// benchmark is actually bound by, while ghw and gopsutil report the totals of the host. Limits that aren't set are
+// zero or empty.
+type Cgroup struct {
+	Version     int      // Version is the cgroup version, i.e. 1 or 2
+	CPUQuota    float64  // CPUQuota is the number of CPUs the cgroup may use per period, e.g. 1.5
+	MemoryLimit uint64   // MemoryLimit is the memory limit in bytes
+	IOLimits    []string // IOLimits holds the IO throttles per device in the format of io.max, e.g. "8:0 rbps=1048576"
+
+	cpuDir    string
+	memoryDir string
+}
+
+// DetectCgroup detects the cgroup of the current process and reads its limits.
+func DetectCgroup() (*Cgroup, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil, fmt.Errorf("read cgroup membership: %w", err)
+	}
+
+	return detectCgroup(data, cgroupRoot)
+}
+
+// HasLimits reports whether any CPU, memory or IO limit is set.
+func (c *Cgroup) HasLimits() bool {
+	return c.CPUQuota > 0 || c.MemoryLimit > 0 || len(c.IOLimits) > 0
+}
+
+// detectCgroup reads the limits of the cgroup described by the given content of /proc/self/cgroup. The cgroup
+// filesystems are expected to be mounted below root.
+func detectCgroup(membership []byte, root string) (*Cgroup, error) {
+	paths := parseCgroupMembership(membership)
+
+	// A unified hierarchy is mounted directly at the root. On hybrid systems, the controllers we care about are still
+	// bound to the v1 hierarchies.
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		path, ok := paths[""]
+		if !ok {
+			return nil, errors.New("find cgroup v2 membership")
+		}
+		return readCgroupV2(cgroupDir(root, path))
+	}
+
+	return readCgroupV1(root, paths)
+}
+
+// parseCgroupMembership maps each controller of /proc/self/cgroup to the path of the cgroup. The unified hierarchy has
+// no controllers and is stored under the empty key.
+func parseCgroupMembership(membership []byte) map[string]string {
+	paths := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(membership))
+	for scanner.Scan() {
+		// Each line has the format "hierarchy-id:controller-list:path"
+		fields := strings.SplitN(scanner.Text(), ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if fields[1] == "" {
+			paths[""] = fields[2]
+			continue
+		}
+		for _, controller := range strings.Split(fields[1], ",") {
+			paths[controller] = fields[2]
+		}
+	}
+
+	return paths
+}
+
+// cgroupDir returns the directory of the cgroup with the given path. Containers without their own cgroup namespace see
+// the path of the host, but only have their own cgroup mounted at the root, so we fall back to that.
+func cgroupDir(mount, path string) string {
+	dir := filepath.Join(mount, path)
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+
+	return mount
+}
+
+func readCgroupV2(dir string) (*Cgroup, error) {
+	cgroup := &Cgroup{Version: 2, cpuDir: dir, memoryDir: dir}
+
+	// The CPU quota has the format "$MAX $PERIOD", where $MAX may be "max"
+	if content, err := readCgroupFile(dir, "cpu.max"); err == nil {
+		fields := strings.Fields(content)
+		if len(fields) == 2 && fields[0] != "max" {
+			cgroup.CPUQuota, err = cpuQuota(fields[0], fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("parse cpu.max: %w", err)
+			}
+		}
+	}
+
+	if content, err := readCgroupFile(dir, "memory.max"); err == nil && content != "max" {
+		cgroup.MemoryLimit, err = strconv.ParseUint(content, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse memory.max: %w", err)
+		}
+	}
+
+	// Only devices with at least one throttle are listed
+	if content, err := readCgroupFile(dir, "io.max"); err == nil {
+		for _, line := range strings.Split(content, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				cgroup.IOLimits = append(cgroup.IOLimits, line)
+			}
+		}
+	}
+
+	return cgroup, nil
+}
+
+func readCgroupV1(root string, paths map[string]string) (*Cgroup, error) {
+	cgroup := &Cgroup{
+		Version:   1,
+		cpuDir:    cgroupDir(filepath.Join(root, "cpu"), paths["cpu"]),
+		memoryDir: cgroupDir(filepath.Join(root, "memory"), paths["memory"]),
+	}
+
+	// A quota of -1 means that the CPU usage isn't limited
+	quota, errQuota := readCgroupFile(cgroup.cpuDir, "cpu.cfs_quota_us")
+	period, errPeriod := readCgroupFile(cgroup.cpuDir, "cpu.cfs_period_us")
+	if errQuota == nil && errPeriod == nil && quota != "-1" {
+		var err error
+		cgroup.CPUQuota, err = cpuQuota(quota, period)
+		if err != nil {
+			return nil, fmt.Errorf("parse cpu.cfs_quota_us: %w", err)
+		}
+	}
+
+	if content, err := readCgroupFile(cgroup.memoryDir, "memory.limit_in_bytes"); err == nil {
+		limit, err := strconv.ParseUint(content, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse memory.limit_in_bytes: %w", err)
+		}
+		if limit < unlimitedMemoryV1 {
+			cgroup.MemoryLimit = limit
+		}
+	}
+
+	// The throttles are spread over one file per kind, with one "$MAJ:$MIN $LIMIT" line per device. Combine them into
+	// the format of cgroup v2.
+	blkioDir := cgroupDir(filepath.Join(root, "blkio"), paths["blkio"])
+	throttles := make(map[string][]string)
+	for _, kind := range []struct{ file, key string }{
+		{"blkio.throttle.read_bps_device", "rbps"},
+		{"blkio.throttle.write_bps_device", "wbps"},
+		{"blkio.throttle.read_iops_device", "riops"},
+		{"blkio.throttle.write_iops_device", "wiops"},
+	} {
+		content, err := readCgroupFile(blkioDir, kind.file)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(content, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 2 {
+				throttles[fields[0]] = append(throttles[fields[0]], kind.key+"="+fields[1])
+			}
+		}
+	}
+	for device, limits := range throttles {
+		cgroup.IOLimits = append(cgroup.IOLimits, device+" "+strings.Join(limits, " "))
+	}
+	slices.Sort(cgroup.IOLimits)
+
+	return cgroup, nil
+}
+
+// cpuQuota converts a CPU quota and its period, both in microseconds, into the number of CPUs.
+func cpuQuota(quota, period string) (float64, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, err
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil {
+		return 0, err
+	}
+	if q <= 0 || p <= 0 {
+		return 0, nil
+	}
+
+	return q / p, nil
+}
+
+// CPUUsage returns the CPU time consumed by all processes of the cgroup.
+func (c *Cgroup) CPUUsage() (time.Duration, error) {
+	if c.Version == 1 {
+		// The cpuacct controller is usually co-mounted with the cpu controller
+		content, err := readCgroupFile(c.cpuDir, "cpuacct.usage")
+		if err != nil {
+			return 0, err
+		}
+		usage, err := strconv.ParseInt(content, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parse cpuacct.usage: %w", err)
+		}
+		return time.Duration(usage), nil
+	}
+
+	usage, err := readCgroupStat(c.cpuDir, "cpu.stat", "usage_usec")
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(usage) * time.Microsecond, nil
+}
+
+// MemoryUsage returns the working set of the cgroup in bytes. Like docker stats, it excludes the inactive page cache,
+// which the kernel reclaims before it would hit the limit.
+func (c *Cgroup) MemoryUsage() (uint64, error) {
+	usageFile, inactiveKey := "memory.current", "inactive_file"
+	if c.Version == 1 {
+		usageFile, inactiveKey = "memory.usage_in_bytes", "total_inactive_file"
+	}
+
+	content, err := readCgroupFile(c.memoryDir, usageFile)
+	if err != nil {
+		return 0, err
+	}
+	usage, err := strconv.ParseUint(content, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", usageFile, err)
+	}
+
+	// The page cache is only a correction, so a missing memory.stat isn't an error
+	if inactive, err := readCgroupStat(c.memoryDir, "memory.stat", inactiveKey); err == nil && inactive <= usage {
+		usage -= inactive
+	}
+
+	return usage, nil
+}
+
+func readCgroupFile(dir, name string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return "", fmt.Errorf("read %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(string(content)), nil
+}
+
+// readCgroupStat returns the value of the given key of a flat keyed file like cpu.stat or memory.stat.
+func readCgroupStat(dir, name, key string) (uint64, error) {
+	content, err := readCgroupFile(dir, name)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			value, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("parse %s of %s: %w", key, name, err)
+			}
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("find %s in %s", key, name)
+}
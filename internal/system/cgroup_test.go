@@ -0,0 +1,85 @@
+package system
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeCgroupFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestDetectCgroupV2(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeCgroupFiles(t, root, map[string]string{
+		"cgroup.controllers":           "cpu io memory",
+		"docker/abc/cpu.max":           "200000 100000\n",
+		"docker/abc/memory.max":        "1073741824\n",
+		"docker/abc/io.max":            "8:0 rbps=1048576 wbps=max riops=max wiops=100\n",
+		"docker/abc/cpu.stat":          "usage_usec 1500000\nuser_usec 1000000\n",
+		"docker/abc/memory.current":    "536870912\n",
+		"docker/abc/memory.stat":       "anon 1024\ninactive_file 268435456\n",
+		"unlimited/cgroup.controllers": "",
+	})
+
+	cgroup, err := detectCgroup([]byte("0::/docker/abc\n"), root)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, cgroup.Version)
+	assert.Equal(t, 2.0, cgroup.CPUQuota)
+	assert.Equal(t, uint64(1<<30), cgroup.MemoryLimit)
+	assert.Equal(t, []string{"8:0 rbps=1048576 wbps=max riops=max wiops=100"}, cgroup.IOLimits)
+	assert.True(t, cgroup.HasLimits())
+
+	usage, err := cgroup.CPUUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, usage)
+
+	memory, err := cgroup.MemoryUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1<<28), memory)
+
+	// A cgroup without limits
+	cgroup, err = detectCgroup([]byte("0::/unlimited\n"), root)
+	assert.NoError(t, err)
+	assert.False(t, cgroup.HasLimits())
+}
+
+func TestDetectCgroupV1(t *testing.T) {
+	t.Parallel()
+
+	// Without a cgroup namespace, the container only sees its own cgroup at the root of each hierarchy
+	root := t.TempDir()
+	writeCgroupFiles(t, root, map[string]string{
+		"cpu/cpu.cfs_quota_us":                   "50000\n",
+		"cpu/cpu.cfs_period_us":                  "100000\n",
+		"cpu/cpuacct.usage":                      "2000000000\n",
+		"memory/memory.limit_in_bytes":           "9223372036854771712\n",
+		"blkio/blkio.throttle.read_bps_device":   "8:0 1048576\n",
+		"blkio/blkio.throttle.write_iops_device": "8:16 100\n8:0 200\n",
+	})
+
+	membership := "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n3:blkio:/docker/abc\n0::/system.slice\n"
+
+	cgroup, err := detectCgroup([]byte(membership), root)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cgroup.Version)
+	assert.Equal(t, 0.5, cgroup.CPUQuota)
+	assert.Zero(t, cgroup.MemoryLimit) // Unlimited
+	assert.Equal(t, []string{"8:0 rbps=1048576 wiops=200", "8:16 wiops=100"}, cgroup.IOLimits)
+
+	usage, err := cgroup.CPUUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, usage)
+}
@@ -1,11 +1,18 @@
 package system
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/jaypipes/ghw"
 	"github.com/panta/machineid"
+	"github.com/shirou/gopsutil/v3/host"
+	"golang.org/x/exp/slices"
 
 	"github.com/nikoksr/dbench/internal/build"
 	"github.com/nikoksr/dbench/internal/models"
@@ -15,7 +22,10 @@ import (
 // GetConfig collects information about the system and returns it as a SystemConfig struct. If an error occurs, the
 // method will not stop but continue to collect information. The errors will be returned as a slice. We do this since
 // the system config are not essential for dbench to run and should not prevent the user from running a benchmark.
-func GetConfig() (*models.SystemConfig, []error) {
+//
+// The data directory of the PostgreSQL server is optional. If given, the filesystem, mount options and disk type are
+// taken from the disk it resides on.
+func GetConfig(dataDir string) (*models.SystemConfig, []error) {
 	var errs []error
 	systemconfig := new(models.SystemConfig)
 
@@ -54,9 +64,232 @@ func GetConfig() (*models.SystemConfig, []error) {
 		setDiskConfig(block, systemconfig)
 	}
 
+	// Collect information about the NUMA topology
+	topology, err := ghw.Topology()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("get topology info: %w", err))
+	} else if len(topology.Nodes) > 0 {
+		systemconfig.NumaNodes = pointer.To(uint32(len(topology.Nodes)))
+	}
+
+	// Collect information about the kernel. The remaining settings are only exposed by Linux.
+	if version, err := host.KernelVersion(); err != nil {
+		errs = append(errs, fmt.Errorf("get kernel version: %w", err))
+	} else if version != "" {
+		systemconfig.KernelVersion = pointer.To(version)
+	}
+
+	if runtime.GOOS == "linux" {
+		errs = append(errs, setLinuxConfig(dataDir, block, systemconfig)...)
+
+		// Inside a container, the limits of the cgroup matter more than the totals of the host
+		if cgroup, err := DetectCgroup(); err != nil {
+			errs = append(errs, fmt.Errorf("detect cgroup: %w", err))
+		} else {
+			setCgroupConfig(cgroup, systemconfig)
+		}
+	} else if block != nil {
+		systemconfig.DiskType = diskTypes(block.Disks)
+	}
+
 	return systemconfig, errs
 }
 
+// setLinuxConfig collects the kernel settings and the details of the data directory's mount from procfs and sysfs.
+func setLinuxConfig(dataDir string, block *ghw.BlockInfo, systemconfig *models.SystemConfig) []error {
+	var errs []error
+
+	// The governor is usually the same for all CPUs, so we only look at the first one. It's missing in most VMs.
+	if governor, err := os.ReadFile("/sys/devices/system/cpu/cpu0/cpufreq/scaling_governor"); err == nil {
+		systemconfig.CPUGovernor = nonEmpty(string(governor))
+	}
+
+	if thp, err := os.ReadFile("/sys/kernel/mm/transparent_hugepage/enabled"); err != nil {
+		errs = append(errs, fmt.Errorf("get transparent huge pages setting: %w", err))
+	} else {
+		systemconfig.TransparentHugePages = nonEmpty(selectedOption(string(thp)))
+	}
+
+	var disks []*ghw.Disk
+	if block != nil {
+		disks = block.Disks
+	}
+
+	// Without a data directory, we can only tell which types of disks the system has
+	if dataDir == "" {
+		systemconfig.DiskType = diskTypes(disks)
+		return errs
+	}
+
+	mounts, err := os.ReadFile("/proc/mounts")
+	if err != nil {
+		return append(errs, fmt.Errorf("read mounts: %w", err))
+	}
+
+	path, err := filepath.EvalSymlinks(dataDir)
+	if err != nil {
+		return append(errs, fmt.Errorf("resolve data directory: %w", err))
+	}
+
+	mount, ok := findMount(mounts, path)
+	if !ok {
+		return append(errs, fmt.Errorf("find mount of data directory %q", path))
+	}
+
+	systemconfig.DataDirFilesystem = nonEmpty(mount.fsType)
+	systemconfig.DataDirMountOptions = nonEmpty(mount.options)
+
+	// Find the disk that holds the data directory. Device mapper devices, e.g. LVM or LUKS, can't be resolved that
+	// easily, so we fall back to the types of all disks.
+	if disk := findDisk(disks, filepath.Base(mount.device)); disk != nil {
+		systemconfig.DiskType = diskTypes([]*ghw.Disk{disk})
+	} else {
+		systemconfig.DiskType = diskTypes(disks)
+	}
+
+	return errs
+}
+
+// nonEmpty returns a pointer to the trimmed string, or nil if it's empty.
+func nonEmpty(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// selectedOption returns the selected option of a sysfs setting like "always [madvise] never".
+func selectedOption(setting string) string {
+	start := strings.Index(setting, "[")
+	end := strings.Index(setting, "]")
+	if start < 0 || end < start {
+		return strings.TrimSpace(setting)
+	}
+	return setting[start+1 : end]
+}
+
+// mountEntry is a single entry of /proc/mounts.
+type mountEntry struct {
+	device     string
+	mountPoint string
+	fsType     string
+	options    string
+}
+
+// findMount returns the entry of /proc/mounts that the given absolute path resides on, i.e. the one with the longest
+// matching mount point. Later entries win on ties, since they're stacked on top of earlier ones.
+func findMount(mounts []byte, path string) (mountEntry, bool) {
+	var (
+		found mountEntry
+		ok    bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(mounts))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+
+		entry := mountEntry{
+			device:     fields[0],
+			mountPoint: unescapeMountField(fields[1]),
+			fsType:     fields[2],
+			options:    fields[3],
+		}
+
+		if !isSubPath(entry.mountPoint, path) {
+			continue
+		}
+		if !ok || len(entry.mountPoint) >= len(found.mountPoint) {
+			found, ok = entry, true
+		}
+	}
+
+	return found, ok
+}
+
+// unescapeMountField reverts the octal escaping of spaces, tabs, newlines and backslashes in /proc/mounts.
+func unescapeMountField(field string) string {
+	return strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`).Replace(field)
+}
+
+// isSubPath reports whether path is the given directory or resides below it.
+func isSubPath(dir, path string) bool {
+	if dir == "/" || dir == path {
+		return true
+	}
+	return strings.HasPrefix(path, dir+"/")
+}
+
+// findDisk returns the disk with the given name, or the disk that contains a partition with the given name.
+func findDisk(disks []*ghw.Disk, name string) *ghw.Disk {
+	for _, disk := range disks {
+		if disk.Name == name {
+			return disk
+		}
+		for _, partition := range disk.Partitions {
+			if partition.Name == name {
+				return disk
+			}
+		}
+	}
+
+	return nil
+}
+
+// diskType classifies a disk as "nvme", "ssd" or "hdd". Other disks, e.g. optical drives, yield an empty string.
+func diskType(disk *ghw.Disk) string {
+	switch {
+	case disk.StorageController == ghw.STORAGE_CONTROLLER_NVME:
+		return "nvme"
+	case disk.DriveType == ghw.DRIVE_TYPE_SSD:
+		return "ssd"
+	case disk.DriveType == ghw.DRIVE_TYPE_HDD:
+		return "hdd"
+	default:
+		return ""
+	}
+}
+
+// diskTypes returns the distinct types of the given disks as a sorted, comma separated list. It returns nil if none of
+// the disks could be classified.
+func diskTypes(disks []*ghw.Disk) *string {
+	var types []string
+	for _, disk := range disks {
+		typ := diskType(disk)
+		if typ != "" && !slices.Contains(types, typ) {
+			types = append(types, typ)
+		}
+	}
+
+	if len(types) == 0 {
+		return nil
+	}
+
+	slices.Sort(types)
+
+	return pointer.To(strings.Join(types, ","))
+}
+
+func setCgroupConfig(cgroup *Cgroup, systemconfig *models.SystemConfig) {
+	if !cgroup.HasLimits() {
+		return
+	}
+
+	systemconfig.CgroupVersion = pointer.To(uint8(cgroup.Version))
+	if cgroup.CPUQuota > 0 {
+		systemconfig.CgroupCPUQuota = pointer.To(cgroup.CPUQuota)
+	}
+	if cgroup.MemoryLimit > 0 {
+		systemconfig.CgroupMemoryLimit = pointer.To(cgroup.MemoryLimit)
+	}
+	if len(cgroup.IOLimits) > 0 {
+		systemconfig.CgroupIoLimits = pointer.To(strings.Join(cgroup.IOLimits, ", "))
+	}
+}
+
 func setOSConfig(systemconfig *models.SystemConfig) {
 	systemconfig.OsName = pointer.To(runtime.GOOS)
 	systemconfig.OsArch = pointer.To(runtime.GOARCH)
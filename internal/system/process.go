@@ -0,0 +1,265 @@
+package system
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+	"golang.org/x/exp/slices"
+
+	"github.com/nikoksr/dbench/internal/models"
+)
+
+// ErrPostgresNotFound is returned by FindPostgresPID if no PostgreSQL server process could be found on this machine.
+var ErrPostgresNotFound = errors.New("postgres server process not found")
+
+// ErrAmbiguousPostgres is returned by FindPostgresPID if several PostgreSQL servers run on this machine and the one to
+// monitor can't be told by its process name.
+var ErrAmbiguousPostgres = errors.New("several postgres server processes found; set the data directory of the server to monitor")
+
+// FindPostgresPID returns the PID of the PostgreSQL server (postmaster) process. If a data directory is given, the PID
+// is read from its postmaster.pid file. Otherwise, the process with the given name whose parent has a different name is
+// considered the postmaster. If there are several such processes, ErrAmbiguousPostgres is returned.
+func FindPostgresPID(ctx context.Context, dataDir, processName string) (int32, error) {
+	if dataDir != "" {
+		return readPostmasterPID(dataDir)
+	}
+
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list processes: %w", err)
+	}
+
+	names := make(map[int32]string, len(procs))
+	parents := make(map[int32]int32, len(procs))
+	for _, proc := range procs {
+		name, err := proc.NameWithContext(ctx)
+		if err != nil {
+			continue // Process might have exited in the meantime
+		}
+		ppid, err := proc.PpidWithContext(ctx)
+		if err != nil {
+			continue
+		}
+
+		names[proc.Pid] = name
+		parents[proc.Pid] = ppid
+	}
+
+	postmasters := findPostmasters(names, parents, processName)
+	switch len(postmasters) {
+	case 0:
+		return 0, ErrPostgresNotFound
+	case 1:
+		return postmasters[0], nil
+	default:
+		return 0, fmt.Errorf("%w (PIDs %s)", ErrAmbiguousPostgres, joinPIDs(postmasters))
+	}
+}
+
+// findPostmasters returns the PIDs of the processes with the given name whose parent has a different name, in
+// ascending order. The server's child processes share its name, so they're left out.
+func findPostmasters(names map[int32]string, parents map[int32]int32, processName string) []int32 {
+	var pids []int32
+	for pid, name := range names {
+		if name != processName {
+			continue
+		}
+		if names[parents[pid]] != processName {
+			pids = append(pids, pid)
+		}
+	}
+
+	slices.Sort(pids)
+
+	return pids
+}
+
+func joinPIDs(pids []int32) string {
+	parts := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		parts = append(parts, strconv.Itoa(int(pid)))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// readPostmasterPID reads the PID of the postmaster from the postmaster.pid file in the given data directory.
+func readPostmasterPID(dataDir string) (int32, error) {
+	file, err := os.Open(filepath.Join(dataDir, "postmaster.pid"))
+	if err != nil {
+		return 0, fmt.Errorf("open postmaster.pid: %w", err)
+	}
+	defer file.Close()
+
+	// The first line of the file holds the PID
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return 0, fmt.Errorf("read postmaster.pid: %w", err)
+		}
+		return 0, fmt.Errorf("read postmaster.pid: file is empty")
+	}
+
+	pid, err := strconv.ParseInt(strings.TrimSpace(scanner.Text()), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("parse postmaster.pid: %w", err)
+	}
+
+	return int32(pid), nil
+}
+
+// processCounters holds the cumulative counters of a single process.
+type processCounters struct {
+	cpuTime                float64 // In seconds
+	readBytes, writeBytes  uint64
+	voluntaryCtxSwitches   uint64
+	involuntaryCtxSwitches uint64
+}
+
+// ProcessTracker tracks the counters of a process tree between two samples.
+type ProcessTracker struct {
+	role      string
+	rootPID   int32
+	counters  map[int32]processCounters
+	sampledAt time.Time
+}
+
+// NewProcessTracker returns a tracker for the process tree of the given root process. It takes a baseline, so that the
+// first sample only covers the time since then.
+func NewProcessTracker(ctx context.Context, role string, rootPID int32) *ProcessTracker {
+	tracker := &ProcessTracker{
+		role:    role,
+		rootPID: rootPID,
+	}
+
+	// Take a baseline, so that the first sample only contains what happened since the tracker was created
+	tracker.counters, _ = readProcessTree(ctx, rootPID)
+	tracker.sampledAt = time.Now()
+
+	return tracker
+}
+
+// readProcessTree reads the counters of the process with the given PID and all of its descendants. It also returns
+// whether the IO counters could be read for all processes; they usually require elevated permissions for processes
+// of other users.
+func readProcessTree(ctx context.Context, rootPID int32) (map[int32]processCounters, bool) {
+	pids := []int32{rootPID}
+
+	// Find all descendants of the root process
+	procs, err := process.ProcessesWithContext(ctx)
+	if err == nil {
+		children := make(map[int32][]int32, len(procs))
+		for _, proc := range procs {
+			ppid, err := proc.PpidWithContext(ctx)
+			if err != nil {
+				continue // Process might have exited in the meantime
+			}
+			children[ppid] = append(children[ppid], proc.Pid)
+		}
+
+		for idx := 0; idx < len(pids); idx++ {
+			pids = append(pids, children[pids[idx]]...)
+		}
+	}
+
+	counters := make(map[int32]processCounters, len(pids))
+	ioAvailable := true
+
+	for _, pid := range pids {
+		proc, err := process.NewProcessWithContext(ctx, pid)
+		if err != nil {
+			continue
+		}
+
+		var c processCounters
+
+		times, err := proc.TimesWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		c.cpuTime = times.User + times.System
+
+		if io, err := proc.IOCountersWithContext(ctx); err == nil {
+			c.readBytes = io.ReadBytes
+			c.writeBytes = io.WriteBytes
+		} else {
+			ioAvailable = false
+		}
+
+		if ctxSwitches, err := proc.NumCtxSwitchesWithContext(ctx); err == nil {
+			c.voluntaryCtxSwitches = uint64(ctxSwitches.Voluntary)
+			c.involuntaryCtxSwitches = uint64(ctxSwitches.Involuntary)
+		}
+
+		counters[pid] = c
+	}
+
+	return counters, ioAvailable
+}
+
+// delta returns the difference between two counter values. Counters of a recycled PID might be lower than the previous
+// ones, in which case we count the current value.
+func delta[T float64 | uint64](current, previous T) T {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
+
+// Sample reads the current counters of the process tree and returns the differences to the previous sample. It fails if
+// the root process is gone.
+func (t *ProcessTracker) Sample(ctx context.Context) (models.ProcessSample, error) {
+	counters, ioAvailable := readProcessTree(ctx, t.rootPID)
+	if len(counters) == 0 {
+		return models.ProcessSample{}, fmt.Errorf("process %d is not running", t.rootPID)
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(t.sampledAt).Seconds()
+
+	sample := models.ProcessSample{
+		Role:         t.role,
+		ProcessCount: len(counters),
+		IOAvailable:  ioAvailable,
+	}
+
+	var cpuTime float64
+	for pid, current := range counters {
+		// Processes that were started after the previous sample count with their full counters
+		previous := t.counters[pid]
+
+		cpuTime += delta(current.cpuTime, previous.cpuTime)
+		sample.ReadBytes += delta(current.readBytes, previous.readBytes)
+		sample.WriteBytes += delta(current.writeBytes, previous.writeBytes)
+		sample.VoluntaryCtxSwitches += delta(current.voluntaryCtxSwitches, previous.voluntaryCtxSwitches)
+		sample.InvoluntaryCtxSwitches += delta(current.involuntaryCtxSwitches, previous.involuntaryCtxSwitches)
+	}
+
+	if elapsed > 0 {
+		sample.CPULoad = cpuTime / elapsed * 100
+	}
+
+	// Resident memory is not cumulative, so we read it separately
+	for pid := range counters {
+		proc, err := process.NewProcessWithContext(ctx, pid)
+		if err != nil {
+			continue
+		}
+		if mem, err := proc.MemoryInfoWithContext(ctx); err == nil {
+			sample.RSS += mem.RSS
+		}
+	}
+
+	t.counters = counters
+	t.sampledAt = now
+
+	return sample, nil
+}
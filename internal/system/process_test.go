@@ -0,0 +1,33 @@
+package system
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindPostmasters(t *testing.T) {
+	t.Parallel()
+
+	// Two clusters, each with a postmaster and a child process, plus an unrelated process
+	names := map[int32]string{
+		1:   "systemd",
+		900: "postgres",
+		901: "postgres",
+		300: "postgres",
+		301: "postgres",
+		500: "bash",
+	}
+	parents := map[int32]int32{
+		1:   0,
+		900: 1,
+		901: 900,
+		300: 1,
+		301: 300,
+		500: 1,
+	}
+
+	assert.Equal(t, []int32{300, 900}, findPostmasters(names, parents, "postgres"))
+	assert.Equal(t, []int32{500}, findPostmasters(names, parents, "bash"))
+	assert.Empty(t, findPostmasters(names, parents, "mysqld"))
+}
@@ -2,35 +2,125 @@ package system
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/shirou/gopsutil/v3/net"
 
 	"github.com/nikoksr/dbench/internal/models"
 )
 
-// CollectMetrics monitors the system and sends samples to the sample channel.
-func CollectMetrics(interval time.Duration, stopChan <-chan struct{}, sampleChan chan<- models.SystemSample) error {
-	for {
-		select {
-		case <-stopChan:
-			close(sampleChan)
-			return nil
-		case <-time.After(interval):
-			// Get system metrics
-			sample, err := getMetrics()
-			if err != nil {
-				return fmt.Errorf("get system metrics: %w", err)
-			}
-
-			// Send sample to the channel
-			sampleChan <- sample
+// Sampler samples the metrics of the whole system. It holds the cumulative counters of the previous sample; most IO
+// related counters are cumulative since boot, so we need them to calculate rates.
+//
+// When running in a cgroup with a CPU quota or memory limit, e.g. inside a container, the CPU and memory load are
+// relative to these limits instead of the host's totals.
+type Sampler struct {
+	sampledAt      time.Time
+	cpuTimes       cpu.TimesStat
+	disks          map[string]disk.IOCountersStat
+	networks       map[string]net.IOCountersStat
+	cgroup         *Cgroup
+	cgroupCPUUsage time.Duration
+}
+
+// NewSampler returns a new Sampler. It takes a baseline, so that the first sample only covers the time since then.
+func NewSampler() *Sampler {
+	s := new(Sampler)
+
+	// Outside of Linux or without access to the cgroup filesystem, we fall back to the host's totals
+	if cgroup, err := DetectCgroup(); err == nil && cgroup.HasLimits() {
+		s.cgroup = cgroup
+	}
+
+	// Take a baseline. Errors are ignored here, they'll surface on the first sample.
+	s.sampledAt = time.Now()
+	if times, err := cpu.Times(false); err == nil && len(times) > 0 {
+		s.cpuTimes = times[0]
+	}
+	if s.cgroup != nil {
+		s.cgroupCPUUsage, _ = s.cgroup.CPUUsage()
+	}
+	s.disks, _ = disk.IOCounters()
+	s.networks = getNetworkCounters()
+
+	return s
+}
+
+func getNetworkCounters() map[string]net.IOCountersStat {
+	counters, err := net.IOCounters(true)
+	if err != nil {
+		return nil
+	}
+
+	networks := make(map[string]net.IOCountersStat, len(counters))
+	for _, counter := range counters {
+		networks[counter.Name] = counter
+	}
+
+	return networks
+}
+
+// isPhysicalDisk reports whether the given block device is worth sampling. Loop and RAM devices are skipped, just like
+// partitions, which would otherwise count the IO of their disk twice.
+func isPhysicalDisk(name string) bool {
+	for _, prefix := range []string{"loop", "ram", "zram", "dm-"} {
+		if strings.HasPrefix(name, prefix) {
+			return false
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join("/sys/class/block", name, "partition")); err == nil {
+		return false
+	}
+
+	return true
+}
+
+// rate returns the per-second rate of a cumulative counter. Counters that wrapped or got reset yield zero.
+func rate(current, previous uint64, elapsed float64) float64 {
+	if current < previous || elapsed <= 0 {
+		return 0
+	}
+	return float64(current-previous) / elapsed
+}
+
+// sampleCgroup replaces the CPU and memory load of the sample with the load relative to the limits of the cgroup.
+func (s *Sampler) sampleCgroup(sample *models.SystemSample, elapsed float64) error {
+	if s.cgroup == nil {
+		return nil
+	}
+
+	if s.cgroup.CPUQuota > 0 {
+		usage, err := s.cgroup.CPUUsage()
+		if err != nil {
+			return fmt.Errorf("get cgroup cpu usage: %w", err)
+		}
+		if elapsed > 0 && usage >= s.cgroupCPUUsage {
+			sample.CPULoad = min(100, (usage-s.cgroupCPUUsage).Seconds()/elapsed/s.cgroup.CPUQuota*100)
+		}
+		s.cgroupCPUUsage = usage
+	}
+
+	if s.cgroup.MemoryLimit > 0 {
+		usage, err := s.cgroup.MemoryUsage()
+		if err != nil {
+			return fmt.Errorf("get cgroup memory usage: %w", err)
 		}
+		sample.MemoryLoad = min(100, float64(usage)/float64(s.cgroup.MemoryLimit)*100)
 	}
+
+	return nil
 }
 
-func getMetrics() (models.SystemSample, error) {
+// Sample takes a sample of the system metrics. Rates cover the time since the previous sample.
+func (s *Sampler) Sample() (models.SystemSample, error) {
 	cpuPercent, err := cpu.Percent(0, false)
 	if err != nil {
 		return models.SystemSample{}, fmt.Errorf("get cpu usage: %w", err)
@@ -41,8 +131,88 @@ func getMetrics() (models.SystemSample, error) {
 		return models.SystemSample{}, fmt.Errorf("get memory usage: %w", err)
 	}
 
-	return models.SystemSample{
+	now := time.Now()
+
+	sample := models.SystemSample{
+		SampledAt:  now,
 		CPULoad:    cpuPercent[0],
 		MemoryLoad: memUsage.UsedPercent,
-	}, nil
+	}
+
+	elapsed := now.Sub(s.sampledAt).Seconds()
+
+	if err := s.sampleCgroup(&sample, elapsed); err != nil {
+		return models.SystemSample{}, err
+	}
+
+	// Load average
+	avg, err := load.Avg()
+	if err != nil {
+		return models.SystemSample{}, fmt.Errorf("get load average: %w", err)
+	}
+	sample.LoadAverage = avg.Load1
+
+	// Swap usage
+	swap, err := mem.SwapMemory()
+	if err != nil {
+		return models.SystemSample{}, fmt.Errorf("get swap usage: %w", err)
+	}
+	sample.SwapLoad = swap.UsedPercent
+
+	// IO wait, as share of the CPU time since the previous sample
+	times, err := cpu.Times(false)
+	if err != nil {
+		return models.SystemSample{}, fmt.Errorf("get cpu times: %w", err)
+	}
+	if len(times) > 0 {
+		if total := times[0].Total() - s.cpuTimes.Total(); total > 0 {
+			sample.IOWait = max(0, (times[0].Iowait-s.cpuTimes.Iowait)/total*100)
+		}
+		s.cpuTimes = times[0]
+	}
+
+	// Disk IO
+	disks, err := disk.IOCounters()
+	if err != nil {
+		return models.SystemSample{}, fmt.Errorf("get disk io counters: %w", err)
+	}
+	for name, current := range disks {
+		previous, ok := s.disks[name]
+		if !ok || !isPhysicalDisk(name) {
+			continue
+		}
+
+		sample.Disks = append(sample.Disks, models.DiskSample{
+			Device:              name,
+			ReadBytesPerSecond:  rate(current.ReadBytes, previous.ReadBytes, elapsed),
+			WriteBytesPerSecond: rate(current.WriteBytes, previous.WriteBytes, elapsed),
+			ReadIOPS:            rate(current.ReadCount, previous.ReadCount, elapsed),
+			WriteIOPS:           rate(current.WriteCount, previous.WriteCount, elapsed),
+			// IoTime is the time in milliseconds the device was busy
+			Utilization: min(100, rate(current.IoTime, previous.IoTime, elapsed)/1000*100),
+		})
+	}
+	s.disks = disks
+
+	// Network IO
+	networks := getNetworkCounters()
+	for name, current := range networks {
+		previous, ok := s.networks[name]
+		if !ok {
+			continue
+		}
+
+		sample.Networks = append(sample.Networks, models.NetworkSample{
+			Interface:                name,
+			BytesReceivedPerSecond:   rate(current.BytesRecv, previous.BytesRecv, elapsed),
+			BytesSentPerSecond:       rate(current.BytesSent, previous.BytesSent, elapsed),
+			PacketsReceivedPerSecond: rate(current.PacketsRecv, previous.PacketsRecv, elapsed),
+			PacketsSentPerSecond:     rate(current.PacketsSent, previous.PacketsSent, elapsed),
+		})
+	}
+	s.networks = networks
+
+	s.sampledAt = now
+
+	return sample, nil
 }
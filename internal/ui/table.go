@@ -48,6 +48,7 @@ func (r *BenchmarksTableRenderer) Render(benchmarks []*models.Benchmark) string
 		"Avg. Latency",
 		"Conn Time",
 		"Recorded At",
+		"Tags",
 	})
 	for _, benchmark := range benchmarks {
 		var machineID *string
@@ -67,6 +68,7 @@ func (r *BenchmarksTableRenderer) Render(benchmarks []*models.Benchmark) string
 			benchmark.Edges.Result.AverageLatency,
 			benchmark.Edges.Result.ConnectionTime,
 			benchmark.RecordedAt.Local().Format("2006-01-02 15:04:05"),
+			text.StringOrNA(models.FormatTags(benchmark.Edges.Tags)),
 		})
 	}
 
@@ -78,6 +80,7 @@ func (r *BenchmarksTableRenderer) Render(benchmarks []*models.Benchmark) string
 
 	t.SetColumnConfigs([]table.ColumnConfig{
 		{Name: "Comment", WidthMax: 30},
+		{Name: "Tags", WidthMax: 30},
 		{Name: "TPS", Align: prettytext.AlignRight},
 	})
 
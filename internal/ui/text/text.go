@@ -21,6 +21,15 @@ func ValueOrNA[T any](v *T) string {
 	return fmt.Sprintf("%v", *v)
 }
 
+// StringOrNA returns the given string or "N/A" if it's empty.
+func StringOrNA(s string) string {
+	if s == "" {
+		return naChar
+	}
+
+	return s
+}
+
 // HumanizeBytes returns a human-readable string for the given bytes. If the given pointer is nil, "N/A" is returned.
 func HumanizeBytes(bytes *uint64) string {
 	if bytes == nil {
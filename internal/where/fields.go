@@ -0,0 +1,137 @@
+package where
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kind is the type of the values of a field.
+type Kind int
+
+const (
+	KindString   Kind = iota // KindString is a text field
+	KindInt                  // KindInt is an integer field
+	KindFloat                // KindFloat is a floating point field
+	KindTime                 // KindTime is a timestamp field, given as date or date and time
+	KindDuration             // KindDuration is a duration field, given like 1.5ms or 2s
+)
+
+// String returns the name of the kind as shown in errors and help texts.
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "text"
+	case KindInt:
+		return "integer"
+	case KindFloat:
+		return "number"
+	case KindTime:
+		return "time"
+	case KindDuration:
+		return "duration"
+	default:
+		return "unknown"
+	}
+}
+
+// Entity is the entity that a field belongs to.
+type Entity int
+
+const (
+	EntityBenchmark       Entity = iota // EntityBenchmark is the benchmark itself
+	EntityBenchmarkResult               // EntityBenchmarkResult is the result of a benchmark
+	EntitySystemMetric                  // EntitySystemMetric is the aggregated system metric of a benchmark
+	EntitySystemConfig                  // EntitySystemConfig is the system config of a benchmark
+)
+
+// Field is a field that can be filtered by.
+type Field struct {
+	Name   string // Name of the field in expressions
+	Column string // Column of the field in the table of its entity
+	Entity Entity
+	Kind   Kind
+}
+
+var fields = buildFields()
+
+func buildFields() map[string]Field {
+	all := []Field{
+		// Benchmark
+		{Name: "id", Column: "id", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "group_id", Column: "group_id", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "comment", Column: "comment", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "version", Column: "version", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "transaction_type", Column: "transaction_type", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "scaling_factor", Column: "scaling_factor", Entity: EntityBenchmark, Kind: KindFloat},
+		{Name: "query_mode", Column: "query_mode", Entity: EntityBenchmark, Kind: KindString},
+		{Name: "clients", Column: "clients", Entity: EntityBenchmark, Kind: KindInt},
+		{Name: "threads", Column: "threads", Entity: EntityBenchmark, Kind: KindInt},
+		{Name: "recorded_at", Column: "recorded_at", Entity: EntityBenchmark, Kind: KindTime},
+		// Benchmark result
+		{Name: "transactions", Column: "transactions", Entity: EntityBenchmarkResult, Kind: KindInt},
+		{Name: "failed_transactions", Column: "failed_transactions", Entity: EntityBenchmarkResult, Kind: KindInt},
+		{Name: "tps", Column: "transactions_per_second", Entity: EntityBenchmarkResult, Kind: KindFloat},
+		{Name: "transactions_per_second", Column: "transactions_per_second", Entity: EntityBenchmarkResult, Kind: KindFloat},
+		{Name: "latency", Column: "average_latency", Entity: EntityBenchmarkResult, Kind: KindDuration},
+		{Name: "average_latency", Column: "average_latency", Entity: EntityBenchmarkResult, Kind: KindDuration},
+		{Name: "connection_time", Column: "connection_time", Entity: EntityBenchmarkResult, Kind: KindDuration},
+		{Name: "total_runtime", Column: "total_runtime", Entity: EntityBenchmarkResult, Kind: KindDuration},
+		// System config
+		{Name: "machine_id", Column: "machine_id", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "os_name", Column: "os_name", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "os_arch", Column: "os_arch", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "cpu_vendor", Column: "cpu_vendor", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "cpu_model", Column: "cpu_model", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "cpu_count", Column: "cpu_count", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "cpu_cores", Column: "cpu_cores", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "cpu_threads", Column: "cpu_threads", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "ram_physical", Column: "ram_physical", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "ram_usable", Column: "ram_usable", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "disk_count", Column: "disk_count", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "disk_space_total", Column: "disk_space_total", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "disk_type", Column: "disk_type", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "kernel_version", Column: "kernel_version", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "cpu_governor", Column: "cpu_governor", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "numa_nodes", Column: "numa_nodes", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "transparent_huge_pages", Column: "transparent_huge_pages", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "data_dir_filesystem", Column: "data_dir_filesystem", Entity: EntitySystemConfig, Kind: KindString},
+		{Name: "cgroup_version", Column: "cgroup_version", Entity: EntitySystemConfig, Kind: KindInt},
+		{Name: "cgroup_cpu_quota", Column: "cgroup_cpu_quota", Entity: EntitySystemConfig, Kind: KindFloat},
+		{Name: "cgroup_memory_limit", Column: "cgroup_memory_limit", Entity: EntitySystemConfig, Kind: KindInt},
+	}
+
+	// System metrics share the same set of statistics
+	for _, stat := range []string{"min", "max", "average", "50th", "75th", "90th", "95th", "99th"} {
+		for _, metric := range []string{"cpu", "memory", "iowait", "swap"} {
+			name := metric + "_" + stat + "_load"
+			all = append(all, Field{Name: name, Column: name, Entity: EntitySystemMetric, Kind: KindFloat})
+		}
+
+		name := "load_average_" + stat
+		all = append(all, Field{Name: name, Column: name, Entity: EntitySystemMetric, Kind: KindFloat})
+	}
+
+	byName := make(map[string]Field, len(all))
+	for _, field := range all {
+		byName[field.Name] = field
+	}
+
+	return byName
+}
+
+// LookupField returns the field with the given name.
+func LookupField(name string) (Field, bool) {
+	field, ok := fields[strings.ToLower(name)]
+	return field, ok
+}
+
+// FieldNames returns the sorted names of all fields.
+func FieldNames() []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,315 @@
+// Package where parses filter expressions like "clients>=32 and tps>1000" that select benchmarks by the fields of
+// their result, system metric and system config.
+package where
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	OpEQ  Op = "="  // OpEQ matches equal values
+	OpNEQ Op = "!=" // OpNEQ matches unequal values
+	OpLT  Op = "<"  // OpLT matches values less than the given one
+	OpLTE Op = "<=" // OpLTE matches values less than or equal to the given one
+	OpGT  Op = ">"  // OpGT matches values greater than the given one
+	OpGTE Op = ">=" // OpGTE matches values greater than or equal to the given one
+)
+
+// Node is a node of a parsed expression. It's one of *And, *Or or *Condition.
+type Node interface {
+	node()
+}
+
+// And matches if all of its nodes match.
+type And struct {
+	Nodes []Node
+}
+
+// Or matches if any of its nodes matches.
+type Or struct {
+	Nodes []Node
+}
+
+// Condition compares a field with a value. The type of the value depends on the kind of the field: string, int64,
+// float64, time.Time or time.Duration.
+type Condition struct {
+	Field Field
+	Op    Op
+	Value any
+}
+
+func (*And) node()       {}
+func (*Or) node()        {}
+func (*Condition) node() {}
+
+// Parse parses the given expression. Conditions are combined with "and" and "or", where "and" binds stronger, and can
+// be grouped with parentheses.
+func Parse(expr string) (Node, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty expression")
+	}
+
+	p := &parser{tokens: tokens}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+
+	return node, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune("()=!<>\"'", r)
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case strings.ContainsRune("=!<>", r):
+			start := i
+			for i < len(runes) && strings.ContainsRune("=!<>", runes[i]) {
+				i++
+			}
+
+			op := string(runes[start:i])
+			switch Op(op) {
+			case OpEQ, OpNEQ, OpLT, OpLTE, OpGT, OpGTE:
+			default:
+				return nil, fmt.Errorf("invalid operator %q at position %d", op, start+1)
+			}
+
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+		case r == '"' || r == '\'':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != r {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string at position %d", start+1)
+			}
+
+			tokens = append(tokens, token{kind: tokenString, text: string(runes[start+1 : i]), pos: start})
+			i++
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		}
+	}
+
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	if p.pos >= len(p.tokens) {
+		return token{kind: tokenEOF, text: "end of expression", pos: p.endPos()}
+	}
+
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+func (p *parser) endPos() int {
+	if len(p.tokens) == 0 {
+		return 0
+	}
+
+	last := p.tokens[len(p.tokens)-1]
+	return last.pos + len(last.text)
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) parseOr() (Node, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+	for p.isKeyword("or") {
+		p.next()
+
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+
+	return &Or{Nodes: nodes}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	node, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := []Node{node}
+	for p.isKeyword("and") {
+		p.next()
+
+		node, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+
+	return &And{Nodes: nodes}, nil
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	if p.peek().kind == tokenLParen {
+		p.next()
+
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, fmt.Errorf("expected \")\" at position %d, got %q", tok.pos+1, tok.text)
+		}
+
+		return node, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parser) parseCondition() (Node, error) {
+	nameTok := p.next()
+	if nameTok.kind != tokenWord {
+		return nil, fmt.Errorf("expected a field at position %d, got %q", nameTok.pos+1, nameTok.text)
+	}
+
+	field, ok := LookupField(nameTok.text)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q at position %d, known fields are: %s", nameTok.text, nameTok.pos+1, strings.Join(FieldNames(), ", "))
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokenOp {
+		return nil, fmt.Errorf("expected an operator after %q at position %d, got %q", field.Name, opTok.pos+1, opTok.text)
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+		return nil, fmt.Errorf("expected a value for %q at position %d, got %q", field.Name, valueTok.pos+1, valueTok.text)
+	}
+
+	value, err := parseValue(field.Kind, valueTok.text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q for %s field %q at position %d: %w", valueTok.text, field.Kind, field.Name, valueTok.pos+1, err)
+	}
+
+	return &Condition{Field: field, Op: Op(opTok.text), Value: value}, nil
+}
+
+// timeLayouts are the accepted layouts of time values. Times without a zone are taken as local time.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case KindString:
+		return raw, nil
+	case KindInt:
+		return strconv.ParseInt(raw, 10, 64)
+	case KindFloat:
+		return strconv.ParseFloat(raw, 64)
+	case KindDuration:
+		return time.ParseDuration(raw)
+	case KindTime:
+		for _, layout := range timeLayouts {
+			if t, err := time.ParseInLocation(layout, raw, time.Local); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return nil, fmt.Errorf("expected a date like 2024-01-31 or a time like 2024-01-31T15:04:05")
+	default:
+		return nil, fmt.Errorf("unsupported kind %s", kind)
+	}
+}
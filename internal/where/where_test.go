@@ -0,0 +1,106 @@
+package where
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	node, err := Parse("clients>=32 and tps>1000.5 AND machine_id=abc and recorded_at>2024-01-01 and latency<2ms")
+	assert.NoError(t, err)
+
+	and, ok := node.(*And)
+	if !assert.True(t, ok, "expected an and node") {
+		return
+	}
+	if !assert.Len(t, and.Nodes, 5) {
+		return
+	}
+
+	expected := []struct {
+		column string
+		entity Entity
+		op     Op
+		value  any
+	}{
+		{column: "clients", entity: EntityBenchmark, op: OpGTE, value: int64(32)},
+		{column: "transactions_per_second", entity: EntityBenchmarkResult, op: OpGT, value: 1000.5},
+		{column: "machine_id", entity: EntitySystemConfig, op: OpEQ, value: "abc"},
+		{column: "recorded_at", entity: EntityBenchmark, op: OpGT, value: time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local).UTC()},
+		{column: "average_latency", entity: EntityBenchmarkResult, op: OpLT, value: 2 * time.Millisecond},
+	}
+
+	for i, want := range expected {
+		cond, ok := and.Nodes[i].(*Condition)
+		if !assert.True(t, ok, "expected a condition at index %d", i) {
+			continue
+		}
+
+		assert.Equal(t, want.column, cond.Field.Column)
+		assert.Equal(t, want.entity, cond.Field.Entity)
+		assert.Equal(t, want.op, cond.Op)
+		assert.Equal(t, want.value, cond.Value)
+	}
+}
+
+func TestParsePrecedence(t *testing.T) {
+	t.Parallel()
+
+	node, err := Parse("clients=1 or clients=2 and (threads=1 or os_name='Fedora Linux')")
+	assert.NoError(t, err)
+
+	or, ok := node.(*Or)
+	if !assert.True(t, ok, "expected an or node") {
+		return
+	}
+	if !assert.Len(t, or.Nodes, 2) {
+		return
+	}
+
+	and, ok := or.Nodes[1].(*And)
+	if !assert.True(t, ok, "expected the second node to be an and node") {
+		return
+	}
+	if !assert.Len(t, and.Nodes, 2) {
+		return
+	}
+
+	inner, ok := and.Nodes[1].(*Or)
+	if !assert.True(t, ok, "expected the parenthesized node to be an or node") {
+		return
+	}
+	if assert.Len(t, inner.Nodes, 2) {
+		assert.Equal(t, "Fedora Linux", inner.Nodes[1].(*Condition).Value)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr string
+		err  string
+	}{
+		{expr: "", err: "empty expression"},
+		{expr: "foo=1", err: `unknown field "foo" at position 1`},
+		{expr: "clients>abc", err: `invalid value "abc" for integer field "clients" at position 9`},
+		{expr: "clients 32", err: `expected an operator after "clients"`},
+		{expr: "clients=>32", err: `invalid operator "=>"`},
+		{expr: "clients=32 and", err: "expected a field at position 15"},
+		{expr: "(clients=32", err: `expected ")"`},
+		{expr: "clients=32 threads=1", err: `unexpected "threads" at position 12`},
+		{expr: "os_name='linux", err: "unterminated string at position 9"},
+		{expr: "recorded_at>yesterday", err: "expected a date"},
+	}
+
+	for _, tc := range tests {
+		_, err := Parse(tc.expr)
+		if assert.Error(t, err, tc.expr) {
+			assert.Contains(t, err.Error(), tc.err, tc.expr)
+		}
+	}
+}